@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var consoleCmd = &cobra.Command{
+	Use:   "console",
+	Short: "Open the AWS Management Console for the current session",
+	Long:  `Sign in to the AWS Management Console with this terminal's account and role using the federation endpoint, and open it in the browser. Use --print to print the sign-in URL instead (valid for 15 minutes).`,
+	Run:   runConsole,
+}
+
+var consoleService string
+var consolePrint bool
+
+func init() {
+	rootCmd.AddCommand(consoleCmd)
+	consoleCmd.Flags().StringVar(&consoleService, "service", "", "Console service to open (e.g. rds, ec2, cloudwatch)")
+	consoleCmd.Flags().BoolVar(&consolePrint, "print", false, "Print the sign-in URL instead of opening a browser")
+}
+
+func runConsole(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// --print output is often captured, so only offer re-login when opening a browser
+	if !consolePrint {
+		if _, err := aws.CheckCredentialExpiry(ctx); err != nil {
+			fmt.Printf("Error during re-authentication: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	consoleManager, err := aws.NewConsoleManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			// Retry creating manager after successful login
+			consoleManager, err = aws.NewConsoleManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating console manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating console manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := consoleManager.RunConsole(ctx, consoleService, consolePrint); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
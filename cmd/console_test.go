@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestConsoleCommand(t *testing.T) {
+	if consoleCmd.Use != "console" {
+		t.Errorf("Expected Use 'console', got '%s'", consoleCmd.Use)
+	}
+
+	if consoleCmd.Short == "" {
+		t.Error("consoleCmd should have Short description")
+	}
+
+	if consoleCmd.Run == nil {
+		t.Error("consoleCmd should have Run function")
+	}
+
+	for _, name := range []string{"service", "print"} {
+		flag := consoleCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("--%s flag should be defined for console command", name)
+			continue
+		}
+		if flag.Usage == "" {
+			t.Errorf("--%s flag should have usage description", name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/spf13/cobra"
+)
+
+var credentialProcessCmd = &cobra.Command{
+	Use:   "credential-process",
+	Short: "Print credentials in the AWS credential_process format",
+	Long: `Print fresh role credentials as JSON for use as a credential_process in ~/.aws/config. Credentials are cached until shortly before they expire.
+
+With --chain, print the cached credentials of a role chain logged in with 'awsc login --chain'.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Never prompt for configuration - this command is run non-interactively by SDKs
+		debug.SetVerbose(verbose)
+	},
+	Run: runCredentialProcess,
+}
+
+var credentialProcessAccount string
+var credentialProcessRole string
+var credentialProcessChain string
+
+func init() {
+	rootCmd.AddCommand(credentialProcessCmd)
+	credentialProcessCmd.Flags().StringVar(&credentialProcessAccount, "account", "", "Account ID (or cached account name) to get credentials for")
+	credentialProcessCmd.Flags().StringVar(&credentialProcessRole, "role", "", "Role name to get credentials for")
+	credentialProcessCmd.Flags().StringVar(&credentialProcessChain, "chain", "", "Role chain to print cached credentials for")
+	credentialProcessCmd.MarkFlagsRequiredTogether("account", "role")
+	credentialProcessCmd.MarkFlagsOneRequired("account", "chain")
+	credentialProcessCmd.MarkFlagsMutuallyExclusive("account", "chain")
+	credentialProcessCmd.MarkFlagsMutuallyExclusive("role", "chain")
+}
+
+func runCredentialProcess(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	var output *aws.CredentialProcessOutput
+	if credentialProcessChain != "" {
+		var err error
+		output, err = aws.GetChainCredentialProcessOutput(credentialProcessChain)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		ssoManager, err := aws.NewSSOManager(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		accountID := config.GetAccountID(credentialProcessAccount)
+		output, err = ssoManager.GetCredentialProcessOutput(ctx, accountID, credentialProcessRole)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// stdout carries only the credential JSON
+	if err := json.NewEncoder(os.Stdout).Encode(output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
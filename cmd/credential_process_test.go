@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCredentialProcessCommand(t *testing.T) {
+	if credentialProcessCmd.Use != "credential-process" {
+		t.Errorf("Expected Use 'credential-process', got '%s'", credentialProcessCmd.Use)
+	}
+
+	if credentialProcessCmd.Short == "" {
+		t.Error("credentialProcessCmd should have Short description")
+	}
+
+	if credentialProcessCmd.Run == nil {
+		t.Error("credentialProcessCmd should have Run function")
+	}
+
+	// Must not inherit the interactive config setup from the root command
+	if credentialProcessCmd.PersistentPreRun == nil {
+		t.Error("credentialProcessCmd should override PersistentPreRun")
+	}
+}
+
+func TestCredentialProcessCommandFlags(t *testing.T) {
+	for _, name := range []string{"account", "role", "chain"} {
+		flag := credentialProcessCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("--%s flag should be defined for credential-process command", name)
+			continue
+		}
+		if flag.Usage == "" {
+			t.Errorf("--%s flag should have usage description", name)
+		}
+	}
+}
+
+func TestCredentialProcessFlagGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"account and role", []string{"--account", "123456789012", "--role", "Admin"}, false},
+		{"chain", []string{"--chain", "prod-admin"}, false},
+		{"no flags", nil, true},
+		{"account without role", []string{"--account", "123456789012"}, true},
+		{"role without account", []string{"--role", "Admin"}, true},
+		{"chain and account", []string{"--chain", "prod-admin", "--account", "123456789012", "--role", "Admin"}, true},
+		{"chain and role", []string{"--chain", "prod-admin", "--role", "Admin"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Parse into copies of the flags so the command's own flags stay untouched
+			cmd := &cobra.Command{Use: "credential-process"}
+			for _, name := range []string{"account", "role", "chain"} {
+				cmd.Flags().String(name, "", "")
+				cmd.Flags().Lookup(name).Annotations = credentialProcessCmd.Flags().Lookup(name).Annotations
+			}
+
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			err := cmd.ValidateFlagGroups()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFlagGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
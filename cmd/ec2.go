@@ -32,6 +32,7 @@ var ec2RdpCmd = &cobra.Command{
 var instanceId string
 var rdpLocalPort int32
 var ec2SwitchAccount bool
+var ec2Regions string
 
 func init() {
 	rootCmd.AddCommand(ec2Cmd)
@@ -46,11 +47,19 @@ func init() {
 	// Add switch-account flag to both commands
 	ec2ConnectCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
 	ec2RdpCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+
+	// Add regions flag to both commands
+	addRegionsFlag(ec2ConnectCmd, &ec2Regions)
+	addRegionsFlag(ec2RdpCmd, &ec2Regions)
 }
 
 func createEC2Manager() (*aws.EC2Manager, error) {
 	ctx := context.Background()
-	return aws.NewEC2Manager(ctx)
+	regions, err := resolveRegionsFlag(ctx, ec2Regions)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewEC2Manager(ctx, aws.EC2ManagerOptions{Regions: regions})
 }
 
 func runEC2Connect(cmd *cobra.Command, args []string) {
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print the current session's credentials as environment variables",
+	Long: `Print export statements for the credentials, region and profile of this terminal's session, for tools that can't read ~/.aws/config profiles.
+
+  eval "$(awsc env)"
+  awsc env --shell fish | source
+  awsc env --shell powershell | Invoke-Expression
+  awsc env --shell dotenv > .env`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// stdout is usually evaluated by a shell - never prompt for configuration
+		debug.SetVerbose(verbose)
+	},
+	Run: runEnv,
+}
+
+var envShell string
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().StringVar(&envShell, "shell", "", fmt.Sprintf("Output format (%s), detected from $SHELL by default", strings.Join(aws.EnvShells, ", ")))
+}
+
+func runEnv(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	shell := envShell
+	if shell == "" {
+		shell = aws.DetectShell()
+	}
+
+	creds, err := aws.GetSessionCredentials(ctx)
+	if err != nil {
+		if aws.IsAuthError(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v - run 'awsc login' first\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	output, err := aws.FormatEnv(creds, shell)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// stdout carries only the statements to evaluate
+	fmt.Print(output)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestEnvCommand(t *testing.T) {
+	if envCmd.Use != "env" {
+		t.Errorf("Expected Use 'env', got '%s'", envCmd.Use)
+	}
+
+	if envCmd.Run == nil {
+		t.Error("envCmd should have Run function")
+	}
+
+	// Output is evaluated by a shell, so it must not inherit the interactive config setup
+	if envCmd.PersistentPreRun == nil {
+		t.Error("envCmd should override PersistentPreRun")
+	}
+
+	if envCmd.Flags().Lookup("shell") == nil {
+		t.Error("--shell flag should be defined for env command")
+	}
+}
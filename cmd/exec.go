@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [--account name --role role] -- command [args...]",
+	Short: "Run a command with AWS credentials in its environment",
+	Long: `Run a command with the credentials, region and AWSC_PROFILE of this terminal's session in its environment. With --account or --role, the command gets that account and role instead, without changing this terminal's session.
+
+Signals are forwarded to the command and awsc exits with its exit code.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runExec,
+}
+
+var execAccount string
+var execRole string
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().StringVar(&execAccount, "account", "", "Account name, prefix or ID to run the command in")
+	execCmd.Flags().StringVar(&execRole, "role", "", "Role name or prefix to run the command with")
+	// Flags after the command belong to the command
+	execCmd.Flags().SetInterspersed(false)
+}
+
+func runExec(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Login prompts and messages go to stderr, so they don't mix with the command's stdout
+	// (e.g. awsc exec -- aws ... > out.json)
+	creds := execCredentials(ctx)
+
+	exitCode, err := aws.RunWithCredentials(ctx, creds, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(exitCode)
+}
+
+// execCredentials resolves the credentials for the command: those of --account/--role,
+// fetched without writing a profile, or this terminal's session
+func execCredentials(ctx context.Context) *aws.SessionCredentials {
+	if execAccount != "" || execRole != "" {
+		ssoManager, err := aws.NewSSOManager(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating SSO manager: %v\n", err)
+			os.Exit(1)
+		}
+
+		creds, err := ssoManager.CredentialsFor(ctx, execAccount, execRole)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return creds
+	}
+
+	if _, err := aws.CheckCredentialExpiry(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error during re-authentication: %v\n", err)
+		os.Exit(1)
+	}
+
+	creds, err := aws.GetSessionCredentials(ctx)
+	if err != nil {
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Fprintf(os.Stderr, "Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Fprintf(os.Stderr, "Authentication cancelled\n")
+				os.Exit(1)
+			}
+			// Retry after successful login
+			creds, err = aws.GetSessionCredentials(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting credentials after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Error getting credentials: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	return creds
+}
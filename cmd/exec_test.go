@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestExecCommand(t *testing.T) {
+	if execCmd.Short == "" {
+		t.Error("execCmd should have Short description")
+	}
+
+	if execCmd.Run == nil {
+		t.Error("execCmd should have Run function")
+	}
+
+	if err := execCmd.Args(execCmd, []string{}); err == nil {
+		t.Error("exec should require a command")
+	}
+
+	for _, name := range []string{"account", "role"} {
+		if execCmd.Flags().Lookup(name) == nil {
+			t.Errorf("--%s flag should be defined for exec command", name)
+		}
+	}
+}
+
+func TestExecCommandLeavesCommandFlags(t *testing.T) {
+	execCmd.Flags().Parse([]string{"--account", "dev", "aws", "--role", "x"})
+	defer func() { execAccount = "" }()
+
+	if execAccount != "dev" {
+		t.Errorf("Expected --account parsed, got %q", execAccount)
+	}
+	if execRole != "" {
+		t.Errorf("Flags after the command should be left to it, got --role %q", execRole)
+	}
+	if args := execCmd.Flags().Args(); len(args) != 3 || args[0] != "aws" {
+		t.Errorf("Expected command args [aws --role x], got %v", args)
+	}
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Sign out of AWS SSO and remove awsc profiles",
+	Long: `Revoke the AWS SSO token, delete the cached token and client registration, and remove
+the awsc profile and session for the current shell. Use --all to remove every awsc profile,
+cached credential and session file.`,
+	Run: runLogout,
+}
+
+var logoutAll bool
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Remove all awsc profiles and sessions, not just the current shell's")
+}
+
+func runLogout(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	ssoManager, err := aws.NewSSOManager(ctx)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := ssoManager.RunLogout(ctx, logoutAll); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
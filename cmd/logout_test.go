@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestLogoutCommand(t *testing.T) {
+	if logoutCmd.Use != "logout" {
+		t.Errorf("Expected Use 'logout', got '%s'", logoutCmd.Use)
+	}
+
+	if logoutCmd.Short == "" {
+		t.Error("logoutCmd should have Short description")
+	}
+
+	if logoutCmd.Run == nil {
+		t.Error("logoutCmd should have Run function")
+	}
+}
+
+func TestLogoutCommandFlags(t *testing.T) {
+	allFlag := logoutCmd.Flags().Lookup("all")
+	if allFlag == nil {
+		t.Fatal("--all flag should be defined for logout command")
+	}
+
+	if allFlag.DefValue != "false" {
+		t.Errorf("Expected all flag default to be 'false', got '%s'", allFlag.DefValue)
+	}
+
+	if allFlag.Usage == "" {
+		t.Error("All flag should have usage description")
+	}
+}
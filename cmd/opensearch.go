@@ -25,6 +25,7 @@ var opensearchConnectCmd = &cobra.Command{
 var opensearchLocalPort int
 var opensearchDomainName string
 var opensearchSwitchAccount bool
+var opensearchRegions string
 
 func init() {
 	rootCmd.AddCommand(opensearchCmd)
@@ -32,13 +33,22 @@ func init() {
 	opensearchConnectCmd.Flags().IntVar(&opensearchLocalPort, "local-port", 443, "Local port for port forwarding (defaults to 443)")
 	opensearchConnectCmd.Flags().StringVar(&opensearchDomainName, "name", "", "Name of the OpenSearch domain to connect to directly")
 	opensearchConnectCmd.Flags().BoolVarP(&opensearchSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+	addRegionsFlag(opensearchConnectCmd, &opensearchRegions)
+}
+
+func createOpenSearchManager(ctx context.Context) (*aws.OpenSearchManager, error) {
+	regions, err := resolveRegionsFlag(ctx, opensearchRegions)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewOpenSearchManager(ctx, aws.OpenSearchManagerOptions{Regions: regions})
 }
 
 func runOpenSearchConnect(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
 	// Create OpenSearch manager
-	opensearchManager, err := aws.NewOpenSearchManager(ctx)
+	opensearchManager, err := createOpenSearchManager(ctx)
 	if err != nil {
 		// Check if this is a "no active session" error
 		if aws.IsAuthError(err) {
@@ -52,7 +62,7 @@ func runOpenSearchConnect(cmd *cobra.Command, args []string) {
 				os.Exit(1)
 			}
 			// Retry creating manager after successful login
-			opensearchManager, err = aws.NewOpenSearchManager(ctx)
+			opensearchManager, err = createOpenSearchManager(ctx)
 			if err != nil {
 				fmt.Printf("Error creating OpenSearch manager after re-authentication: %v\n", err)
 				os.Exit(1)
@@ -70,7 +80,7 @@ func runOpenSearchConnect(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 		// Recreate OpenSearch manager with new credentials
-		opensearchManager, err = aws.NewOpenSearchManager(ctx)
+		opensearchManager, err = createOpenSearchManager(ctx)
 		if err != nil {
 			fmt.Printf("Error creating OpenSearch manager after account switch: %v\n", err)
 			os.Exit(1)
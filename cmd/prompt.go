@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/blontic/awsc/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the active account and role as a short prompt segment",
+	Long:  `Print the account and role of this terminal's session for use in a shell prompt, coloured red for production accounts. Reads only local session and profile files, and prints nothing when there is no session.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Runs on every prompt - skip config setup and anything slower than reading files
+	},
+	Run: runPrompt,
+}
+
+var promptShell string
+var promptNoColor bool
+var promptProfileOnly bool
+
+const defaultPromptProdPattern = `(?i)prod`
+
+const (
+	promptColorProd    = "31" // Red
+	promptColorDefault = "36" // Cyan
+	promptColorExpired = "33" // Yellow
+)
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.Flags().StringVar(&promptShell, "shell", "", "Wrap colour codes for the prompt of this shell (bash, zsh or fish)")
+	promptCmd.Flags().BoolVar(&promptNoColor, "no-color", false, "Print the segment without colour")
+	promptCmd.Flags().BoolVar(&promptProfileOnly, "profile", false, "Print only the active profile name")
+}
+
+// promptInfo is what the prompt segment shows about the active session
+type promptInfo struct {
+	Profile     string
+	AccountName string
+	RoleName    string
+	Expiration  time.Time
+}
+
+func runPrompt(cmd *cobra.Command, args []string) {
+	info, ok := loadPromptInfo()
+	if !ok {
+		// No session: print nothing so the prompt stays clean
+		return
+	}
+
+	if promptProfileOnly {
+		fmt.Println(info.Profile)
+		return
+	}
+
+	pattern := viper.GetString("prompt.prod_pattern")
+	if pattern == "" {
+		pattern = defaultPromptProdPattern
+	}
+	prodPattern, err := regexp.Compile(pattern)
+	if err != nil {
+		prodPattern = regexp.MustCompile(defaultPromptProdPattern)
+	}
+
+	fmt.Print(formatPromptSegment(info, prodPattern, promptShell, !promptNoColor && os.Getenv("NO_COLOR") == "", time.Now()))
+}
+
+// loadPromptInfo resolves the active session from local files only
+func loadPromptInfo() (promptInfo, bool) {
+	profileName, source, err := config.ResolveProfile()
+	if err != nil {
+		return promptInfo{}, false
+	}
+
+	info := promptInfo{Profile: profileName}
+	if source == config.SessionSourcePPID {
+		if session, err := config.GetCurrentSession(); err == nil {
+			info.AccountName, info.RoleName, info.Expiration = session.AccountName, session.RoleName, session.Expiration
+		}
+	}
+	if info.AccountName == "" {
+		if metadata, err := config.GetProfileMetadata(profileName); err == nil {
+			info.AccountName, info.RoleName, info.Expiration = metadata.AccountName, metadata.RoleName, metadata.Expiration
+			if metadata.Chain != "" {
+				info.AccountName = metadata.Chain
+			}
+		}
+	}
+	return info, true
+}
+
+// formatPromptSegment renders "aws:account/role", coloured and wrapped in the shell's
+// zero-width markers so line editing keeps the right cursor position
+func formatPromptSegment(info promptInfo, prodPattern *regexp.Regexp, shell string, color bool, now time.Time) string {
+	account := info.AccountName
+	if account == "" {
+		account = strings.TrimPrefix(info.Profile, "awsc-")
+	}
+
+	segment := "aws:" + account
+	if info.RoleName != "" {
+		segment += "/" + info.RoleName
+	}
+
+	expired := !info.Expiration.IsZero() && now.After(info.Expiration)
+	if expired {
+		segment += " (expired)"
+	}
+
+	if !color {
+		return segment
+	}
+
+	code := promptColorDefault
+	switch {
+	case expired:
+		code = promptColorExpired
+	case prodPattern.MatchString(account) || prodPattern.MatchString(info.Profile):
+		code = promptColorProd
+	}
+
+	start, end := "\x1b["+code+"m", "\x1b[0m"
+	switch shell {
+	case "bash":
+		// Readline's ignore markers; \[ \] aren't interpreted in command substitution output
+		start, end = "\x01"+start+"\x02", "\x01"+end+"\x02"
+	case "zsh":
+		start, end = "%{"+start+"%}", "%{"+end+"%}"
+	}
+	return start + segment + end
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestPromptCommand(t *testing.T) {
+	if promptCmd.Use != "prompt" {
+		t.Errorf("Expected Use 'prompt', got '%s'", promptCmd.Use)
+	}
+
+	// Runs on every prompt, so it must not inherit the interactive config setup
+	if promptCmd.PersistentPreRun == nil {
+		t.Error("promptCmd should override PersistentPreRun")
+	}
+
+	for _, name := range []string{"shell", "no-color", "profile"} {
+		if promptCmd.Flags().Lookup(name) == nil {
+			t.Errorf("--%s flag should be defined for prompt command", name)
+		}
+	}
+}
+
+func TestFormatPromptSegment(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	prod := regexp.MustCompile(defaultPromptProdPattern)
+
+	tests := []struct {
+		name     string
+		info     promptInfo
+		shell    string
+		color    bool
+		expected string
+	}{
+		{"plain", promptInfo{Profile: "awsc-dev", AccountName: "dev", RoleName: "ReadOnly"}, "", false, "aws:dev/ReadOnly"},
+		{"profile without metadata", promptInfo{Profile: "awsc-sandbox"}, "", false, "aws:sandbox"},
+		{"prod is red", promptInfo{Profile: "awsc-prod", AccountName: "Production", RoleName: "Admin"}, "", true, "\x1b[31maws:Production/Admin\x1b[0m"},
+		{"other accounts are cyan", promptInfo{Profile: "awsc-dev", AccountName: "dev"}, "fish", true, "\x1b[36maws:dev\x1b[0m"},
+		{"expired is yellow", promptInfo{Profile: "awsc-prod", AccountName: "prod", Expiration: now.Add(-time.Minute)}, "", true, "\x1b[33maws:prod (expired)\x1b[0m"},
+		{"bash markers", promptInfo{Profile: "awsc-dev", AccountName: "dev"}, "bash", true, "\x01\x1b[36m\x02aws:dev\x01\x1b[0m\x02"},
+		{"zsh markers", promptInfo{Profile: "awsc-dev", AccountName: "dev"}, "zsh", true, "%{\x1b[36m%}aws:dev%{\x1b[0m%}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatPromptSegment(tt.info, prod, tt.shell, tt.color, now); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -25,6 +25,9 @@ var rdsConnectCmd = &cobra.Command{
 var localPort int
 var rdsInstanceName string
 var switchAccount bool
+var rdsRegions string
+var rdsIAMUser string
+var rdsExec string
 
 func init() {
 	rootCmd.AddCommand(rdsCmd)
@@ -32,13 +35,24 @@ func init() {
 	rdsConnectCmd.Flags().IntVar(&localPort, "local-port", 0, "Local port for port forwarding (defaults to RDS port)")
 	rdsConnectCmd.Flags().StringVar(&rdsInstanceName, "name", "", "Name of the RDS instance to connect to directly")
 	rdsConnectCmd.Flags().BoolVarP(&switchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+	rdsConnectCmd.Flags().StringVar(&rdsIAMUser, "iam-user", "", "Database user to print an IAM auth token for")
+	rdsConnectCmd.Flags().StringVar(&rdsExec, "exec", "", "Start a database client through the tunnel: auto, psql, mysql or a command template")
+	addRegionsFlag(rdsConnectCmd, &rdsRegions)
+}
+
+func createRDSManager(ctx context.Context) (*aws.RDSManager, error) {
+	regions, err := resolveRegionsFlag(ctx, rdsRegions)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewRDSManager(ctx, aws.RDSManagerOptions{Regions: regions})
 }
 
 func runRDSConnect(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
 	// Create RDS manager
-	rdsManager, err := aws.NewRDSManager(ctx)
+	rdsManager, err := createRDSManager(ctx)
 	if err != nil {
 		// Check if this is a "no active session" error
 		if aws.IsAuthError(err) {
@@ -52,7 +66,7 @@ func runRDSConnect(cmd *cobra.Command, args []string) {
 				os.Exit(1)
 			}
 			// Retry creating manager after successful login
-			rdsManager, err = aws.NewRDSManager(ctx)
+			rdsManager, err = createRDSManager(ctx)
 			if err != nil {
 				fmt.Printf("Error creating RDS manager after re-authentication: %v\n", err)
 				os.Exit(1)
@@ -70,7 +84,7 @@ func runRDSConnect(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 		// Recreate RDS manager with new credentials
-		rdsManager, err = aws.NewRDSManager(ctx)
+		rdsManager, err = createRDSManager(ctx)
 		if err != nil {
 			fmt.Printf("Error creating RDS manager after account switch: %v\n", err)
 			os.Exit(1)
@@ -78,7 +92,13 @@ func runRDSConnect(cmd *cobra.Command, args []string) {
 	}
 
 	// Run the RDS connect workflow
-	if err := rdsManager.RunConnect(ctx, rdsInstanceName, int32(localPort)); err != nil {
+	connectOpts := aws.RDSConnectOptions{
+		Name:      rdsInstanceName,
+		LocalPort: int32(localPort),
+		IAMUser:   rdsIAMUser,
+		Exec:      rdsExec,
+	}
+	if err := rdsManager.RunConnect(ctx, connectOpts); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
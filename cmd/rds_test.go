@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func TestRDSCommands(t *testing.T) {
@@ -125,3 +128,52 @@ func TestRDSConnectFlagUsage(t *testing.T) {
 		}
 	}
 }
+
+func TestRDSConnectIAMUserFlag(t *testing.T) {
+	flag := rdsConnectCmd.Flags().Lookup("iam-user")
+	if flag == nil {
+		t.Fatal("rds connect should have an --iam-user flag")
+	}
+	if flag.DefValue != "" {
+		t.Errorf("Expected --iam-user to be off by default, got %q", flag.DefValue)
+	}
+}
+
+func TestRDSConnectExecFlag(t *testing.T) {
+	flag := rdsConnectCmd.Flags().Lookup("exec")
+	if flag == nil {
+		t.Fatal("rds connect should have an --exec flag")
+	}
+	if flag.DefValue != "" {
+		t.Errorf("Expected --exec to be off by default, got %q", flag.DefValue)
+	}
+}
+
+func TestRegionsFlag(t *testing.T) {
+	// Every resource command accepts --regions
+	for _, cmd := range []*cobra.Command{rdsConnectCmd, ec2ConnectCmd, ec2RdpCmd, opensearchConnectCmd, secretsShowCmd} {
+		if cmd.Flags().Lookup("regions") == nil {
+			t.Errorf("%s should have a --regions flag", cmd.CommandPath())
+		}
+	}
+}
+
+func TestResolveRegionsFlag(t *testing.T) {
+	defer func() { regionOverride = "" }()
+
+	regions, err := resolveRegionsFlag(context.Background(), "us-east-1,eu-west-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(regions, ",") != "us-east-1,eu-west-1" {
+		t.Errorf("Unexpected regions: %v", regions)
+	}
+
+	regionOverride = "us-west-2"
+	if _, err := resolveRegionsFlag(context.Background(), "us-east-1"); err == nil {
+		t.Error("Expected an error when --region and --regions are both set")
+	}
+	if regions, err := resolveRegionsFlag(context.Background(), ""); err != nil || regions != nil {
+		t.Errorf("Expected --region alone to be accepted, got %v (%v)", regions, err)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+// addRegionsFlag adds --regions to a command that lists resources
+func addRegionsFlag(cmd *cobra.Command, value *string) {
+	cmd.Flags().StringVar(value, "regions", "", "Comma-separated regions to search, or 'all' for every enabled region")
+}
+
+// resolveRegionsFlag expands a --regions value for the active session. It returns nil when
+// the flag isn't set, so the manager uses the profile's region.
+func resolveRegionsFlag(ctx context.Context, value string) ([]string, error) {
+	if value != "" && regionOverride != "" {
+		return nil, fmt.Errorf("--region and --regions can't be used together")
+	}
+	return aws.ResolveRegions(ctx, value)
+}
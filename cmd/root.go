@@ -13,6 +13,7 @@ import (
 var cfgFile string
 var regionOverride string
 var verbose bool
+var noBrowser bool
 
 var rootCmd = &cobra.Command{
 	Use:   "awsc",
@@ -37,10 +38,14 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(func() {
 		initViper(cfgFile, regionOverride)
+		if noBrowser {
+			viper.Set("sso.no_browser", true)
+		}
 	})
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.awsc/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&regionOverride, "region", "", "AWS region to use (overrides config)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noBrowser, "no-browser", false, "print the SSO sign-in URL to stderr instead of opening a browser")
 }
 
 // initViper initializes viper configuration
@@ -52,6 +52,13 @@ func TestGlobalFlags(t *testing.T) {
 	if verboseFlagShort == nil {
 		t.Error("-v short flag should be defined for verbose")
 	}
+
+	noBrowserFlag := rootCmd.PersistentFlags().Lookup("no-browser")
+	if noBrowserFlag == nil {
+		t.Error("--no-browser flag should be defined")
+	} else if noBrowserFlag.DefValue != "false" {
+		t.Errorf("Expected no-browser flag default to be 'false', got '%s'", noBrowserFlag.DefValue)
+	}
 }
 
 func TestExecute(t *testing.T) {
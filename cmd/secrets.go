@@ -21,18 +21,28 @@ var secretsShowCmd = &cobra.Command{
 }
 
 var secretName string
+var secretsRegions string
 
 func init() {
 	secretsShowCmd.Flags().StringVar(&secretName, "name", "", "Name of the secret to show directly")
+	addRegionsFlag(secretsShowCmd, &secretsRegions)
 	secretsCmd.AddCommand(secretsShowCmd)
 	rootCmd.AddCommand(secretsCmd)
 }
 
+func createSecretsManager(ctx context.Context) (*aws.SecretsManager, error) {
+	regions, err := resolveRegionsFlag(ctx, secretsRegions)
+	if err != nil {
+		return nil, err
+	}
+	return aws.NewSecretsManager(ctx, aws.SecretsManagerOptions{Regions: regions})
+}
+
 func runSecretsShowCommand(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
 	// Create secrets manager
-	secretsManager, err := aws.NewSecretsManager(ctx)
+	secretsManager, err := createSecretsManager(ctx)
 	if err != nil {
 		// Check if this is a "no active session" error
 		if aws.IsAuthError(err) {
@@ -46,7 +56,7 @@ func runSecretsShowCommand(cmd *cobra.Command, args []string) {
 				os.Exit(1)
 			}
 			// Retry creating manager after successful login
-			secretsManager, err = aws.NewSecretsManager(ctx)
+			secretsManager, err = createSecretsManager(ctx)
 			if err != nil {
 				fmt.Printf("Error creating secrets manager after re-authentication: %v\n", err)
 				os.Exit(1)
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List and manage the sessions that bind terminals to awsc profiles",
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every session with its account, role, liveness and expiry",
+	Run:   runSessionsList,
+}
+
+var sessionsUseCmd = &cobra.Command{
+	Use:   "use [profile|account]",
+	Short: "Bind this shell to an existing awsc profile without logging in again",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runSessionsUse,
+}
+
+var sessionsCopyCmd = &cobra.Command{
+	Use:   "copy --from <pid|key>",
+	Short: "Bind this shell to another terminal's session",
+	Run:   runSessionsCopy,
+}
+
+var sessionsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove sessions of exited shells and awsc profiles with expired credentials",
+	Run:   runSessionsPrune,
+}
+
+var sessionsOutput string
+var sessionsCopyFrom string
+
+func init() {
+	sessionsListCmd.Flags().StringVarP(&sessionsOutput, "output", "o", "text", "Output format (text or json)")
+	sessionsCopyCmd.Flags().StringVar(&sessionsCopyFrom, "from", "", "Shell PID or session key to copy, as shown by 'awsc sessions list'")
+	sessionsCopyCmd.MarkFlagRequired("from")
+
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsUseCmd)
+	sessionsCmd.AddCommand(sessionsCopyCmd)
+	sessionsCmd.AddCommand(sessionsPruneCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) {
+	if sessionsOutput != "text" && sessionsOutput != "json" {
+		fmt.Printf("Error: invalid output format '%s' (expected text or json)\n", sessionsOutput)
+		os.Exit(1)
+	}
+
+	sessions, err := config.ListSessions()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if sessionsOutput == "json" {
+		if sessions == nil {
+			sessions = []config.StoredSession{}
+		}
+		data, err := json.MarshalIndent(sessions, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(sessions) == 0 {
+		fmt.Printf("No sessions found\n")
+		return
+	}
+
+	currentKey := ""
+	if key, err := config.CurrentSessionKey(); err == nil {
+		currentKey = key.ID
+	}
+	printSessions(sessions, currentKey)
+}
+
+// printSessions prints sessions as a table, marking the current shell's session with *
+func printSessions(sessions []config.StoredSession, currentKey string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  KEY\tACCOUNT\tROLE\tPROFILE\tSTATUS\tCREDENTIALS")
+	for _, session := range sessions {
+		marker := " "
+		if session.Key == currentKey {
+			marker = "*"
+		}
+
+		account := session.AccountName
+		if session.AccountID != "" && session.AccountID != session.AccountName {
+			account = fmt.Sprintf("%s (%s)", session.AccountName, session.AccountID)
+		}
+
+		status := "alive"
+		if !session.Alive {
+			status = "dead"
+		}
+
+		credentials := "auto-refresh"
+		if !session.Expiration.IsZero() {
+			credentials = formatExpiry(session.Expiration)
+		}
+
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%s\t%s\n", marker, session.Key, orDash(account), orDash(session.RoleName), orDash(session.ProfileName), status, credentials)
+	}
+	w.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func runSessionsUse(cmd *cobra.Command, args []string) {
+	target := ""
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	profiles, err := config.LoadAWSCProfiles()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(profiles) == 0 {
+		fmt.Printf("Error: no awsc profiles found - run 'awsc login' first\n")
+		os.Exit(1)
+	}
+
+	// A missing or ambiguous target falls back to interactive selection
+	candidates := profiles
+	if target != "" {
+		candidates = config.MatchAWSCProfiles(profiles, target)
+		switch len(candidates) {
+		case 0:
+			fmt.Printf("Profile or account '%s' not found. Available profiles:\n\n", target)
+			candidates = profiles
+		case 1:
+			fmt.Printf("Found profile: %s\n", candidates[0].Name)
+		}
+	}
+
+	selected := candidates[0]
+	if len(candidates) > 1 {
+		options := make([]string, len(candidates))
+		for i, profile := range candidates {
+			options[i] = describeProfile(profile)
+		}
+
+		index, err := ui.RunSelector("Select Profile:", options)
+		if err != nil {
+			fmt.Printf("Error selecting profile: %v\n", err)
+			os.Exit(1)
+		}
+		if index == -1 {
+			fmt.Printf("Error: no profile selected\n")
+			os.Exit(1)
+		}
+		selected = candidates[index]
+		fmt.Printf("✓ Selected: %s\n", selected.Name)
+	}
+
+	if selected.Metadata.Expired() {
+		fmt.Fprintf(os.Stderr, "Warning: credentials of %s have expired - you will be asked to log in again\n", selected.Name)
+	}
+
+	if err := config.UseProfile(selected); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Shell now uses profile %s\n", selected.Name)
+}
+
+func runSessionsCopy(cmd *cobra.Command, args []string) {
+	session, err := config.CopySession(sessionsCopyFrom)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Shell now uses profile %s from session %s\n", session.ProfileName, sessionsCopyFrom)
+}
+
+func runSessionsPrune(cmd *cobra.Command, args []string) {
+	removedSessions, err := config.PruneSessions()
+	if err != nil {
+		fmt.Printf("Error removing sessions: %v\n", err)
+		os.Exit(1)
+	}
+	for _, key := range removedSessions {
+		fmt.Printf("✓ Removed session: %s\n", key)
+	}
+
+	removedProfiles, err := config.RemoveExpiredProfiles()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range removedProfiles {
+		fmt.Printf("✓ Removed expired profile: %s\n", name)
+	}
+
+	if len(removedSessions) == 0 && len(removedProfiles) == 0 {
+		fmt.Printf("Nothing to prune\n")
+	}
+}
+
+// describeProfile labels a profile in the selector with its account and role, or chain
+func describeProfile(profile config.AWSCProfile) string {
+	switch {
+	case profile.Metadata.Chain != "":
+		return fmt.Sprintf("%s (chain %s)", profile.Name, profile.Metadata.Chain)
+	case profile.Metadata.AccountName != "":
+		return fmt.Sprintf("%s (%s / %s)", profile.Name, profile.Metadata.AccountName, profile.Metadata.RoleName)
+	default:
+		return profile.Name
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestSessionsCommand(t *testing.T) {
+	if sessionsCmd.Use != "sessions" {
+		t.Errorf("Expected Use 'sessions', got '%s'", sessionsCmd.Use)
+	}
+
+	subcommands := map[string]bool{}
+	for _, sub := range sessionsCmd.Commands() {
+		subcommands[sub.Name()] = true
+		if sub.Run == nil {
+			t.Errorf("sessions %s should have Run function", sub.Name())
+		}
+	}
+	for _, name := range []string{"list", "use", "copy", "prune"} {
+		if !subcommands[name] {
+			t.Errorf("sessions should have %s subcommand", name)
+		}
+	}
+
+	fromFlag := sessionsCopyCmd.Flags().Lookup("from")
+	if fromFlag == nil {
+		t.Fatal("--from flag should be defined for sessions copy")
+	}
+	if required := fromFlag.Annotations["cobra_annotation_bash_completion_one_required_flag"]; len(required) == 0 {
+		t.Error("--from flag should be required")
+	}
+
+	if outputFlag := sessionsListCmd.Flags().Lookup("output"); outputFlag == nil || outputFlag.DefValue != "text" {
+		t.Error("sessions list should have --output flag defaulting to text")
+	}
+}
+
+func TestOrDash(t *testing.T) {
+	if orDash("") != "-" || orDash("prod") != "prod" {
+		t.Error("orDash should replace only empty values")
+	}
+}
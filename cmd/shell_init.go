@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init bash|zsh|fish",
+	Short: "Print shell functions that keep AWSC_PROFILE in sync and show the session in the prompt",
+	Long: `Print shell functions for your shell startup file. The awsc wrapper function exports AWSC_PROFILE in the current shell after login, logout, switching accounts and session changes, and awsc_prompt prints the active account and role for your prompt.
+
+  bash:  eval "$(awsc shell-init bash)"   then  PS1='$(awsc_prompt) '"$PS1"
+  zsh:   eval "$(awsc shell-init zsh)"    then  setopt PROMPT_SUBST; PROMPT='$(awsc_prompt) '"$PROMPT"
+  fish:  awsc shell-init fish | source    then  call awsc_prompt from fish_prompt`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Runs from shell startup files - never prompt for configuration
+		debug.SetVerbose(verbose)
+	},
+	Run: runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func runShellInit(cmd *cobra.Command, args []string) {
+	script, err := shellInitScript(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(script)
+}
+
+// shellInitScript returns the shell-init functions for shell
+func shellInitScript(shell string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		return strings.ReplaceAll(posixShellInit, "{{shell}}", shell), nil
+	case "fish":
+		return fishShellInit, nil
+	default:
+		return "", fmt.Errorf("unsupported shell '%s' (expected bash, zsh or fish)", shell)
+	}
+}
+
+// posixShellInit re-reads the session after the commands that change which profile the
+// shell uses, with AWSC_PROFILE cleared so the session file is consulted. Only the
+// subcommand is matched, plus --switch-account on the connect commands that have it, so
+// arguments such as a secret named "login" don't trigger a sync.
+const posixShellInit = `# awsc shell integration ({{shell}})
+awsc() {
+    command awsc "$@"
+    local awsc_status=$?
+    case "$1" in
+        login|logout|sessions)
+            _awsc_sync_profile
+            ;;
+        rds|ec2|opensearch)
+            local arg
+            for arg in "$@"; do
+                case "$arg" in
+                    -s|--switch-account)
+                        _awsc_sync_profile
+                        break
+                        ;;
+                esac
+            done
+            ;;
+    esac
+    return $awsc_status
+}
+
+_awsc_sync_profile() {
+    local profile
+    profile="$(AWSC_PROFILE= command awsc prompt --profile 2>/dev/null)"
+    if [ -n "$profile" ]; then
+        export AWSC_PROFILE="$profile"
+    else
+        unset AWSC_PROFILE
+    fi
+}
+
+awsc_prompt() {
+    command awsc prompt --shell {{shell}} 2>/dev/null
+}
+`
+
+const fishShellInit = `# awsc shell integration (fish)
+function awsc --wraps awsc
+    command awsc $argv
+    set -l awsc_status $status
+    switch "$argv[1]"
+        case login logout sessions
+            _awsc_sync_profile
+        case rds ec2 opensearch
+            if contains -- -s $argv; or contains -- --switch-account $argv
+                _awsc_sync_profile
+            end
+    end
+    return $awsc_status
+end
+
+function _awsc_sync_profile
+    set -l profile (env AWSC_PROFILE= awsc prompt --profile 2>/dev/null)
+    if test -n "$profile"
+        set -gx AWSC_PROFILE $profile
+    else
+        set -e AWSC_PROFILE
+    end
+end
+
+function awsc_prompt
+    command awsc prompt --shell fish 2>/dev/null
+end
+`
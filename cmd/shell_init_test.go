@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellInitCommand(t *testing.T) {
+	if shellInitCmd.Run == nil {
+		t.Error("shellInitCmd should have Run function")
+	}
+
+	// Runs from shell startup files, so it must not inherit the interactive config setup
+	if shellInitCmd.PersistentPreRun == nil {
+		t.Error("shellInitCmd should override PersistentPreRun")
+	}
+
+	if err := shellInitCmd.Args(shellInitCmd, []string{}); err == nil {
+		t.Error("shell-init should require a shell")
+	}
+}
+
+func TestShellInitScript(t *testing.T) {
+	tests := []struct {
+		shell    string
+		contains []string
+	}{
+		{"bash", []string{"awsc() {", "export AWSC_PROFILE=", "prompt --shell bash"}},
+		{"zsh", []string{"awsc() {", "export AWSC_PROFILE=", "prompt --shell zsh"}},
+		{"fish", []string{"function awsc --wraps awsc", "set -gx AWSC_PROFILE", "prompt --shell fish"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := shellInitScript(tt.shell)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(script, s) {
+					t.Errorf("Expected script to contain %q", s)
+				}
+			}
+			if strings.Contains(script, "{{shell}}") {
+				t.Error("Script contains unreplaced placeholder")
+			}
+		})
+	}
+
+	if _, err := shellInitScript("tcsh"); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestShellInitWrapperSync(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	// Stub awsc that reports a new profile to the wrapper's sync
+	binDir := t.TempDir()
+	stub := "#!/bin/sh\nif [ \"$1\" = prompt ]; then echo awsc-new; fi\n"
+	if err := os.WriteFile(filepath.Join(binDir, "awsc"), []byte(stub), 0755); err != nil {
+		t.Fatalf("Failed to write stub: %v", err)
+	}
+
+	script, err := shellInitScript("bash")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{"login", "login", "awsc-new"},
+		{"sessions use", "sessions use prod", "awsc-new"},
+		{"rds connect switching account", "rds connect -s", "awsc-new"},
+		{"ec2 rdp switching account", "ec2 rdp --switch-account", "awsc-new"},
+		{"rds connect", "rds connect", "awsc-old"},
+		{"argument named like a command", "secrets show login", "awsc-old"},
+		{"-s on another command", "console -s", "awsc-old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(bash, "-c", script+"\nawsc "+tt.args+"\necho \"$AWSC_PROFILE\"")
+			cmd.Env = append(os.Environ(), "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"), "AWSC_PROFILE=awsc-old")
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("bash failed: %v", err)
+			}
+			if got := strings.TrimSpace(string(output)); got != tt.expected {
+				t.Errorf("Expected AWSC_PROFILE %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
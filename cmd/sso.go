@@ -7,6 +7,7 @@ import (
 
 	"github.com/blontic/awsc/internal/aws"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var loginCmd = &cobra.Command{
@@ -19,17 +20,53 @@ var loginCmd = &cobra.Command{
 var forceAuth bool
 var accountName string
 var roleName string
+var loginAll bool
+var includeAccounts string
+var excludeAccounts string
+var loginConcurrency int
+var loginChain string
+var loginMethod string
 
 func init() {
 	rootCmd.AddCommand(loginCmd)
 	loginCmd.Flags().BoolVar(&forceAuth, "force", false, "Force re-authentication by clearing cached tokens")
-	loginCmd.Flags().StringVar(&accountName, "account", "", "Account name to connect to (optional)")
-	loginCmd.Flags().StringVar(&roleName, "role", "", "Role name to assume (optional)")
+	loginCmd.Flags().StringVar(&accountName, "account", "", "Account name, prefix or ID to connect to (optional)")
+	loginCmd.Flags().StringVar(&roleName, "role", "", "Role name to assume (optional, filters roles with --all)")
+	loginCmd.Flags().BoolVar(&loginAll, "all", false, "Write a profile for every account and role")
+	loginCmd.Flags().StringVar(&includeAccounts, "include", "", "Regex of account names to include (with --all)")
+	loginCmd.Flags().StringVar(&excludeAccounts, "exclude", "", "Regex of account names to exclude (with --all)")
+	loginCmd.Flags().IntVar(&loginConcurrency, "concurrency", 5, "Maximum concurrent SSO requests (with --all)")
+	loginCmd.Flags().StringVar(&loginChain, "chain", "", "Role chain from config to assume after SSO login")
+	loginCmd.Flags().StringVar(&loginMethod, "login-method", "", "SSO login method: device or pkce (overrides sso.login_method)")
+	loginCmd.MarkFlagsMutuallyExclusive("all", "chain", "account")
+}
+
+// validateLoginFlags rejects the bulk login filters when --all isn't set, instead of
+// ignoring them
+func validateLoginFlags(cmd *cobra.Command) error {
+	if cmd.Flags().Changed("all") {
+		return nil
+	}
+	for _, name := range []string{"include", "exclude", "concurrency"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s can only be used with --all", name)
+		}
+	}
+	return nil
 }
 
 func runSSOLogin(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
+	if err := validateLoginFlags(cmd); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if loginMethod != "" {
+		viper.Set("sso.login_method", loginMethod)
+	}
+
 	// Create SSO manager and run login
 	ssoManager, err := aws.NewSSOManager(ctx)
 	if err != nil {
@@ -37,6 +74,28 @@ func runSSOLogin(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if loginChain != "" {
+		if err := ssoManager.RunLoginChain(ctx, forceAuth, loginChain); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if loginAll {
+		opts := aws.BulkLoginOptions{
+			IncludeAccounts: includeAccounts,
+			ExcludeAccounts: excludeAccounts,
+			RoleName:        roleName,
+			Concurrency:     loginConcurrency,
+		}
+		if err := ssoManager.RunLoginAll(ctx, forceAuth, opts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := ssoManager.RunLogin(ctx, forceAuth, accountName, roleName); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
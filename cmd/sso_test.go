@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func TestSSOCommands(t *testing.T) {
@@ -83,3 +85,78 @@ func TestRunLogin(t *testing.T) {
 	// Business logic is tested in internal/aws package
 	// This only tests CLI interface
 }
+
+func TestLoginCommandBulkFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		defValue string
+	}{
+		{"all", "false"},
+		{"include", ""},
+		{"exclude", ""},
+		{"concurrency", "5"},
+		{"chain", ""},
+		{"login-method", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := loginCmd.Flags().Lookup(tt.name)
+			if flag == nil {
+				t.Fatalf("--%s flag should be defined for login command", tt.name)
+			}
+			if flag.DefValue != tt.defValue {
+				t.Errorf("Expected %s flag default to be '%s', got '%s'", tt.name, tt.defValue, flag.DefValue)
+			}
+			if flag.Usage == "" {
+				t.Errorf("%s flag should have usage description", tt.name)
+			}
+		})
+	}
+}
+
+func TestLoginFlagValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"no flags", nil, false},
+		{"account and role", []string{"--account", "prod", "--role", "Admin"}, false},
+		{"all with filters", []string{"--all", "--include", "^prod-", "--exclude", "sandbox", "--concurrency", "2"}, false},
+		{"all with role", []string{"--all", "--role", "ReadOnly"}, false},
+		{"chain", []string{"--chain", "prod-db-admin"}, false},
+		{"all and chain", []string{"--all", "--chain", "prod-db-admin"}, true},
+		{"all and account", []string{"--all", "--account", "prod"}, true},
+		{"chain and account", []string{"--chain", "prod-db-admin", "--account", "prod"}, true},
+		{"include without all", []string{"--include", "^prod-"}, true},
+		{"exclude without all", []string{"--account", "prod", "--exclude", "sandbox"}, true},
+		{"concurrency without all", []string{"--concurrency", "10"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Parse into copies of the flags so the command's own flags stay untouched
+			cmd := &cobra.Command{Use: "login"}
+			for _, name := range []string{"account", "role", "include", "exclude", "chain"} {
+				cmd.Flags().String(name, "", "")
+			}
+			cmd.Flags().Bool("all", false, "")
+			cmd.Flags().Int("concurrency", 5, "")
+			for _, name := range []string{"all", "chain", "account"} {
+				cmd.Flags().Lookup(name).Annotations = loginCmd.Flags().Lookup(name).Annotations
+			}
+
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			err := cmd.ValidateFlagGroups()
+			if err == nil {
+				err = validateLoginFlags(cmd)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validation error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
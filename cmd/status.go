@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"whoami"},
+	Short:   "Show the AWS identity and expiry for this terminal",
+	Long:    `Show which account and role this terminal is bound to, where the session came from, and when the credentials and SSO token expire`,
+	Run:     runStatus,
+}
+
+var statusOutput string
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "text", "Output format (text or json)")
+}
+
+func runStatus(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	if statusOutput != "text" && statusOutput != "json" {
+		fmt.Printf("Error: invalid output format '%s' (expected text or json)\n", statusOutput)
+		os.Exit(1)
+	}
+
+	statusManager, err := aws.NewStatusManager(ctx)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	status, err := statusManager.GetStatus(ctx)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if statusOutput == "json" {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		printStatus(status)
+	}
+
+	if status.Error != "" {
+		os.Exit(1)
+	}
+}
+
+func printStatus(status *aws.Status) {
+	fmt.Printf("Profile:      %s (from %s)\n", status.Profile, status.Source)
+	if status.AccountName != "" && status.AccountName != status.AccountID {
+		fmt.Printf("Account:      %s (%s)\n", status.AccountName, status.AccountID)
+	} else if status.AccountID != "" {
+		fmt.Printf("Account:      %s\n", status.AccountID)
+	}
+	if status.RoleName != "" {
+		fmt.Printf("Role:         %s\n", status.RoleName)
+	}
+	if status.Arn != "" {
+		fmt.Printf("ARN:          %s\n", status.Arn)
+	}
+	fmt.Printf("Region:       %s\n", status.Region)
+	if status.CredentialsExpiration != nil {
+		fmt.Printf("Credentials:  %s\n", formatExpiry(*status.CredentialsExpiration))
+	}
+	if status.SSOTokenExpiration != nil {
+		fmt.Printf("SSO Token:    %s\n", formatExpiry(*status.SSOTokenExpiration))
+	}
+	if status.Error != "" {
+		fmt.Printf("\nError: %s\n", status.Error)
+	}
+}
+
+// formatExpiry describes an expiry time relative to now
+func formatExpiry(expires time.Time) string {
+	remaining := time.Until(expires).Round(time.Minute)
+	local := expires.Local().Format("2006-01-02 15:04 MST")
+	if remaining <= 0 {
+		return fmt.Sprintf("expired %s ago (%s)", formatDuration(-remaining), local)
+	}
+	return fmt.Sprintf("expires in %s (%s)", formatDuration(remaining), local)
+}
+
+// formatDuration formats a minute-rounded duration without trailing seconds (e.g. 1h5m)
+func formatDuration(d time.Duration) string {
+	return strings.TrimSuffix(d.String(), "0s")
+}
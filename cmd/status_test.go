@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusCommand(t *testing.T) {
+	if statusCmd.Use != "status" {
+		t.Errorf("Expected Use 'status', got '%s'", statusCmd.Use)
+	}
+
+	if len(statusCmd.Aliases) == 0 || statusCmd.Aliases[0] != "whoami" {
+		t.Errorf("Expected alias 'whoami', got %v", statusCmd.Aliases)
+	}
+
+	if statusCmd.Short == "" {
+		t.Error("statusCmd should have Short description")
+	}
+
+	if statusCmd.Run == nil {
+		t.Error("statusCmd should have Run function")
+	}
+
+	outputFlag := statusCmd.Flags().Lookup("output")
+	if outputFlag == nil {
+		t.Fatal("--output flag should be defined for status command")
+	}
+	if outputFlag.DefValue != "text" {
+		t.Errorf("Expected output flag default to be 'text', got '%s'", outputFlag.DefValue)
+	}
+}
+
+func TestFormatExpiry(t *testing.T) {
+	tests := []struct {
+		name     string
+		expires  time.Time
+		contains string
+	}{
+		{"future", time.Now().Add(90*time.Minute + 10*time.Second), "expires in 1h30m"},
+		{"past", time.Now().Add(-5*time.Minute - 10*time.Second), "expired 5m ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatExpiry(tt.expires)
+			if !strings.Contains(result, tt.contains) {
+				t.Errorf("Expected %q to contain %q", result, tt.contains)
+			}
+		})
+	}
+}
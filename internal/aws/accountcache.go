@@ -0,0 +1,180 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// cacheRefresh tracks background updates of the account cache started while the user is
+// selecting from it
+type cacheRefresh struct {
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	err      error
+	accounts []types.AccountInfo // Fresh account list, once the accounts refresh succeeded
+}
+
+// start runs refresh in the background, keeping the first error
+func (r *cacheRefresh) start(refresh func() error) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := refresh(); err != nil {
+			r.mu.Lock()
+			if r.err == nil {
+				r.err = err
+			}
+			r.mu.Unlock()
+		}
+	}()
+}
+
+// wait blocks until every refresh finished and returns the first error
+func (r *cacheRefresh) wait() error {
+	r.wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// freshAccounts waits for the refresh and returns the accounts it fetched, or nil
+func (r *cacheRefresh) freshAccounts() []types.AccountInfo {
+	r.wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.accounts
+}
+
+// loginAccounts returns an SSO access token and the accounts to select from. While the
+// account cache is fresh and the cached token hasn't expired, the cached accounts are
+// returned straight away and refreshed in the background; the returned refresh must then be
+// passed to finishRefresh before the token is used. refresh is nil when accounts were
+// fetched live.
+func (s *SSOManager) loginAccounts(ctx context.Context, force bool) (string, []types.AccountInfo, *cacheRefresh, error) {
+	if !force {
+		if accessToken, accounts, ok := s.cachedLoginAccounts(ctx); ok {
+			refresh := &cacheRefresh{}
+			refresh.start(func() error {
+				fresh, err := s.ListAccounts(ctx, accessToken)
+				if err != nil {
+					return err
+				}
+				refresh.mu.Lock()
+				refresh.accounts = fresh
+				refresh.mu.Unlock()
+				return awscconfig.SaveAccountCache(fresh)
+			})
+			return accessToken, accounts, refresh, nil
+		}
+	}
+
+	accessToken, accounts, err := s.authenticate(ctx, force)
+	return accessToken, accounts, nil, err
+}
+
+// cachedLoginAccounts returns the cached token and accounts if both can be used without
+// contacting SSO
+func (s *SSOManager) cachedLoginAccounts(ctx context.Context) (string, []types.AccountInfo, bool) {
+	ttl := awscconfig.GetAccountCacheTTL()
+	if ttl <= 0 {
+		return "", nil, false
+	}
+
+	cache, err := awscconfig.LoadAccountCache()
+	if err != nil || !cache.IsFresh(ttl) || len(cache.Accounts) == 0 {
+		return "", nil, false
+	}
+
+	credentialsManager, err := NewCredentialsManager(ctx)
+	if err != nil {
+		return "", nil, false
+	}
+	accessToken, err := credentialsManager.GetCachedToken(ctx)
+	if err != nil {
+		return "", nil, false
+	}
+
+	debug.Printf("Using account cache fetched at %s\n", cache.FetchedAt.Local().Format("2006-01-02 15:04"))
+	return *accessToken, cache.AccountInfos(), true
+}
+
+// finishRefresh waits for the background refresh of a cached selection. If SSO rejected
+// the cached token, it refreshes the token silently and only logs in again when that fails.
+func (s *SSOManager) finishRefresh(ctx context.Context, refresh *cacheRefresh, accessToken string) (string, error) {
+	if refresh == nil {
+		return accessToken, nil
+	}
+
+	err := refresh.wait()
+	if err == nil {
+		return accessToken, nil
+	}
+	if !IsAuthError(err) {
+		debug.Printf("Warning: failed to refresh account cache: %v\n", err)
+		return accessToken, nil
+	}
+
+	credentialsManager, err := NewCredentialsManager(ctx, CredentialsManagerOptions{OIDCClient: s.oidcClient, SSOManager: s})
+	if err != nil {
+		return "", fmt.Errorf("failed to create credentials manager: %v", err)
+	}
+	refreshed, err := credentialsManager.refreshCachedToken(ctx)
+	if err == nil {
+		return *refreshed, nil
+	}
+	if !errors.Is(err, errTokenNotRefreshable) && !IsAuthError(err) {
+		return "", fmt.Errorf("failed to refresh SSO token: %v", err)
+	}
+	debug.Printf("SSO token refresh failed, logging in again: %v\n", err)
+
+	newToken, _, err := s.authenticate(ctx, true)
+	return newToken, err
+}
+
+// accountRoles returns the roles of an account. During a cached selection they come from
+// the cache and are refreshed in the background; otherwise they are listed live and cached.
+func (s *SSOManager) accountRoles(ctx context.Context, accessToken, accountID string, refresh *cacheRefresh) ([]types.RoleInfo, error) {
+	if refresh != nil {
+		if cache, err := awscconfig.LoadAccountCache(); err == nil && len(cache.Roles[accountID]) > 0 {
+			refresh.start(func() error {
+				roles, err := s.ListRoles(ctx, accessToken, accountID)
+				if err != nil {
+					return err
+				}
+				return awscconfig.SaveAccountRoles(accountID, roleNames(roles))
+			})
+
+			names := cache.Roles[accountID]
+			roles := make([]types.RoleInfo, len(names))
+			for i := range names {
+				roles[i] = types.RoleInfo{AccountId: &accountID, RoleName: &names[i]}
+			}
+			return roles, nil
+		}
+	}
+
+	roles, err := s.ListRoles(ctx, accessToken, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := awscconfig.SaveAccountRoles(accountID, roleNames(roles)); err != nil {
+		debug.Printf("Warning: failed to cache roles: %v\n", err)
+	}
+	return roles, nil
+}
+
+func roleNames(roles []types.RoleInfo) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.RoleName != nil {
+			names = append(names, *role.RoleName)
+		}
+	}
+	return names
+}
@@ -0,0 +1,258 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/spf13/viper"
+	"go.uber.org/mock/gomock"
+)
+
+// setupCachedLogin seeds a valid SSO token and a fresh account cache with the given accounts
+// (ID -> name), each with an Admin and a ReadOnly role
+func setupCachedLogin(t *testing.T, accounts map[string]string) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_PROFILE", "")
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("sso.start_url", "https://test.awsapps.com/start")
+	viper.Set("sso.region", "us-east-1")
+
+	if err := (&CredentialsManager{}).saveTokenToCache(&SSOCache{
+		AccessToken: "sso-token",
+		ExpiresAt:   time.Now().Add(time.Hour),
+		StartURL:    "https://test.awsapps.com/start",
+	}); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+
+	var infos []types.AccountInfo
+	for id, name := range accounts {
+		infos = append(infos, types.AccountInfo{AccountId: aws.String(id), AccountName: aws.String(name)})
+	}
+	if err := awscconfig.SaveAccountCache(infos); err != nil {
+		t.Fatalf("SaveAccountCache failed: %v", err)
+	}
+	for id := range accounts {
+		if err := awscconfig.SaveAccountRoles(id, []string{"Admin", "ReadOnly"}); err != nil {
+			t.Fatalf("SaveAccountRoles failed: %v", err)
+		}
+	}
+	return tempDir
+}
+
+func roleCredentialsOutput() *sso.GetRoleCredentialsOutput {
+	return &sso.GetRoleCredentialsOutput{RoleCredentials: &types.RoleCredentials{
+		AccessKeyId:     aws.String("AKIATEST"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+		Expiration:      time.Now().Add(time.Hour).UnixMilli(),
+	}}
+}
+
+func TestSSOManager_RunLogin_AccountCache(t *testing.T) {
+	tempDir := setupCachedLogin(t, map[string]string{"111111111111": "Production", "222222222222": "Staging"})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+
+	// Accounts and roles are refreshed in the background while the cached ones are used
+	mockSSO.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountsOutput{
+		AccountList: []types.AccountInfo{
+			{AccountId: aws.String("111111111111"), AccountName: aws.String("Production")},
+			{AccountId: aws.String("333333333333"), AccountName: aws.String("Sandbox")},
+		},
+	}, nil)
+	mockSSO.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountRolesOutput{
+		RoleList: []types.RoleInfo{{RoleName: aws.String("Admin")}, {RoleName: aws.String("Billing")}},
+	}, nil)
+	mockSSO.EXPECT().GetRoleCredentials(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+			if *params.AccountId != "111111111111" || *params.RoleName != "Admin" {
+				t.Errorf("Unexpected role %s/%s", *params.AccountId, *params.RoleName)
+			}
+			return roleCredentialsOutput(), nil
+		})
+
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Prefix and fuzzy matches resolve against the cached accounts and roles
+	if err := manager.RunLogin(context.Background(), false, "prod", "adm"); err != nil {
+		t.Fatalf("RunLogin failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, ".aws", "config"))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(content), "[profile awsc-Production]") {
+		t.Errorf("Expected awsc-Production profile, got:\n%s", content)
+	}
+
+	cache, err := awscconfig.LoadAccountCache()
+	if err != nil {
+		t.Fatalf("LoadAccountCache failed: %v", err)
+	}
+	if cache.Accounts["333333333333"] != "Sandbox" || cache.Accounts["222222222222"] != "" {
+		t.Errorf("Expected the background refresh to update accounts, got %v", cache.Accounts)
+	}
+	if got := strings.Join(cache.Roles["111111111111"], ","); got != "Admin,Billing" {
+		t.Errorf("Expected the background refresh to update roles, got %s", got)
+	}
+}
+
+func TestSSOManager_RunLogin_AccountCacheNewAccount(t *testing.T) {
+	setupCachedLogin(t, map[string]string{"111111111111": "Production"})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+
+	mockSSO.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountsOutput{
+		AccountList: []types.AccountInfo{
+			{AccountId: aws.String("111111111111"), AccountName: aws.String("Production")},
+			{AccountId: aws.String("333333333333"), AccountName: aws.String("Sandbox")},
+		},
+	}, nil)
+	// Roles of the new account aren't cached, so they are listed before selection
+	mockSSO.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountRolesOutput{
+		RoleList: []types.RoleInfo{{RoleName: aws.String("Developer")}},
+	}, nil)
+	mockSSO.EXPECT().GetRoleCredentials(gomock.Any(), gomock.Any(), gomock.Any()).Return(roleCredentialsOutput(), nil)
+
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Accounts missing from the cache are looked up in the refreshed list by ID
+	if err := manager.RunLogin(context.Background(), false, "333333333333", "developer"); err != nil {
+		t.Fatalf("RunLogin failed: %v", err)
+	}
+
+	session, err := awscconfig.GetCurrentSession()
+	if err != nil {
+		t.Fatalf("GetCurrentSession failed: %v", err)
+	}
+	if session.AccountName != "Sandbox" || session.RoleName != "Developer" {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+}
+
+func TestSSOManager_RunLogin_AccountCacheRejectedToken(t *testing.T) {
+	setupCachedLogin(t, map[string]string{"111111111111": "Production"})
+	if err := (&CredentialsManager{}).saveTokenToCache(&SSOCache{
+		AccessToken:           "sso-token",
+		ExpiresAt:             time.Now().Add(time.Hour),
+		RefreshToken:          "refresh-token",
+		ClientID:              "client-id",
+		ClientSecret:          "client-secret",
+		RegistrationExpiresAt: time.Now().Add(24 * time.Hour),
+		StartURL:              "https://test.awsapps.com/start",
+	}); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+	mockOIDC := mocks.NewMockOIDCClient(ctrl)
+
+	// SSO rejects the cached token during the background refresh
+	mockSSO.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, &types.UnauthorizedException{})
+	mockSSO.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, &types.UnauthorizedException{}).AnyTimes()
+	// The token is refreshed silently instead of starting an interactive login
+	mockOIDC.EXPECT().CreateToken(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+			if *input.GrantType != refreshTokenGrantType || *input.RefreshToken != "refresh-token" {
+				t.Errorf("Unexpected token request: %s", *input.GrantType)
+			}
+			return &ssooidc.CreateTokenOutput{AccessToken: aws.String("new-token"), ExpiresIn: 3600}, nil
+		})
+	mockSSO.EXPECT().GetRoleCredentials(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+			if *params.AccessToken != "new-token" {
+				t.Errorf("Expected the refreshed token, got %s", *params.AccessToken)
+			}
+			return roleCredentialsOutput(), nil
+		})
+
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO, OIDCClient: mockOIDC})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := manager.RunLogin(context.Background(), false, "production", "admin"); err != nil {
+		t.Fatalf("RunLogin failed: %v", err)
+	}
+}
+
+func TestSSOManager_RunLogin_AmbiguousAccount(t *testing.T) {
+	setupCachedLogin(t, map[string]string{"111111111111": "Production", "222222222222": "Production-EU"})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+	mockSSO.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountsOutput{}, nil)
+
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = manager.RunLogin(context.Background(), false, "prod", "")
+	if err == nil {
+		t.Fatal("Expected ambiguity error")
+	}
+	for _, expected := range []string{"'prod' is ambiguous", "Production, Production-EU", "account ID"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestSSOManager_loginAccounts_StaleCache(t *testing.T) {
+	setupCachedLogin(t, map[string]string{"111111111111": "Production"})
+	viper.Set("account_cache_ttl_minutes", 0)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+	mockSSO.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountsOutput{
+		AccountList: []types.AccountInfo{{AccountId: aws.String("111111111111"), AccountName: aws.String("Production")}},
+	}, nil)
+
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// With the cache disabled, accounts are listed before selection
+	_, accounts, refresh, err := manager.loginAccounts(context.Background(), false)
+	if err != nil {
+		t.Fatalf("loginAccounts failed: %v", err)
+	}
+	if refresh != nil {
+		t.Error("Expected no background refresh when the cache isn't used")
+	}
+	if len(accounts) != 1 {
+		t.Errorf("Expected 1 account, got %d", len(accounts))
+	}
+}
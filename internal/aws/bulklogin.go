@@ -0,0 +1,231 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+const (
+	defaultBulkConcurrency = 5
+	maxThrottleRetries     = 5
+)
+
+// throttleBaseDelay is the initial backoff after an SSO throttling error (variable for tests)
+var throttleBaseDelay = 500 * time.Millisecond
+
+// BulkLoginOptions controls which accounts and roles `awsc login --all` writes profiles for
+type BulkLoginOptions struct {
+	IncludeAccounts string // Regex matched against account names
+	ExcludeAccounts string // Regex matched against account names
+	RoleName        string // Only write profiles for this role (case-insensitive)
+	Concurrency     int
+}
+
+type bulkProfileResult struct {
+	entry awscconfig.ProfileEntry
+	err   error
+}
+
+// RunLoginAll authenticates once and writes a profile for every matching account/role pair
+func (s *SSOManager) RunLoginAll(ctx context.Context, force bool, opts BulkLoginOptions) error {
+	include, exclude, err := compileAccountFilters(opts)
+	if err != nil {
+		return err
+	}
+
+	accessToken, accounts, err := s.authenticate(ctx, force)
+	if err != nil {
+		return err
+	}
+
+	accounts = filterAccounts(accounts, include, exclude)
+	if len(accounts) == 0 {
+		return fmt.Errorf("no accounts match the include/exclude filters")
+	}
+
+	fmt.Printf("Writing profiles for %d accounts...\n", len(accounts))
+
+	results := s.collectProfiles(ctx, accessToken, accounts, opts)
+
+	var entries []awscconfig.ProfileEntry
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+			fmt.Printf("Warning: %v\n", result.err)
+			continue
+		}
+		entries = append(entries, result.entry)
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no profiles to write")
+	}
+
+	// Write every profile in one update so a failure can't leave a half-written config
+	if err := awscconfig.WriteProfiles(entries); err != nil {
+		return fmt.Errorf("error writing profiles: %v", err)
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("✓ %s\n", entry.ProfileName)
+	}
+	fmt.Printf("\nWrote %d profiles", len(entries))
+	if failed > 0 {
+		fmt.Printf(" (%d failed)", failed)
+	}
+	fmt.Printf("\n")
+	return nil
+}
+
+// collectProfiles lists roles and fetches credentials for each account with bounded concurrency
+func (s *SSOManager) collectProfiles(ctx context.Context, accessToken string, accounts []types.AccountInfo, opts BulkLoginOptions) []bulkProfileResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	// Credentials are only needed when they are written into the profile
+	needCreds := awscconfig.GetProfileType() != awscconfig.ProfileTypeSSO
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []bulkProfileResult
+	)
+	sem := make(chan struct{}, concurrency)
+
+	addResult := func(result bulkProfileResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	}
+
+	for _, account := range accounts {
+		wg.Add(1)
+		go func(account types.AccountInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			accountName := *account.AccountName
+			accountID := *account.AccountId
+
+			var roles []types.RoleInfo
+			err := withThrottleRetry(ctx, func() error {
+				var listErr error
+				roles, listErr = s.ListRoles(ctx, accessToken, accountID)
+				return listErr
+			})
+			if err != nil {
+				addResult(bulkProfileResult{err: fmt.Errorf("error listing roles for %s: %v", accountName, err)})
+				return
+			}
+
+			for _, role := range roles {
+				roleName := *role.RoleName
+				if opts.RoleName != "" && !strings.EqualFold(roleName, opts.RoleName) {
+					continue
+				}
+
+				entry := awscconfig.ProfileEntry{
+					ProfileName: fmt.Sprintf("awsc-%s-%s", accountName, roleName),
+					AccountName: accountName,
+					AccountID:   accountID,
+					RoleName:    roleName,
+				}
+
+				if needCreds {
+					var creds *types.RoleCredentials
+					err := withThrottleRetry(ctx, func() error {
+						var credErr error
+						creds, credErr = s.GetRoleCredentials(ctx, accessToken, accountID, roleName)
+						return credErr
+					})
+					if err != nil {
+						addResult(bulkProfileResult{err: fmt.Errorf("error getting credentials for %s/%s: %v", accountName, roleName, err)})
+						continue
+					}
+					entry.Creds = creds
+					if awscconfig.GetProfileType() == awscconfig.ProfileTypeCredentialProcess {
+						cacheRoleCredentials(accountID, roleName, creds)
+					}
+				}
+
+				addResult(bulkProfileResult{entry: entry})
+			}
+		}(account)
+	}
+	wg.Wait()
+
+	// Sort for stable output regardless of completion order
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].entry.ProfileName < results[j].entry.ProfileName
+	})
+	return results
+}
+
+// withThrottleRetry retries fn with exponential backoff while SSO reports throttling
+func withThrottleRetry(ctx context.Context, fn func() error) error {
+	delay := throttleBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isThrottleError(err) || attempt >= maxThrottleRetries {
+			return err
+		}
+
+		debug.Printf("SSO throttled, retrying in %v", delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func isThrottleError(err error) bool {
+	var tooMany *types.TooManyRequestsException
+	return errors.As(err, &tooMany)
+}
+
+func compileAccountFilters(opts BulkLoginOptions) (*regexp.Regexp, *regexp.Regexp, error) {
+	var include, exclude *regexp.Regexp
+	var err error
+	if opts.IncludeAccounts != "" {
+		if include, err = regexp.Compile(opts.IncludeAccounts); err != nil {
+			return nil, nil, fmt.Errorf("invalid --include pattern: %v", err)
+		}
+	}
+	if opts.ExcludeAccounts != "" {
+		if exclude, err = regexp.Compile(opts.ExcludeAccounts); err != nil {
+			return nil, nil, fmt.Errorf("invalid --exclude pattern: %v", err)
+		}
+	}
+	return include, exclude, nil
+}
+
+func filterAccounts(accounts []types.AccountInfo, include, exclude *regexp.Regexp) []types.AccountInfo {
+	var filtered []types.AccountInfo
+	for _, account := range accounts {
+		name := *account.AccountName
+		if include != nil && !include.MatchString(name) {
+			continue
+		}
+		if exclude != nil && exclude.MatchString(name) {
+			continue
+		}
+		filtered = append(filtered, account)
+	}
+	return filtered
+}
@@ -0,0 +1,167 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"github.com/spf13/viper"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFilterAccounts(t *testing.T) {
+	accounts := []types.AccountInfo{
+		{AccountId: aws.String("111111111111"), AccountName: aws.String("prod-web")},
+		{AccountId: aws.String("222222222222"), AccountName: aws.String("prod-data")},
+		{AccountId: aws.String("333333333333"), AccountName: aws.String("dev-web")},
+	}
+
+	tests := []struct {
+		name     string
+		include  string
+		exclude  string
+		expected []string
+	}{
+		{"no filters", "", "", []string{"prod-web", "prod-data", "dev-web"}},
+		{"include only", "^prod-", "", []string{"prod-web", "prod-data"}},
+		{"exclude only", "", "data", []string{"prod-web", "dev-web"}},
+		{"include and exclude", "^prod-", "data", []string{"prod-web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var include, exclude *regexp.Regexp
+			if tt.include != "" {
+				include = regexp.MustCompile(tt.include)
+			}
+			if tt.exclude != "" {
+				exclude = regexp.MustCompile(tt.exclude)
+			}
+
+			filtered := filterAccounts(accounts, include, exclude)
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("Expected %d accounts, got %d", len(tt.expected), len(filtered))
+			}
+			for i, account := range filtered {
+				if *account.AccountName != tt.expected[i] {
+					t.Errorf("Expected account %s, got %s", tt.expected[i], *account.AccountName)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileAccountFilters_InvalidPattern(t *testing.T) {
+	if _, _, err := compileAccountFilters(BulkLoginOptions{IncludeAccounts: "("}); err == nil {
+		t.Error("Expected error for invalid include pattern")
+	}
+	if _, _, err := compileAccountFilters(BulkLoginOptions{ExcludeAccounts: "["}); err == nil {
+		t.Error("Expected error for invalid exclude pattern")
+	}
+}
+
+func TestWithThrottleRetry(t *testing.T) {
+	originalDelay := throttleBaseDelay
+	throttleBaseDelay = time.Millisecond
+	defer func() { throttleBaseDelay = originalDelay }()
+
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectError   bool
+	}{
+		{"success first try", []error{nil}, 1, false},
+		{"throttled then success", []error{&types.TooManyRequestsException{}, &types.TooManyRequestsException{}, nil}, 3, false},
+		{"non-throttle error not retried", []error{fmt.Errorf("access denied")}, 1, true},
+		{"gives up after max retries", []error{
+			&types.TooManyRequestsException{}, &types.TooManyRequestsException{}, &types.TooManyRequestsException{},
+			&types.TooManyRequestsException{}, &types.TooManyRequestsException{}, &types.TooManyRequestsException{},
+		}, maxThrottleRetries + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := withThrottleRetry(context.Background(), func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+			if (err != nil) != tt.expectError {
+				t.Errorf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestSSOManager_collectProfiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	defer viper.Reset()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	accounts := []types.AccountInfo{
+		{AccountId: aws.String("111111111111"), AccountName: aws.String("prod")},
+		{AccountId: aws.String("222222222222"), AccountName: aws.String("dev")},
+	}
+
+	mockSSO.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *sso.ListAccountRolesInput, optFns ...func(*sso.Options)) (*sso.ListAccountRolesOutput, error) {
+			if *params.AccountId == "222222222222" {
+				return nil, fmt.Errorf("access denied")
+			}
+			return &sso.ListAccountRolesOutput{RoleList: []types.RoleInfo{
+				{RoleName: aws.String("Admin"), AccountId: params.AccountId},
+				{RoleName: aws.String("ReadOnly"), AccountId: params.AccountId},
+			}}, nil
+		}).Times(2)
+
+	mockSSO.EXPECT().GetRoleCredentials(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.GetRoleCredentialsOutput{
+		RoleCredentials: &types.RoleCredentials{
+			AccessKeyId:     aws.String("AKIATEST"),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("token"),
+			Expiration:      time.Now().Add(time.Hour).UnixMilli(),
+		},
+	}, nil).Times(1)
+
+	results := manager.collectProfiles(context.Background(), "token", accounts, BulkLoginOptions{RoleName: "readonly", Concurrency: 2})
+
+	var profiles []string
+	failures := 0
+	for _, result := range results {
+		if result.err != nil {
+			failures++
+			continue
+		}
+		profiles = append(profiles, result.entry.ProfileName)
+		if result.entry.Creds == nil {
+			t.Errorf("Expected credentials for static profile %s", result.entry.ProfileName)
+		}
+	}
+
+	if failures != 1 {
+		t.Errorf("Expected 1 failure, got %d", failures)
+	}
+	if len(profiles) != 1 || profiles[0] != "awsc-prod-ReadOnly" {
+		t.Errorf("Expected [awsc-prod-ReadOnly], got %v", profiles)
+	}
+}
@@ -0,0 +1,159 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// RunLoginChain logs in to the chain's source SSO role, assumes the chain's role with STS
+// and writes its profile and session like a normal login
+func (s *SSOManager) RunLoginChain(ctx context.Context, force bool, chainName string) error {
+	chain, err := awscconfig.GetChain(chainName)
+	if err != nil {
+		return err
+	}
+	sourceAccount, sourceRole, _ := chain.Source()
+
+	accessToken, accounts, err := s.authenticate(ctx, force)
+	if err != nil {
+		return err
+	}
+
+	account := findAccount(accounts, sourceAccount)
+	if account == nil {
+		return fmt.Errorf("source account '%s' for chain '%s' not found", sourceAccount, chainName)
+	}
+
+	// Role names are case-sensitive for GetRoleCredentials, so resolve the exact name
+	roles, err := s.ListRoles(ctx, accessToken, *account.AccountId)
+	if err != nil {
+		return fmt.Errorf("error listing roles: %v", err)
+	}
+	roleName := ""
+	for _, role := range roles {
+		if strings.EqualFold(*role.RoleName, sourceRole) {
+			roleName = *role.RoleName
+			break
+		}
+	}
+	if roleName == "" {
+		return fmt.Errorf("source role '%s' for chain '%s' not found in account %s", sourceRole, chainName, *account.AccountName)
+	}
+	fmt.Fprintf(os.Stderr, "✓ Selected: %s / %s\n", *account.AccountName, roleName)
+
+	sourceCreds, err := s.GetRoleCredentials(ctx, accessToken, *account.AccountId, roleName)
+	if err != nil {
+		return fmt.Errorf("error getting role credentials: %v", err)
+	}
+
+	stsClient, err := s.chainSTSClient(ctx, sourceCreds)
+	if err != nil {
+		return err
+	}
+
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(chain.RoleARN),
+		RoleSessionName: aws.String(chain.SessionName),
+	}
+	if chain.ExternalID != "" {
+		input.ExternalId = aws.String(chain.ExternalID)
+	}
+
+	result, err := stsClient.AssumeRole(ctx, input)
+	if err != nil {
+		return fmt.Errorf("error assuming role %s: %v", chain.RoleARN, err)
+	}
+	fmt.Fprintf(os.Stderr, "✓ Assumed: %s\n", chain.RoleARN)
+
+	accountID, chainRole := parseRoleARN(chain.RoleARN)
+	accountName := awscconfig.GetAccountName(accountID)
+
+	creds := &types.RoleCredentials{
+		AccessKeyId:     result.Credentials.AccessKeyId,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+	}
+	var expiration time.Time
+	if result.Credentials.Expiration != nil {
+		expiration = *result.Credentials.Expiration
+		creds.Expiration = expiration.UnixMilli()
+	}
+
+	if awscconfig.IsEncryptedCredentialStore() {
+		// The profile reads the credentials back from the store through credential-process
+		if err := awscconfig.SaveCachedChainCredentials(chainName, &awscconfig.CachedCredentials{
+			AccessKeyID:     aws.ToString(creds.AccessKeyId),
+			SecretAccessKey: aws.ToString(creds.SecretAccessKey),
+			SessionToken:    aws.ToString(creds.SessionToken),
+			Expiration:      expiration,
+		}); err != nil {
+			return fmt.Errorf("error caching chain credentials: %v", err)
+		}
+	}
+
+	profileName, err := awscconfig.WriteChainProfile(chainName, accountName, accountID, chainRole, creds)
+	if err != nil {
+		return fmt.Errorf("error writing profile: %v", err)
+	}
+
+	return saveLoginSession(profileName, accountID, accountName, chainRole, expiration)
+}
+
+// chainSTSClient returns an STS client that calls AssumeRole with the source role credentials
+func (s *SSOManager) chainSTSClient(ctx context.Context, sourceCreds *types.RoleCredentials) (STSClient, error) {
+	if s.stsClient != nil {
+		return s.stsClient, nil
+	}
+
+	cfg, err := awscconfig.LoadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Credentials = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{
+			AccessKeyID:     aws.ToString(sourceCreds.AccessKeyId),
+			SecretAccessKey: aws.ToString(sourceCreds.SecretAccessKey),
+			SessionToken:    aws.ToString(sourceCreds.SessionToken),
+			Source:          "awsc",
+			CanExpire:       true,
+			Expires:         time.UnixMilli(sourceCreds.Expiration),
+		}, nil
+	})
+
+	return sts.NewFromConfig(cfg), nil
+}
+
+// findAccount matches an account by name (case-insensitive) or ID
+func findAccount(accounts []types.AccountInfo, nameOrID string) *types.AccountInfo {
+	for i, account := range accounts {
+		if strings.EqualFold(*account.AccountName, nameOrID) || *account.AccountId == nameOrID {
+			return &accounts[i]
+		}
+	}
+	return nil
+}
+
+// parseRoleARN returns the account ID and role name of an IAM role ARN
+// (e.g. arn:aws:iam::123456789012:role/path/db-admin)
+func parseRoleARN(roleARN string) (string, string) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", roleARN
+	}
+
+	resource := parsed.Resource
+	if i := strings.LastIndex(resource, "/"); i >= 0 {
+		resource = resource[i+1:]
+	}
+	return parsed.AccountID, resource
+}
@@ -0,0 +1,138 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/spf13/viper"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseRoleARN(t *testing.T) {
+	tests := []struct {
+		arn             string
+		expectedAccount string
+		expectedRole    string
+	}{
+		{"arn:aws:iam::123456789012:role/db-admin", "123456789012", "db-admin"},
+		{"arn:aws:iam::123456789012:role/path/to/db-admin", "123456789012", "db-admin"},
+		{"not-an-arn", "", "not-an-arn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			account, role := parseRoleARN(tt.arn)
+			if account != tt.expectedAccount || role != tt.expectedRole {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.expectedAccount, tt.expectedRole, account, role)
+			}
+		})
+	}
+}
+
+func TestSSOManager_RunLoginChain(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_PROFILE", "")
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sso.start_url", "https://test.awsapps.com/start")
+	viper.Set("sso.region", "us-east-1")
+	viper.Set("chains", map[string]interface{}{
+		"prod-db": map[string]interface{}{
+			"from":         "production/dataaccess",
+			"role_arn":     "arn:aws:iam::999999999999:role/db-admin",
+			"external_id":  "ext-123",
+			"session_name": "alice",
+		},
+	})
+
+	// Seed a valid SSO token so no browser flow is needed
+	if err := (&CredentialsManager{}).saveTokenToCache(&SSOCache{
+		AccessToken: "sso-token",
+		ExpiresAt:   time.Now().Add(time.Hour),
+		StartURL:    "https://test.awsapps.com/start",
+	}); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSSO := mocks.NewMockSSOClient(ctrl)
+	mockSTS := mocks.NewMockSTSClient(ctrl)
+
+	mockSSO.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountsOutput{
+		AccountList: []types.AccountInfo{{AccountId: aws.String("111111111111"), AccountName: aws.String("Production")}},
+	}, nil)
+	mockSSO.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.ListAccountRolesOutput{
+		RoleList: []types.RoleInfo{{RoleName: aws.String("DataAccess")}},
+	}, nil)
+	mockSSO.EXPECT().GetRoleCredentials(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+			if *params.RoleName != "DataAccess" || *params.AccountId != "111111111111" {
+				t.Errorf("Unexpected source role %s/%s", *params.AccountId, *params.RoleName)
+			}
+			return &sso.GetRoleCredentialsOutput{RoleCredentials: &types.RoleCredentials{
+				AccessKeyId:     aws.String("SOURCEKEY"),
+				SecretAccessKey: aws.String("source-secret"),
+				SessionToken:    aws.String("source-token"),
+				Expiration:      time.Now().Add(time.Hour).UnixMilli(),
+			}}, nil
+		})
+
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	mockSTS.EXPECT().AssumeRole(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+			if *params.RoleArn != "arn:aws:iam::999999999999:role/db-admin" || *params.ExternalId != "ext-123" || *params.RoleSessionName != "alice" {
+				t.Errorf("Unexpected AssumeRole input: %+v", params)
+			}
+			return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+				AccessKeyId:     aws.String("CHAINEDKEY"),
+				SecretAccessKey: aws.String("chained-secret"),
+				SessionToken:    aws.String("chained-token"),
+				Expiration:      &expiration,
+			}}, nil
+		})
+
+	manager, err := NewSSOManager(context.Background(), SSOManagerOptions{Client: mockSSO, STSClient: mockSTS})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := manager.RunLoginChain(context.Background(), false, "prod-db"); err != nil {
+		t.Fatalf("RunLoginChain failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, ".aws", "config"))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	for _, expected := range []string{"[profile awsc-prod-db]", "# Chain: prod-db", "# Role: db-admin", "aws_access_key_id = CHAINEDKEY"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Config missing %q:\n%s", expected, string(content))
+		}
+	}
+
+	session, err := awscconfig.GetCurrentSession()
+	if err != nil {
+		t.Fatalf("GetCurrentSession failed: %v", err)
+	}
+	if session.ProfileName != "awsc-prod-db" || session.AccountID != "999999999999" || session.RoleName != "db-admin" {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+	if !session.Expiration.Equal(expiration) {
+		t.Errorf("Expected session expiration %v, got %v", expiration, session.Expiration)
+	}
+}
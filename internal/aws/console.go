@@ -0,0 +1,178 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// consoleServicePattern matches console service paths such as "rds" or "cloudwatch"
+var consoleServicePattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+type ConsoleManager struct {
+	httpClient  *http.Client
+	credentials aws.CredentialsProvider
+	region      string
+}
+
+type ConsoleManagerOptions struct {
+	HTTPClient  *http.Client
+	Credentials aws.CredentialsProvider
+	Region      string
+}
+
+func NewConsoleManager(ctx context.Context, opts ...ConsoleManagerOptions) (*ConsoleManager, error) {
+	if len(opts) > 0 && opts[0].Credentials != nil {
+		// Use provided credentials (for testing)
+		httpClient := opts[0].HTTPClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		return &ConsoleManager{
+			httpClient:  httpClient,
+			credentials: opts[0].Credentials,
+			region:      opts[0].Region,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := loadAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsoleManager{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		credentials: cfg.Credentials,
+		region:      cfg.Region,
+	}, nil
+}
+
+// RunConsole signs in to the AWS Management Console with the session's credentials and
+// opens it in the browser, or prints the sign-in URL to stdout when printOnly is set
+func (c *ConsoleManager) RunConsole(ctx context.Context, service string, printOnly bool) error {
+	loginURL, err := c.GetConsoleLoginURL(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		// stdout carries only the URL
+		fmt.Println(loginURL)
+		return nil
+	}
+
+	fmt.Printf("Opening AWS console in browser...\n")
+	if err := browserOpener(loginURL); err != nil {
+		return fmt.Errorf("failed to open browser: %v (use --print to get the URL instead)", err)
+	}
+	return nil
+}
+
+// GetConsoleLoginURL exchanges the session's credentials for a sign-in token at the
+// federation endpoint and returns the console login URL for service (empty for the home page)
+func (c *ConsoleManager) GetConsoleLoginURL(ctx context.Context, service string) (string, error) {
+	if service != "" && !consoleServicePattern.MatchString(service) {
+		return "", fmt.Errorf("invalid service '%s' (expected a console path such as rds or ec2)", service)
+	}
+
+	// The sign-in and console hosts differ between partitions
+	endpoint, err := awscconfig.GetFederationEndpoint(c.region)
+	if err != nil {
+		return "", err
+	}
+	consoleURL, err := awscconfig.GetConsoleURL(c.region)
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", err
+	}
+	if creds.SessionToken == "" {
+		return "", fmt.Errorf("console sign-in requires temporary credentials - the active profile has long-term access keys")
+	}
+
+	signinToken, err := c.getSigninToken(ctx, endpoint, creds)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("Action", "login")
+	params.Set("Issuer", "awsc")
+	params.Set("Destination", c.destinationURL(consoleURL, service))
+	params.Set("SigninToken", signinToken)
+	return endpoint + "?" + params.Encode(), nil
+}
+
+// getSigninToken calls the federation endpoint's getSigninToken action
+func (c *ConsoleManager) getSigninToken(ctx context.Context, endpoint string, creds aws.Credentials) (string, error) {
+	session, err := json.Marshal(map[string]string{
+		"sessionId":    creds.AccessKeyID,
+		"sessionKey":   creds.SecretAccessKey,
+		"sessionToken": creds.SessionToken,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("Action", "getSigninToken")
+	params.Set("Session", string(session))
+
+	debug.Printf("Requesting sign-in token from %s", endpoint)
+
+	// The session goes in the body so credentials don't end up in proxy or server logs
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach federation endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read federation response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// The endpoint answers expired or invalid credentials with a bare 400
+		return "", fmt.Errorf("federation sign-in failed with status %d - credentials may be expired, run 'awsc login'", resp.StatusCode)
+	}
+
+	var result struct {
+		SigninToken string `json:"SigninToken"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.SigninToken == "" {
+		return "", fmt.Errorf("unexpected federation response")
+	}
+	return result.SigninToken, nil
+}
+
+// destinationURL returns the console page to open after sign-in
+func (c *ConsoleManager) destinationURL(consoleURL, service string) string {
+	if service == "" {
+		service = "console"
+	}
+	destination := fmt.Sprintf("%s/%s/home", strings.TrimSuffix(consoleURL, "/"), service)
+	if c.region != "" {
+		destination += "?region=" + url.QueryEscape(c.region)
+	}
+	return destination
+}
@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/spf13/viper"
+)
+
+// newFederationStub serves getSigninToken, recording the session it was given
+func newFederationStub(t *testing.T, status int) (*httptest.Server, *map[string]string) {
+	session := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("Action") != "getSigninToken" {
+			t.Errorf("Unexpected federation request: %v", r.Form)
+		}
+		json.Unmarshal([]byte(r.PostForm.Get("Session")), &session)
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"SigninToken":"TOKEN123"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &session
+}
+
+func TestConsoleManager_GetConsoleLoginURL(t *testing.T) {
+	tests := []struct {
+		name                string
+		service             string
+		sessionToken        string
+		status              int
+		expectError         bool
+		expectedDestination string
+	}{
+		{"console home", "", "token", http.StatusOK, false, "https://console.aws.amazon.com/console/home?region=eu-west-1"},
+		{"service deep link", "rds", "token", http.StatusOK, false, "https://console.aws.amazon.com/rds/home?region=eu-west-1"},
+		{"invalid service", "rds/../iam", "token", http.StatusOK, true, ""},
+		{"long-term credentials", "", "", http.StatusOK, true, ""},
+		{"rejected credentials", "", "token", http.StatusBadRequest, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, session := newFederationStub(t, tt.status)
+			viper.Set("console.federation_endpoint", server.URL)
+			defer viper.Set("console.federation_endpoint", "")
+
+			manager, err := NewConsoleManager(context.Background(), ConsoleManagerOptions{
+				HTTPClient:  server.Client(),
+				Credentials: credentials.NewStaticCredentialsProvider("AKIATEST", "secret", tt.sessionToken),
+				Region:      "eu-west-1",
+			})
+			if err != nil {
+				t.Fatalf("Failed to create manager: %v", err)
+			}
+
+			loginURL, err := manager.GetConsoleLoginURL(context.Background(), tt.service)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if tt.expectError {
+				return
+			}
+
+			if (*session)["sessionId"] != "AKIATEST" || (*session)["sessionToken"] != "token" {
+				t.Errorf("Unexpected session sent to federation endpoint: %v", *session)
+			}
+
+			parsed, err := url.Parse(loginURL)
+			if err != nil {
+				t.Fatalf("Invalid login URL %q: %v", loginURL, err)
+			}
+			if !strings.HasPrefix(loginURL, server.URL+"?") {
+				t.Errorf("Expected login URL on the configured endpoint, got %s", loginURL)
+			}
+			query := parsed.Query()
+			if query.Get("Action") != "login" || query.Get("SigninToken") != "TOKEN123" {
+				t.Errorf("Unexpected login parameters: %v", query)
+			}
+			if query.Get("Destination") != tt.expectedDestination {
+				t.Errorf("Expected destination %s, got %s", tt.expectedDestination, query.Get("Destination"))
+			}
+		})
+	}
+}
+
+func TestConsoleManager_GetConsoleLoginURL_Partition(t *testing.T) {
+	server, _ := newFederationStub(t, http.StatusOK)
+	viper.Set("console.federation_endpoint", server.URL)
+	defer viper.Set("console.federation_endpoint", "")
+
+	manager, err := NewConsoleManager(context.Background(), ConsoleManagerOptions{
+		HTTPClient:  server.Client(),
+		Credentials: credentials.NewStaticCredentialsProvider("AKIATEST", "secret", "token"),
+		Region:      "us-gov-west-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	loginURL, err := manager.GetConsoleLoginURL(context.Background(), "ec2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err := url.Parse(loginURL)
+	if err != nil {
+		t.Fatalf("Invalid login URL %q: %v", loginURL, err)
+	}
+
+	// The destination follows the session region's partition
+	expected := "https://console.amazonaws-us-gov.com/ec2/home?region=us-gov-west-1"
+	if destination := parsed.Query().Get("Destination"); destination != expected {
+		t.Errorf("Expected destination %s, got %s", expected, destination)
+	}
+}
@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// CredentialProcessOutput is the JSON document the AWS SDKs expect from a credential_process
+type CredentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+// GetCredentialProcessOutput returns credentials for the account and role, served from the
+// local cache until shortly before they expire. It never prompts, so it is safe to run from SDKs.
+func (s *SSOManager) GetCredentialProcessOutput(ctx context.Context, accountID, roleName string) (*CredentialProcessOutput, error) {
+	if cached, err := awscconfig.LoadCachedCredentials(accountID, roleName); err == nil {
+		debug.Printf("Using cached credentials for %s/%s\n", accountID, roleName)
+		return newCredentialProcessOutput(cached), nil
+	}
+
+	credentialsManager, err := NewCredentialsManager(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credentials manager: %v", err)
+	}
+
+	accessToken, err := credentialsManager.GetCachedToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("no valid SSO session, please run 'awsc login': %v", err)
+	}
+
+	creds, err := s.GetRoleCredentials(ctx, *accessToken, accountID, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting role credentials: %v", err)
+	}
+
+	cached := cacheRoleCredentials(accountID, roleName, creds)
+	return newCredentialProcessOutput(cached), nil
+}
+
+// GetChainCredentialProcessOutput returns the cached credentials of a role chain. Assuming
+// the chain again may need MFA or a new SSO login, so expired credentials are an error.
+func GetChainCredentialProcessOutput(chainName string) (*CredentialProcessOutput, error) {
+	cached, err := awscconfig.LoadCachedChainCredentials(chainName)
+	if err != nil {
+		return nil, fmt.Errorf("no valid credentials for chain '%s', please run 'awsc login --chain %s': %v", chainName, chainName, err)
+	}
+	return newCredentialProcessOutput(cached), nil
+}
+
+// cacheRoleCredentials stores role credentials for credential_process use (best effort)
+func cacheRoleCredentials(accountID, roleName string, creds *types.RoleCredentials) *awscconfig.CachedCredentials {
+	cached := &awscconfig.CachedCredentials{
+		AccessKeyID:     *creds.AccessKeyId,
+		SecretAccessKey: *creds.SecretAccessKey,
+		SessionToken:    *creds.SessionToken,
+		Expiration:      time.UnixMilli(creds.Expiration),
+	}
+
+	if err := awscconfig.SaveCachedCredentials(accountID, roleName, cached); err != nil {
+		debug.Printf("Warning: failed to cache credentials: %v\n", err)
+	}
+
+	return cached
+}
+
+func newCredentialProcessOutput(creds *awscconfig.CachedCredentials) *CredentialProcessOutput {
+	return &CredentialProcessOutput{
+		Version:         1,
+		AccessKeyId:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expiration:      creds.Expiration.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+func TestNewCredentialProcessOutput(t *testing.T) {
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	output := newCredentialProcessOutput(&awscconfig.CachedCredentials{
+		AccessKeyID:     "AKIATEST",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      expiration,
+	})
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		t.Fatalf("Failed to marshal output: %v", err)
+	}
+
+	expected := `{"Version":1,"AccessKeyId":"AKIATEST","SecretAccessKey":"secret","SessionToken":"token","Expiration":"2030-01-02T03:04:05Z"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(data))
+	}
+}
+
+func TestGetChainCredentialProcessOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := GetChainCredentialProcessOutput("prod-admin"); err == nil || !strings.Contains(err.Error(), "awsc login --chain prod-admin") {
+		t.Errorf("Expected login hint for missing chain credentials, got %v", err)
+	}
+
+	if err := awscconfig.SaveCachedChainCredentials("prod-admin", &awscconfig.CachedCredentials{
+		AccessKeyID:     "AKIACHAIN",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("SaveCachedChainCredentials failed: %v", err)
+	}
+
+	output, err := GetChainCredentialProcessOutput("prod-admin")
+	if err != nil {
+		t.Fatalf("GetChainCredentialProcessOutput failed: %v", err)
+	}
+	if output.AccessKeyId != "AKIACHAIN" {
+		t.Errorf("Expected AKIACHAIN, got %s", output.AccessKeyId)
+	}
+}
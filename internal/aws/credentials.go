@@ -5,8 +5,9 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/json"
+	"errors"
 	"fmt"
-
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,150 +16,438 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
 	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/smithy-go"
 	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/spf13/viper"
 )
 
+// OIDCClient interface for mocking
+type OIDCClient interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
 type CredentialsManager struct {
-	oidcClient *ssooidc.Client
+	oidcClient OIDCClient
 	ssoManager *SSOManager
 }
 
+type CredentialsManagerOptions struct {
+	OIDCClient OIDCClient
+	SSOManager *SSOManager
+}
+
+// SSOCache is the SSO token cache entry, stored in the botocore format shared with the AWS CLI v2
 type SSOCache struct {
-	AccessToken string    `json:"accessToken"`
-	ExpiresAt   time.Time `json:"expiresAt"`
-	Region      string    `json:"region"`
-	StartURL    string    `json:"startUrl"`
+	AccessToken           string
+	ExpiresAt             time.Time
+	RefreshToken          string
+	ClientID              string
+	ClientSecret          string
+	RegistrationExpiresAt time.Time
+	Region                string
+	StartURL              string
+	LoginMethod           string // Login method the client registration was created for
+}
+
+// ssoCacheFile is the on-disk JSON layout used by botocore and the AWS SDKs
+type ssoCacheFile struct {
+	StartURL              string `json:"startUrl"`
+	Region                string `json:"region"`
+	AccessToken           string `json:"accessToken"`
+	ExpiresAt             string `json:"expiresAt"`
+	ClientID              string `json:"clientId,omitempty"`
+	ClientSecret          string `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt string `json:"registrationExpiresAt,omitempty"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	LoginMethod           string `json:"loginMethod,omitempty"` // awsc only, ignored by other tools
+}
+
+// legacyCacheTimeFormat is the timestamp format written by older AWS CLI versions
+const legacyCacheTimeFormat = "2006-01-02T15:04:05UTC"
+
+func (c SSOCache) MarshalJSON() ([]byte, error) {
+	file := ssoCacheFile{
+		StartURL:     c.StartURL,
+		Region:       c.Region,
+		AccessToken:  c.AccessToken,
+		ExpiresAt:    formatCacheTime(c.ExpiresAt),
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RefreshToken: c.RefreshToken,
+		LoginMethod:  c.LoginMethod,
+	}
+	if !c.RegistrationExpiresAt.IsZero() {
+		file.RegistrationExpiresAt = formatCacheTime(c.RegistrationExpiresAt)
+	}
+	return json.Marshal(file)
 }
 
-func NewCredentialsManager(ctx context.Context) (*CredentialsManager, error) {
-	cfg, err := awscconfig.LoadAWSConfig(ctx)
+func (c *SSOCache) UnmarshalJSON(data []byte) error {
+	var file ssoCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	expiresAt, err := parseCacheTime(file.ExpiresAt)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("invalid expiresAt: %w", err)
+	}
+	registrationExpiresAt, err := parseCacheTime(file.RegistrationExpiresAt)
+	if err != nil {
+		return fmt.Errorf("invalid registrationExpiresAt: %w", err)
 	}
 
-	ssoManager, err := NewSSOManager(ctx)
+	*c = SSOCache{
+		AccessToken:           file.AccessToken,
+		ExpiresAt:             expiresAt,
+		RefreshToken:          file.RefreshToken,
+		ClientID:              file.ClientID,
+		ClientSecret:          file.ClientSecret,
+		RegistrationExpiresAt: registrationExpiresAt,
+		Region:                file.Region,
+		StartURL:              file.StartURL,
+		LoginMethod:           file.LoginMethod,
+	}
+	return nil
+}
+
+func formatCacheTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseCacheTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(legacyCacheTimeFormat, value)
+}
+
+const (
+	deviceCodeGrantType        = "urn:ietf:params:oauth:grant-type:device_code"
+	authorizationCodeGrantType = "authorization_code"
+	refreshTokenGrantType      = "refresh_token"
+
+	// pkceRedirectURI is registered for the PKCE flow; the port is chosen at login time,
+	// which loopback redirects allow (RFC 8252)
+	pkceRedirectURI = "http://127.0.0.1/oauth/callback"
+)
+
+func NewCredentialsManager(ctx context.Context, opts ...CredentialsManagerOptions) (*CredentialsManager, error) {
+	if len(opts) > 0 && opts[0].OIDCClient != nil {
+		// Use provided clients (for testing)
+		return &CredentialsManager{
+			oidcClient: opts[0].OIDCClient,
+			ssoManager: opts[0].SSOManager,
+		}, nil
+	}
+
+	cfg, err := awscconfig.LoadSSOConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var ssoManager *SSOManager
+	if len(opts) > 0 && opts[0].SSOManager != nil {
+		ssoManager = opts[0].SSOManager
+	} else {
+		ssoManager, err = NewSSOManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &CredentialsManager{
 		oidcClient: ssooidc.NewFromConfig(cfg),
 		ssoManager: ssoManager,
 	}, nil
 }
 
-// IsAuthError checks if an error is related to authentication/credentials
+// authErrorCodes are the service error codes meaning the caller's credentials or SSO token
+// are missing, expired or invalid. Permission errors (AccessDenied, UnauthorizedOperation)
+// are deliberately absent: logging in again doesn't fix them.
+var authErrorCodes = map[string]bool{
+	"AuthFailure":                 true, // EC2
+	"ExpiredToken":                true, // Query protocol services (RDS, EC2, STS)
+	"ExpiredTokenException":       true, // JSON protocol services and SSO OIDC
+	"IncompleteSignature":         true,
+	"InvalidAccessKeyId":          true,
+	"InvalidClientTokenId":        true,
+	"InvalidGrantException":       true, // SSO OIDC refresh token rejected
+	"InvalidSignatureException":   true,
+	"InvalidToken":                true,
+	"MissingAuthenticationToken":  true,
+	"SignatureDoesNotMatch":       true,
+	"TokenRefreshRequired":        true,
+	"UnauthorizedClientException": true, // SSO OIDC client registration expired
+	"UnauthorizedException":       true, // SSO portal access token expired
+	"UnrecognizedClientException": true,
+}
+
+// IsAuthError checks if an error means awsc needs to log in again: no active session,
+// a missing profile, a credential provider failure, or a service rejecting the credentials
 func IsAuthError(err error) bool {
 	if err == nil {
 		return false
 	}
-	errorStr := err.Error()
-	// Don't treat permission errors as auth errors
-	if contains(errorStr, "is not authorized to perform") {
+
+	// A network failure or cancellation is never fixed by logging in again
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return false
 	}
-	return contains(errorStr, "no active session") ||
-		contains(errorStr, "failed to get shared config profile") ||
-		contains(errorStr, "AuthFailure") ||
-		contains(errorStr, "SignatureDoesNotMatch") ||
-		contains(errorStr, "TokenRefreshRequired") ||
-		contains(errorStr, "ExpiredToken") ||
-		contains(errorStr, "InvalidToken") ||
-		contains(errorStr, "get credentials") ||
-		contains(errorStr, "no EC2 IMDS role found") ||
-		contains(errorStr, "failed to refresh cached credentials") ||
-		contains(errorStr, "no such host") || // DNS resolution failure due to missing region
-		contains(errorStr, "failed to load AWS config")
+
+	if errors.Is(err, awscconfig.ErrNoActiveSession) {
+		return true
+	}
+
+	var profileNotExist config.SharedConfigProfileNotExistError
+	if errors.As(err, &profileNotExist) {
+		return true
+	}
+
+	var invalidToken *ssocreds.InvalidTokenError
+	var processErr *processcreds.ProviderError
+	var emptyCreds *credentials.StaticCredentialsEmptyError
+	if errors.As(err, &invalidToken) || errors.As(err, &processErr) || errors.As(err, &emptyCreds) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return authErrorCodes[apiErr.ErrorCode()]
+	}
+
+	return isIdentityResolutionError(err)
 }
 
-func contains(s, substr string) bool {
-	return strings.Contains(s, substr)
+// identityErrorMessages are the messages of the credential provider failures IsAuthError
+// matches by type above, for clients that lose the type
+var identityErrorMessages = []string{
+	awscconfig.ErrNoActiveSession.Error(),
+	"failed to get shared config profile, ",                                // config.SharedConfigProfileNotExistError
+	(&ssocreds.InvalidTokenError{}).Error(),                                // SSO token expired or invalid
+	"cached SSO token is expired, or not present, and cannot be refreshed", // ssocreds.SSOTokenProvider
+	"process provider error: ",                                             // processcreds.ProviderError
+	(&credentials.StaticCredentialsEmptyError{}).Error(),
 }
 
-func (c *CredentialsManager) GetCachedToken() (*string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+// isIdentityResolutionError reports whether a client failed to resolve credentials before
+// sending the request because they are missing or expired. Older generated clients (RDS,
+// SSM) flatten every provider error with %v, network failures included, so only the known
+// provider failure messages count.
+func isIdentityResolutionError(err error) bool {
+	var opErr *smithy.OperationError
+	if !errors.As(err, &opErr) {
+		return false
 	}
+	for cause := opErr.Err; cause != nil; cause = errors.Unwrap(cause) {
+		message, found := strings.CutPrefix(cause.Error(), "get identity: ")
+		if !found {
+			continue
+		}
+		// Clients that wrap with %w keep the typed cause, which IsAuthError already checked
+		if errors.Unwrap(cause) != nil {
+			return false
+		}
+		for _, known := range identityErrorMessages {
+			if strings.Contains(message, known) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
 
-	cacheDir := filepath.Join(homeDir, ".aws", "sso", "cache")
-
+// GetCachedToken returns the cached SSO access token, silently refreshing it
+// with the cached refresh token when it has expired
+func (c *CredentialsManager) GetCachedToken(ctx context.Context) (*string, error) {
 	// Get the start URL to find the correct cache file
 	startURL := viper.GetString("sso.start_url")
 	if startURL == "" {
 		return nil, fmt.Errorf("no SSO start URL configured")
 	}
 
-	// Create cache filename based on start URL (same logic as saveTokenToCache)
-	h := sha1.New()
-	h.Write([]byte(startURL))
-	filename := fmt.Sprintf("%x.json", h.Sum(nil))
-	cacheFile := filepath.Join(cacheDir, filename)
+	cache, err := c.loadTokenFromCache(startURL)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if the specific cache file exists
-	data, err := os.ReadFile(cacheFile)
+	// Without a refresh token, return the token without checking expiration - let API calls fail naturally
+	if cache.RefreshToken == "" || time.Now().Before(cache.ExpiresAt) {
+		return &cache.AccessToken, nil
+	}
+
+	if err := c.refreshToken(ctx, cache); err != nil {
+		return nil, fmt.Errorf("failed to refresh SSO token: %v", err)
+	}
+
+	return &cache.AccessToken, nil
+}
+
+// GetTokenExpiration returns when the cached SSO access token expires
+func (c *CredentialsManager) GetTokenExpiration() (time.Time, error) {
+	cache, err := c.loadTokenFromCache(viper.GetString("sso.start_url"))
 	if err != nil {
-		return nil, fmt.Errorf("no SSO cache found for this start URL, please run 'awsc login'")
+		return time.Time{}, err
 	}
+	return cache.ExpiresAt, nil
+}
 
-	var cache SSOCache
-	if err := json.Unmarshal(data, &cache); err != nil {
-		return nil, err
+// errTokenNotRefreshable means the cached SSO token has no refresh token or its client
+// registration has expired, so only an interactive login can replace it
+var errTokenNotRefreshable = errors.New("cached SSO token can't be refreshed")
+
+// refreshCachedToken refreshes the cached SSO token even if it hasn't expired yet, for when
+// SSO has already rejected it
+func (c *CredentialsManager) refreshCachedToken(ctx context.Context) (*string, error) {
+	cache, err := c.loadTokenFromCache(viper.GetString("sso.start_url"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errTokenNotRefreshable, err)
+	}
+	if cache.RefreshToken == "" {
+		return nil, fmt.Errorf("%w: no refresh token", errTokenNotRefreshable)
 	}
 
-	// Return token without checking expiration - let API calls fail naturally
+	if err := c.refreshToken(ctx, cache); err != nil {
+		return nil, err
+	}
 	return &cache.AccessToken, nil
 }
 
-func (c *CredentialsManager) Authenticate(ctx context.Context, startURL, ssoRegion string) error {
-	// Register client
-	registerResp, err := c.oidcClient.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+// refreshToken exchanges the cached refresh token for a new access token and updates the cache
+func (c *CredentialsManager) refreshToken(ctx context.Context, cache *SSOCache) error {
+	if cache.ClientID == "" || time.Now().After(cache.RegistrationExpiresAt) {
+		return fmt.Errorf("%w: client registration expired", errTokenNotRefreshable)
+	}
+
+	tokenResp, err := c.oidcClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
+		ClientId:     aws.String(cache.ClientID),
+		ClientSecret: aws.String(cache.ClientSecret),
+		RefreshToken: aws.String(cache.RefreshToken),
+		GrantType:    aws.String(refreshTokenGrantType),
+	})
+	if err != nil {
+		return err
+	}
+
+	cache.AccessToken = *tokenResp.AccessToken
+	cache.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	// The service may rotate the refresh token
+	if tokenResp.RefreshToken != nil {
+		cache.RefreshToken = *tokenResp.RefreshToken
+	}
+
+	return c.saveTokenToCache(cache)
+}
+
+// getClientRegistration reuses the cached OIDC client registration if it is still valid,
+// otherwise registers a new client
+func (c *CredentialsManager) getClientRegistration(ctx context.Context, startURL, method string) (*SSOCache, error) {
+	cache, err := c.loadTokenFromCache(startURL)
+	if err == nil && cache.ClientID != "" && time.Now().Before(cache.RegistrationExpiresAt) && registrationMethod(cache) == method {
+		return cache, nil
+	}
+
+	input := &ssooidc.RegisterClientInput{
 		ClientName: aws.String("awsc"),
 		ClientType: aws.String("public"),
-	})
+		Scopes:     []string{"sso:account:access"},
+		GrantTypes: []string{deviceCodeGrantType, refreshTokenGrantType},
+	}
+	if method == awscconfig.LoginMethodPKCE {
+		input.GrantTypes = []string{authorizationCodeGrantType, refreshTokenGrantType}
+		input.RedirectUris = []string{pkceRedirectURI}
+		input.IssuerUrl = aws.String(startURL)
+	}
+
+	registerResp, err := c.oidcClient.RegisterClient(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSOCache{
+		ClientID:              *registerResp.ClientId,
+		ClientSecret:          *registerResp.ClientSecret,
+		RegistrationExpiresAt: time.Unix(registerResp.ClientSecretExpiresAt, 0),
+		StartURL:              startURL,
+		LoginMethod:           method,
+	}, nil
+}
+
+// registrationMethod returns the login method a cached registration was created for;
+// registrations from older awsc versions and the AWS CLI use the device flow
+func registrationMethod(cache *SSOCache) string {
+	if cache.LoginMethod == "" {
+		return awscconfig.LoginMethodDevice
+	}
+	return cache.LoginMethod
+}
+
+// Authenticate signs in to AWS SSO with the configured login method and caches the token
+func (c *CredentialsManager) Authenticate(ctx context.Context, startURL, ssoRegion string) error {
+	switch method := awscconfig.GetLoginMethod(); method {
+	case awscconfig.LoginMethodDevice:
+		return c.authenticateDevice(ctx, startURL, ssoRegion)
+	case awscconfig.LoginMethodPKCE:
+		// The browser has to reach the 127.0.0.1 listener, which a headless session can't offer
+		if awscconfig.IsNoBrowser() {
+			return fmt.Errorf("the pkce login method needs a browser on this machine to reach its 127.0.0.1 redirect, use --login-method device with --no-browser")
+		}
+		return c.authenticatePKCE(ctx, startURL, ssoRegion)
+	default:
+		return fmt.Errorf("unknown login method '%s' (expected %s or %s)", method, awscconfig.LoginMethodDevice, awscconfig.LoginMethodPKCE)
+	}
+}
+
+// authenticateDevice runs the OAuth device authorization flow
+func (c *CredentialsManager) authenticateDevice(ctx context.Context, startURL, ssoRegion string) error {
+	// Register client (or reuse cached registration)
+	registration, err := c.getClientRegistration(ctx, startURL, awscconfig.LoginMethodDevice)
 	if err != nil {
 		return fmt.Errorf("failed to register client: %v", err)
 	}
 
 	// Start device authorization
 	deviceResp, err := c.oidcClient.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
-		ClientId:     registerResp.ClientId,
-		ClientSecret: registerResp.ClientSecret,
+		ClientId:     aws.String(registration.ClientID),
+		ClientSecret: aws.String(registration.ClientSecret),
 		StartUrl:     aws.String(startURL),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to start device authorization: %v", err)
 	}
 
-	// Open browser
-	fmt.Printf("Opening browser to: %s\n", *deviceResp.VerificationUriComplete)
-	fmt.Printf("If browser doesn't open, visit: %s\n", *deviceResp.VerificationUriComplete)
-	fmt.Printf("And enter code: %s\n", *deviceResp.UserCode)
-
-	if err := openBrowser(*deviceResp.VerificationUriComplete); err != nil {
-		fmt.Printf("Failed to open browser: %v\n", err)
-	}
+	presentLoginURL(*deviceResp.VerificationUriComplete, aws.ToString(deviceResp.UserCode))
 
 	// Poll for token with timeout
 	timeoutMinutes := int(deviceResp.ExpiresIn / 60)
-	fmt.Printf("Waiting for authentication (timeout in %d minutes)...\n", timeoutMinutes)
+	fmt.Fprintf(os.Stderr, "Waiting for authentication (timeout in %d minutes)...\n", timeoutMinutes)
 	timeout := time.Now().Add(time.Duration(deviceResp.ExpiresIn) * time.Second)
 	interval := time.Duration(deviceResp.Interval) * time.Second
 
 	for time.Now().Before(timeout) {
 		tokenResp, err := c.oidcClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
-			ClientId:     registerResp.ClientId,
-			ClientSecret: registerResp.ClientSecret,
+			ClientId:     aws.String(registration.ClientID),
+			ClientSecret: aws.String(registration.ClientSecret),
 			DeviceCode:   deviceResp.DeviceCode,
-			GrantType:    aws.String("urn:ietf:params:oauth:grant-type:device_code"),
+			GrantType:    aws.String(deviceCodeGrantType),
 		})
 
 		if err != nil {
 			// Check if we should continue polling
 			if isRetryableError(err) {
-				fmt.Print(".")
+				fmt.Fprint(os.Stderr, ".")
 				time.Sleep(interval)
 				continue
 			}
@@ -166,42 +455,110 @@ func (c *CredentialsManager) Authenticate(ctx context.Context, startURL, ssoRegi
 		}
 
 		// Success! Save token to cache
-		fmt.Println("\nAuthentication successful!")
-		if err := c.saveTokenToCache(startURL, ssoRegion, tokenResp.AccessToken, &tokenResp.ExpiresIn); err != nil {
-			return fmt.Errorf("failed to save token: %v", err)
-		}
-
-		return nil
+		fmt.Fprintln(os.Stderr, "\nAuthentication successful!")
+		return c.storeToken(registration, tokenResp, startURL, ssoRegion)
 	}
 
 	return fmt.Errorf("authentication timed out - please try again")
+}
 
+// storeToken saves a newly issued token together with its client registration
+func (c *CredentialsManager) storeToken(registration *SSOCache, tokenResp *ssooidc.CreateTokenOutput, startURL, ssoRegion string) error {
+	registration.AccessToken = *tokenResp.AccessToken
+	registration.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	registration.RefreshToken = aws.ToString(tokenResp.RefreshToken)
+	registration.Region = ssoRegion
+	registration.StartURL = startURL
+	if err := c.saveTokenToCache(registration); err != nil {
+		return fmt.Errorf("failed to save token: %v", err)
+	}
 	return nil
 }
 
-func (c *CredentialsManager) saveTokenToCache(startURL, ssoRegion string, accessToken *string, expiresIn *int32) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
+// presentLoginURL opens the sign-in URL in the browser. In no-browser mode it only prints
+// the URL (and device code) to stderr as KEY=value lines so wrappers can parse them.
+func presentLoginURL(url, userCode string) {
+	if awscconfig.IsNoBrowser() {
+		fmt.Fprintf(os.Stderr, "AWSC_SSO_URL=%s\n", url)
+		if userCode != "" {
+			fmt.Fprintf(os.Stderr, "AWSC_SSO_CODE=%s\n", userCode)
+		}
+		return
 	}
 
-	cacheDir := filepath.Join(homeDir, ".aws", "sso", "cache")
-	if err := os.MkdirAll(cacheDir, 0700); err != nil {
-		return err
+	fmt.Fprintf(os.Stderr, "Opening browser to: %s\n", url)
+	fmt.Fprintf(os.Stderr, "If browser doesn't open, visit: %s\n", url)
+	if userCode != "" {
+		fmt.Fprintf(os.Stderr, "And enter code: %s\n", userCode)
+	}
+
+	if err := browserOpener(url); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open browser: %v\n", err)
+	}
+}
+
+// getTokenCachePath returns the cache file path for the given key, using the same
+// sha1 naming as botocore so the AWS CLI v2 and awsc share one token
+func getTokenCachePath(key string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
 	}
 
-	// Create cache filename based on start URL
 	h := sha1.New()
-	h.Write([]byte(startURL))
+	h.Write([]byte(key))
 	filename := fmt.Sprintf("%x.json", h.Sum(nil))
-	cacheFile := filepath.Join(cacheDir, filename)
+	return filepath.Join(homeDir, ".aws", "sso", "cache", filename), nil
+}
+
+// loadTokenFromCache reads the token cached for the configured sso-session, falling back
+// to the legacy start URL cache file written by older awsc and AWS CLI versions
+func (c *CredentialsManager) loadTokenFromCache(startURL string) (*SSOCache, error) {
+	store, err := awscconfig.GetCredentialStore()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{awscconfig.GetSSOSessionName(), startURL} {
+		cacheFile, err := getTokenCachePath(key)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := store.Load(cacheFile)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			continue
+		}
 
-	// Create cache entry
-	cache := SSOCache{
-		AccessToken: *accessToken,
-		ExpiresAt:   time.Now().Add(time.Duration(*expiresIn) * time.Second),
-		Region:      ssoRegion,
-		StartURL:    startURL,
+		var cache SSOCache
+		if err := json.Unmarshal(data, &cache); err != nil {
+			return nil, err
+		}
+
+		// Ignore tokens issued for a different start URL
+		if cache.StartURL != "" && cache.StartURL != startURL {
+			continue
+		}
+
+		return &cache, nil
+	}
+
+	return nil, fmt.Errorf("no SSO cache found for this start URL, please run 'awsc login'")
+}
+
+// saveTokenToCache writes the token to the cache file of the configured sso-session
+func (c *CredentialsManager) saveTokenToCache(cache *SSOCache) error {
+	store, err := awscconfig.GetCredentialStore()
+	if err != nil {
+		return err
+	}
+
+	cacheFile, err := getTokenCachePath(awscconfig.GetSSOSessionName())
+	if err != nil {
+		return err
 	}
 
 	data, err := json.MarshalIndent(cache, "", "  ")
@@ -209,9 +566,41 @@ func (c *CredentialsManager) saveTokenToCache(startURL, ssoRegion string, access
 		return err
 	}
 
-	return os.WriteFile(cacheFile, data, 0600)
+	return store.Save(cacheFile, data)
 }
 
+// Logout revokes the cached SSO token and deletes it together with the cached client registration
+func (c *CredentialsManager) Logout(ctx context.Context) error {
+	startURL := viper.GetString("sso.start_url")
+
+	cache, err := c.loadTokenFromCache(startURL)
+	if err == nil && cache.AccessToken != "" && time.Now().Before(cache.ExpiresAt) && c.ssoManager != nil {
+		// Still delete the local cache if the token can't be revoked
+		if err := c.ssoManager.Logout(ctx, cache.AccessToken); err != nil {
+			fmt.Printf("Warning: failed to revoke SSO token: %v\n", err)
+		}
+	}
+
+	return deleteTokenCache(startURL)
+}
+
+// deleteTokenCache removes the sso-session and legacy start URL token cache files
+func deleteTokenCache(startURL string) error {
+	for _, key := range []string{awscconfig.GetSSOSessionName(), startURL} {
+		cacheFile, err := getTokenCachePath(key)
+		if err != nil {
+			return err
+		}
+		if err := awscconfig.RemoveStoredFile(cacheFile); err != nil {
+			return fmt.Errorf("failed to remove SSO token cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// browserOpener opens URLs in the user's browser (variable for tests)
+var browserOpener = openBrowser
+
 func openBrowser(url string) error {
 	var cmd string
 	var args []string
@@ -241,10 +630,8 @@ func isRetryableError(err error) bool {
 // PromptForReauth asks the user if they want to re-authenticate and runs login if yes
 func PromptForReauth(ctx context.Context) (bool, error) {
 	// Check if this is a "no active session" error
-	cfg, loadErr := awscconfig.LoadAWSConfigWithProfile(ctx)
-	_ = cfg // Unused, just checking the error
-
-	if loadErr != nil && strings.Contains(loadErr.Error(), "no active session") {
+	_, loadErr := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if errors.Is(loadErr, awscconfig.ErrNoActiveSession) {
 		fmt.Fprintf(os.Stderr, "No active session found. Please login first.\n")
 
 		// Auto-trigger login
@@ -295,6 +682,14 @@ func PromptForReauth(ctx context.Context) (bool, error) {
 		if response != "y" && response != "yes" {
 			return false, nil
 		}
+
+		// Log in to the same account and role so a retried call sees the same resources
+		if err := reloginActiveTarget(ctx); err != nil {
+			return false, err
+		}
+
+		fmt.Fprintf(os.Stderr, "Authentication successful. Retrying operation...\n")
+		return true, nil
 	}
 
 	// Run login automatically
@@ -6,12 +6,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	ssooidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/spf13/viper"
+	"go.uber.org/mock/gomock"
 )
 
 func TestNewCredentialsManager(t *testing.T) {
@@ -39,32 +55,62 @@ func TestIsAuthError(t *testing.T) {
 		},
 		{
 			name:     "auth failure error",
-			err:      fmt.Errorf("AuthFailure: invalid credentials"),
+			err:      &smithy.GenericAPIError{Code: "AuthFailure", Message: "invalid credentials"},
 			expected: true,
 		},
 		{
 			name:     "signature error",
-			err:      fmt.Errorf("SignatureDoesNotMatch: signature mismatch"),
+			err:      &smithy.GenericAPIError{Code: "SignatureDoesNotMatch", Message: "signature mismatch"},
 			expected: true,
 		},
 		{
 			name:     "expired token error",
-			err:      fmt.Errorf("ExpiredToken: token has expired"),
+			err:      &smithy.GenericAPIError{Code: "ExpiredToken", Message: "token has expired"},
 			expected: true,
 		},
 		{
 			name:     "invalid token error",
-			err:      fmt.Errorf("InvalidToken: token is invalid"),
+			err:      &smithy.GenericAPIError{Code: "InvalidToken", Message: "token is invalid"},
 			expected: true,
 		},
 		{
 			name:     "get credentials error",
-			err:      fmt.Errorf("failed to get credentials"),
+			err:      fmt.Errorf("get credentials: %w", &ssocreds.InvalidTokenError{}),
+			expected: true,
+		},
+		{
+			name:     "no active session",
+			err:      awscconfig.ErrNoActiveSession,
+			expected: true,
+		},
+		{
+			name:     "wrapped no active session",
+			err:      fmt.Errorf("failed to load AWS config: %w", awscconfig.ErrNoActiveSession),
+			expected: true,
+		},
+		{
+			name:     "profile removed from shared config",
+			err:      fmt.Errorf("failed to load AWS config: %w", config.SharedConfigProfileNotExistError{Profile: "awsc"}),
+			expected: true,
+		},
+		{
+			name:     "credential_process failure",
+			err:      fmt.Errorf("get credentials: %w", &processcreds.ProviderError{Err: fmt.Errorf("exit status 1")}),
 			expected: true,
 		},
 		{
 			name:     "permission error (not auth error)",
-			err:      fmt.Errorf("User is not authorized to perform action"),
+			err:      &smithy.GenericAPIError{Code: "AccessDenied", Message: "User is not authorized to perform action"},
+			expected: false,
+		},
+		{
+			name:     "error text alone is not classified",
+			err:      fmt.Errorf("ExpiredToken: failed to get credentials"),
+			expected: false,
+		},
+		{
+			name:     "DNS failure (not auth error)",
+			err:      &net.DNSError{Err: "no such host", Name: "ec2.us-east-1.amazonaws.com", IsNotFound: true},
 			expected: false,
 		},
 		{
@@ -84,44 +130,85 @@ func TestIsAuthError(t *testing.T) {
 	}
 }
 
-func TestContains(t *testing.T) {
+// TestIsAuthError_Services covers the errors each service awsc calls returns, wrapped the way
+// the SDK returns them from an operation
+func TestIsAuthError_Services(t *testing.T) {
+	dnsErr := &net.DNSError{Err: "no such host", Name: "example.amazonaws.com", IsNotFound: true}
+	timeoutErr := &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+
 	tests := []struct {
-		name     string
-		s        string
-		substr   string
-		expected bool
+		service   string
+		operation string
+		err       error
+		expected  bool
 	}{
-		{
-			name:     "substring found",
-			s:        "hello world",
-			substr:   "world",
-			expected: true,
-		},
-		{
-			name:     "substring not found",
-			s:        "hello world",
-			substr:   "foo",
-			expected: false,
-		},
-		{
-			name:     "empty substring",
-			s:        "hello world",
-			substr:   "",
-			expected: true,
-		},
-		{
-			name:     "empty string",
-			s:        "",
-			substr:   "foo",
-			expected: false,
-		},
+		// RDS (query protocol; credential errors lose their type in this client version)
+		{"RDS", "DescribeDBInstances", &smithy.GenericAPIError{Code: "ExpiredToken"}, true},
+		{"RDS", "DescribeDBInstances", &smithy.GenericAPIError{Code: "InvalidClientTokenId"}, true},
+		{"RDS", "DescribeDBInstances", fmt.Errorf("get identity: %v", &ssocreds.InvalidTokenError{}), true},
+		{"RDS", "DescribeDBInstances", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"RDS", "DescribeDBInstances", &smithy.GenericAPIError{Code: "DBInstanceNotFound"}, false},
+		{"RDS", "DescribeDBInstances", dnsErr, false},
+		{"RDS", "DescribeDBInstances", fmt.Errorf("get identity: %v", fmt.Errorf("get credentials: %w", dnsErr)), false},
+		{"RDS", "DescribeDBInstances", fmt.Errorf("get identity: %v", timeoutErr), false},
+		{"RDS", "DescribeDBInstances", fmt.Errorf("get identity: %v", context.Canceled), false},
+		{"RDS", "DescribeDBInstances", fmt.Errorf("get identity: %v", &credentials.StaticCredentialsEmptyError{}), true},
+		{"RDS", "DescribeDBInstances", fmt.Errorf("get identity: %v", awscconfig.ErrNoActiveSession), true},
+
+		// EC2
+		{"EC2", "DescribeInstances", &smithy.GenericAPIError{Code: "AuthFailure"}, true},
+		{"EC2", "DescribeInstances", &smithy.GenericAPIError{Code: "RequestExpired"}, false},
+		{"EC2", "DescribeInstances", &smithy.GenericAPIError{Code: "UnauthorizedOperation"}, false},
+		{"EC2", "DescribeInstances", fmt.Errorf("get identity: %w", fmt.Errorf("get credentials: %w", &ssocreds.InvalidTokenError{})), true},
+		{"EC2", "DescribeInstances", fmt.Errorf("get identity: %w", fmt.Errorf("get credentials: %w", dnsErr)), false},
+
+		// SSM (JSON protocol; credential errors lose their type in this client version)
+		{"SSM", "StartSession", &smithy.GenericAPIError{Code: "ExpiredTokenException"}, true},
+		{"SSM", "StartSession", &smithy.GenericAPIError{Code: "UnrecognizedClientException"}, true},
+		{"SSM", "StartSession", fmt.Errorf("get identity: %v", &processcreds.ProviderError{}), true},
+		{"SSM", "StartSession", fmt.Errorf("get identity: %v", fmt.Errorf("get credentials: %w", dnsErr)), false},
+		{"SSM", "StartSession", fmt.Errorf("get identity: %v", timeoutErr), false},
+		{"SSM", "StartSession", fmt.Errorf("get identity: %v", context.DeadlineExceeded), false},
+		{"SSM", "StartSession", fmt.Errorf("get identity: %v", fmt.Errorf("get credentials: %w", &ssocreds.InvalidTokenError{})), true},
+		{"SSM", "StartSession", &smithy.GenericAPIError{Code: "AccessDeniedException"}, false},
+		{"SSM", "StartSession", &smithy.GenericAPIError{Code: "TargetNotConnected"}, false},
+
+		// Secrets Manager
+		{"Secrets Manager", "ListSecrets", &smithy.GenericAPIError{Code: "ExpiredTokenException"}, true},
+		{"Secrets Manager", "GetSecretValue", &smithy.GenericAPIError{Code: "InvalidSignatureException"}, true},
+		{"Secrets Manager", "GetSecretValue", &smithy.GenericAPIError{Code: "AccessDeniedException"}, false},
+		{"Secrets Manager", "GetSecretValue", &smithy.GenericAPIError{Code: "ResourceNotFoundException"}, false},
+
+		// OpenSearch
+		{"OpenSearch", "ListDomainNames", &smithy.GenericAPIError{Code: "UnrecognizedClientException"}, true},
+		{"OpenSearch", "ListDomainNames", &smithy.GenericAPIError{Code: "MissingAuthenticationToken"}, true},
+		{"OpenSearch", "DescribeDomains", &smithy.GenericAPIError{Code: "AccessDeniedException"}, false},
+		{"OpenSearch", "DescribeDomains", dnsErr, false},
+
+		// SSO portal
+		{"SSO", "ListAccounts", &ssotypes.UnauthorizedException{}, true},
+		{"SSO", "GetRoleCredentials", &ssotypes.TooManyRequestsException{}, false},
+		{"SSO", "ListAccountRoles", &ssotypes.ResourceNotFoundException{}, false},
+
+		// SSO OIDC
+		{"SSO OIDC", "CreateToken", &ssooidctypes.InvalidGrantException{}, true},
+		{"SSO OIDC", "CreateToken", &ssooidctypes.ExpiredTokenException{}, true},
+		{"SSO OIDC", "CreateToken", &ssooidctypes.UnauthorizedClientException{}, true},
+		{"SSO OIDC", "CreateToken", &ssooidctypes.AuthorizationPendingException{}, false},
+		{"SSO OIDC", "CreateToken", &ssooidctypes.SlowDownException{}, false},
+
+		// STS
+		{"STS", "GetCallerIdentity", &ststypes.ExpiredTokenException{}, true},
+		{"STS", "GetCallerIdentity", &smithy.GenericAPIError{Code: "InvalidClientTokenId"}, true},
+		{"STS", "AssumeRole", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"STS", "AssumeRole", &ststypes.RegionDisabledException{}, false},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := contains(tt.s, tt.substr)
-			if result != tt.expected {
-				t.Errorf("Expected %v, got %v", tt.expected, result)
+		t.Run(fmt.Sprintf("%s %s %v", tt.service, tt.operation, tt.err), func(t *testing.T) {
+			err := &smithy.OperationError{ServiceID: tt.service, OperationName: tt.operation, Err: tt.err}
+			if result := IsAuthError(err); result != tt.expected {
+				t.Errorf("Expected %v, got %v for error: %v", tt.expected, result, err)
 			}
 		})
 	}
@@ -143,7 +230,7 @@ func TestCredentialsManager_GetCachedToken(t *testing.T) {
 	manager := &CredentialsManager{}
 
 	// Test no cache directory
-	_, err := manager.GetCachedToken()
+	_, err := manager.GetCachedToken(context.Background())
 	if err == nil {
 		t.Error("Expected error when no cache directory exists")
 	}
@@ -180,7 +267,7 @@ func TestCredentialsManager_GetCachedToken(t *testing.T) {
 	}
 
 	// Test getting valid token
-	token, err := manager.GetCachedToken()
+	token, err := manager.GetCachedToken(context.Background())
 	if err != nil {
 		t.Fatalf("GetCachedToken failed: %v", err)
 	}
@@ -207,7 +294,7 @@ func TestCredentialsManager_GetCachedToken(t *testing.T) {
 	}
 
 	// Should return token even if expired - let API calls handle expiration
-	token, err = manager.GetCachedToken()
+	token, err = manager.GetCachedToken(context.Background())
 	if err != nil {
 		t.Fatalf("GetCachedToken should not fail for expired token: %v", err)
 	}
@@ -230,9 +317,13 @@ func TestCredentialsManager_saveTokenToCache(t *testing.T) {
 	startURL := "https://test.awsapps.com/start"
 	ssoRegion := "us-east-1"
 	accessToken := "test-access-token"
-	expiresIn := int32(3600)
 
-	err := manager.saveTokenToCache(startURL, ssoRegion, &accessToken, &expiresIn)
+	err := manager.saveTokenToCache(&SSOCache{
+		AccessToken: accessToken,
+		ExpiresAt:   time.Now().Add(1 * time.Hour),
+		Region:      ssoRegion,
+		StartURL:    startURL,
+	})
 	if err != nil {
 		t.Fatalf("saveTokenToCache failed: %v", err)
 	}
@@ -272,6 +363,63 @@ func TestCredentialsManager_saveTokenToCache(t *testing.T) {
 	}
 }
 
+func TestCredentialsManager_TokenCache_EncryptedStore(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_STORE_PASSPHRASE", "hunter2")
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("credential_store.type", "encrypted")
+
+	manager := &CredentialsManager{}
+	startURL := "https://test.awsapps.com/start"
+
+	if err := manager.saveTokenToCache(&SSOCache{
+		AccessToken: "test-access-token",
+		ExpiresAt:   time.Now().Add(1 * time.Hour),
+		Region:      "us-east-1",
+		StartURL:    startURL,
+	}); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+
+	cacheFile, err := getTokenCachePath(awscconfig.GetSSOSessionName())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cacheFile); !os.IsNotExist(err) {
+		t.Errorf("plaintext token cache should not exist, stat error = %v", err)
+	}
+	data, err := os.ReadFile(cacheFile + ".enc")
+	if err != nil {
+		t.Fatalf("encrypted token cache was not created: %v", err)
+	}
+	if strings.Contains(string(data), "test-access-token") {
+		t.Error("encrypted token cache contains the access token")
+	}
+
+	cache, err := manager.loadTokenFromCache(startURL)
+	if err != nil {
+		t.Fatalf("loadTokenFromCache failed: %v", err)
+	}
+	if cache.AccessToken != "test-access-token" {
+		t.Errorf("Expected test-access-token, got %s", cache.AccessToken)
+	}
+
+	// A wrong passphrase is reported instead of looking like a missing login
+	t.Setenv("AWSC_STORE_PASSPHRASE", "wrong")
+	if _, err := manager.loadTokenFromCache(startURL); err == nil || !strings.Contains(err.Error(), "wrong key or passphrase") {
+		t.Errorf("loadTokenFromCache with wrong passphrase error = %v", err)
+	}
+
+	if err := deleteTokenCache(startURL); err != nil {
+		t.Fatalf("deleteTokenCache failed: %v", err)
+	}
+	if _, err := os.Stat(cacheFile + ".enc"); !os.IsNotExist(err) {
+		t.Errorf("encrypted token cache should be removed, stat error = %v", err)
+	}
+}
+
 func TestIsRetryableError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -351,8 +499,355 @@ func TestCredentialsManager_GetCachedToken_InvalidJSON(t *testing.T) {
 	}
 
 	// Test getting token with invalid JSON
-	_, err = manager.GetCachedToken()
+	_, err = manager.GetCachedToken(context.Background())
 	if err == nil {
 		t.Error("Expected error for invalid JSON")
 	}
 }
+
+func TestCredentialsManager_GetCachedToken_Refresh(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	startURL := "https://test.awsapps.com/start"
+	viper.Set("sso.start_url", startURL)
+	defer viper.Reset()
+
+	tests := []struct {
+		name          string
+		cache         SSOCache
+		mockSetup     func(*mocks.MockOIDCClient)
+		expectedToken string
+		expectedError bool
+	}{
+		{
+			name: "expired token is refreshed",
+			cache: SSOCache{
+				AccessToken:           "expired-token",
+				ExpiresAt:             time.Now().Add(-1 * time.Hour),
+				RefreshToken:          "refresh-token",
+				ClientID:              "client-id",
+				ClientSecret:          "client-secret",
+				RegistrationExpiresAt: time.Now().Add(24 * time.Hour),
+				Region:                "us-east-1",
+				StartURL:              startURL,
+			},
+			mockSetup: func(m *mocks.MockOIDCClient) {
+				m.EXPECT().CreateToken(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+						if *input.GrantType != "refresh_token" {
+							t.Errorf("Expected refresh_token grant, got %s", *input.GrantType)
+						}
+						if *input.RefreshToken != "refresh-token" {
+							t.Errorf("Expected refresh-token, got %s", *input.RefreshToken)
+						}
+						return &ssooidc.CreateTokenOutput{
+							AccessToken:  aws.String("new-token"),
+							ExpiresIn:    3600,
+							RefreshToken: aws.String("new-refresh-token"),
+						}, nil
+					})
+			},
+			expectedToken: "new-token",
+		},
+		{
+			name: "valid token is not refreshed",
+			cache: SSOCache{
+				AccessToken:  "valid-token",
+				ExpiresAt:    time.Now().Add(1 * time.Hour),
+				RefreshToken: "refresh-token",
+				StartURL:     startURL,
+			},
+			mockSetup:     func(m *mocks.MockOIDCClient) {},
+			expectedToken: "valid-token",
+		},
+		{
+			name: "refresh failure returns error",
+			cache: SSOCache{
+				AccessToken:           "expired-token",
+				ExpiresAt:             time.Now().Add(-1 * time.Hour),
+				RefreshToken:          "refresh-token",
+				ClientID:              "client-id",
+				ClientSecret:          "client-secret",
+				RegistrationExpiresAt: time.Now().Add(24 * time.Hour),
+				StartURL:              startURL,
+			},
+			mockSetup: func(m *mocks.MockOIDCClient) {
+				m.EXPECT().CreateToken(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("InvalidGrantException"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "expired registration returns error",
+			cache: SSOCache{
+				AccessToken:           "expired-token",
+				ExpiresAt:             time.Now().Add(-1 * time.Hour),
+				RefreshToken:          "refresh-token",
+				ClientID:              "client-id",
+				ClientSecret:          "client-secret",
+				RegistrationExpiresAt: time.Now().Add(-1 * time.Hour),
+				StartURL:              startURL,
+			},
+			mockSetup:     func(m *mocks.MockOIDCClient) {},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOIDC := mocks.NewMockOIDCClient(ctrl)
+			tt.mockSetup(mockOIDC)
+
+			manager, err := NewCredentialsManager(context.Background(), CredentialsManagerOptions{OIDCClient: mockOIDC})
+			if err != nil {
+				t.Fatalf("Unexpected error creating manager: %v", err)
+			}
+
+			if err := manager.saveTokenToCache(&tt.cache); err != nil {
+				t.Fatalf("Failed to write cache: %v", err)
+			}
+
+			token, err := manager.GetCachedToken(context.Background())
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *token != tt.expectedToken {
+				t.Errorf("Expected %s, got %s", tt.expectedToken, *token)
+			}
+
+			// Refreshed tokens must be persisted
+			cache, err := manager.loadTokenFromCache(startURL)
+			if err != nil {
+				t.Fatalf("Failed to load cache: %v", err)
+			}
+			if cache.AccessToken != tt.expectedToken {
+				t.Errorf("Expected cached token %s, got %s", tt.expectedToken, cache.AccessToken)
+			}
+		})
+	}
+}
+
+func TestCredentialsManager_getClientRegistration(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOIDC := mocks.NewMockOIDCClient(ctrl)
+	manager, err := NewCredentialsManager(context.Background(), CredentialsManagerOptions{OIDCClient: mockOIDC})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	startURL := "https://test.awsapps.com/start"
+
+	// No cached registration - a new client is registered with the refresh token grant
+	mockOIDC.EXPECT().RegisterClient(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+			hasRefresh := false
+			for _, grant := range input.GrantTypes {
+				if grant == "refresh_token" {
+					hasRefresh = true
+				}
+			}
+			if !hasRefresh {
+				t.Error("Expected refresh_token grant type to be requested")
+			}
+			return &ssooidc.RegisterClientOutput{
+				ClientId:              aws.String("new-client"),
+				ClientSecret:          aws.String("new-secret"),
+				ClientSecretExpiresAt: time.Now().Add(90 * 24 * time.Hour).Unix(),
+			}, nil
+		})
+
+	registration, err := manager.getClientRegistration(context.Background(), startURL, awscconfig.LoginMethodDevice)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if registration.ClientID != "new-client" {
+		t.Errorf("Expected new-client, got %s", registration.ClientID)
+	}
+
+	// Cached registration is reused without calling RegisterClient
+	if err := manager.saveTokenToCache(&SSOCache{
+		AccessToken:           "token",
+		ClientID:              "cached-client",
+		ClientSecret:          "cached-secret",
+		RegistrationExpiresAt: time.Now().Add(24 * time.Hour),
+		StartURL:              startURL,
+	}); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+
+	registration, err = manager.getClientRegistration(context.Background(), startURL, awscconfig.LoginMethodDevice)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if registration.ClientID != "cached-client" {
+		t.Errorf("Expected cached-client, got %s", registration.ClientID)
+	}
+}
+
+func TestSSOCache_BotocoreFormat(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	cache := SSOCache{
+		AccessToken:           "token",
+		ExpiresAt:             expiresAt,
+		RefreshToken:          "refresh",
+		ClientID:              "client-id",
+		ClientSecret:          "client-secret",
+		RegistrationExpiresAt: expiresAt,
+		Region:                "us-east-1",
+		StartURL:              "https://test.awsapps.com/start",
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatalf("Failed to marshal cache: %v", err)
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Failed to unmarshal fields: %v", err)
+	}
+
+	expected := map[string]string{
+		"startUrl":              "https://test.awsapps.com/start",
+		"region":                "us-east-1",
+		"accessToken":           "token",
+		"expiresAt":             "2030-01-02T03:04:05Z",
+		"clientId":              "client-id",
+		"clientSecret":          "client-secret",
+		"registrationExpiresAt": "2030-01-02T03:04:05Z",
+		"refreshToken":          "refresh",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%s, got %s", key, value, fields[key])
+		}
+	}
+
+	// Legacy AWS CLI timestamps are accepted
+	var legacy SSOCache
+	if err := json.Unmarshal([]byte(`{"accessToken":"token","expiresAt":"2030-01-02T03:04:05UTC"}`), &legacy); err != nil {
+		t.Fatalf("Failed to parse legacy cache: %v", err)
+	}
+	if !legacy.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected %v, got %v", expiresAt, legacy.ExpiresAt)
+	}
+	if !legacy.RegistrationExpiresAt.IsZero() {
+		t.Error("Expected zero registration expiry")
+	}
+}
+
+func TestCredentialsManager_saveTokenToCache_SessionName(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	viper.Set("sso.start_url", "https://test.awsapps.com/start")
+	viper.Set("sso.session_name", "my-sso")
+	defer viper.Reset()
+
+	manager := &CredentialsManager{}
+	if err := manager.saveTokenToCache(&SSOCache{
+		AccessToken: "session-token",
+		ExpiresAt:   time.Now().Add(1 * time.Hour),
+		StartURL:    "https://test.awsapps.com/start",
+	}); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+
+	// The AWS CLI names sso-session caches by the sha1 of the session name
+	h := sha1.New()
+	h.Write([]byte("my-sso"))
+	cacheFile := filepath.Join(tempDir, ".aws", "sso", "cache", fmt.Sprintf("%x.json", h.Sum(nil)))
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Fatalf("Expected cache file %s: %v", cacheFile, err)
+	}
+
+	token, err := manager.GetCachedToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetCachedToken failed: %v", err)
+	}
+	if *token != "session-token" {
+		t.Errorf("Expected session-token, got %s", *token)
+	}
+
+	// Tokens for a different start URL are ignored
+	viper.Set("sso.start_url", "https://other.awsapps.com/start")
+	if _, err := manager.GetCachedToken(context.Background()); err == nil {
+		t.Error("Expected error for token issued to a different start URL")
+	}
+}
+
+func TestCredentialsManager_Logout(t *testing.T) {
+	tests := []struct {
+		name         string
+		expiresAt    time.Time
+		logoutErr    error
+		expectLogout bool
+	}{
+		{"valid token is revoked", time.Now().Add(1 * time.Hour), nil, true},
+		{"revoke failure still clears cache", time.Now().Add(1 * time.Hour), fmt.Errorf("network error"), true},
+		{"expired token is not revoked", time.Now().Add(-1 * time.Hour), nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			originalHome := os.Getenv("HOME")
+			os.Setenv("HOME", tempDir)
+			defer os.Setenv("HOME", originalHome)
+
+			viper.Set("sso.start_url", "https://test.awsapps.com/start")
+			defer viper.Reset()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSO := mocks.NewMockSSOClient(ctrl)
+			if tt.expectLogout {
+				mockSSO.EXPECT().Logout(gomock.Any(), gomock.Any(), gomock.Any()).Return(&sso.LogoutOutput{}, tt.logoutErr)
+			}
+
+			manager := &CredentialsManager{ssoManager: &SSOManager{client: mockSSO}}
+			if err := manager.saveTokenToCache(&SSOCache{
+				AccessToken:  "token",
+				ExpiresAt:    tt.expiresAt,
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				StartURL:     "https://test.awsapps.com/start",
+			}); err != nil {
+				t.Fatalf("saveTokenToCache failed: %v", err)
+			}
+
+			if err := manager.Logout(context.Background()); err != nil {
+				t.Fatalf("Logout failed: %v", err)
+			}
+
+			if _, err := manager.loadTokenFromCache("https://test.awsapps.com/start"); err == nil {
+				t.Error("Expected token cache to be removed")
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// dbSecretTag names a Secrets Manager secret (name or ARN) holding credentials for an
+// instance or cluster. It takes precedence over the RDS-managed master user secret.
+const dbSecretTag = "awsc:secret"
+
+// dbConnection is what a database client needs to reach the local end of the tunnel
+type dbConnection struct {
+	Port     int32
+	User     string
+	Password string
+	DBName   string
+	IAM      bool // Password is an IAM auth token, which requires SSL
+}
+
+// dbEngineFamily groups RDS engines by the client that speaks their protocol
+func dbEngineFamily(engine string) string {
+	switch strings.ToLower(engine) {
+	case "postgres", "aurora-postgresql":
+		return "postgres"
+	case "mysql", "mariadb", "aurora", "aurora-mysql":
+		return "mysql"
+	default:
+		return ""
+	}
+}
+
+// dbClientName returns the built-in client selected by the --exec value, or "" when the
+// value is a command template
+func dbClientName(execValue, engine string) (string, error) {
+	family := dbEngineFamily(engine)
+	switch execValue {
+	case "auto":
+		switch family {
+		case "postgres":
+			return "psql", nil
+		case "mysql":
+			return "mysql", nil
+		}
+		return "", fmt.Errorf("no database client known for %s engines, pass a command template to --exec", engine)
+	case "psql":
+		if family != "postgres" {
+			return "", fmt.Errorf("psql can't connect to %s engines", engine)
+		}
+		return "psql", nil
+	case "mysql":
+		if family != "mysql" {
+			return "", fmt.Errorf("mysql can't connect to %s engines", engine)
+		}
+		return "mysql", nil
+	default:
+		if strings.TrimSpace(execValue) == "" {
+			return "", fmt.Errorf("--exec needs a client or command template")
+		}
+		return "", nil
+	}
+}
+
+// dbClientCommand builds the argv and extra environment for the --exec value. Passwords
+// are only passed through the environment so they don't show up in process listings.
+func dbClientCommand(execValue, engine string, conn dbConnection) ([]string, []string, error) {
+	client, err := dbClientName(execValue, engine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	port := strconv.Itoa(int(conn.Port))
+	var args, env []string
+	switch client {
+	case "psql":
+		args = []string{"psql", "-h", "localhost", "-p", port}
+		if conn.User != "" {
+			args = append(args, "-U", conn.User)
+		}
+		if conn.DBName != "" {
+			args = append(args, "-d", conn.DBName)
+		}
+		if conn.IAM {
+			env = append(env, "PGSSLMODE=require")
+		}
+	case "mysql":
+		// 127.0.0.1 rather than localhost, which makes mysql use the Unix socket
+		args = []string{"mysql", "-h", "127.0.0.1", "-P", port}
+		if conn.User != "" {
+			args = append(args, "-u", conn.User)
+		}
+		if conn.IAM {
+			// The token goes in cleartext, so the connection must be encrypted
+			args = append(args, "--enable-cleartext-plugin", "--ssl-mode=REQUIRED")
+		}
+		if conn.DBName != "" {
+			args = append(args, conn.DBName)
+		}
+	default:
+		placeholders := strings.NewReplacer(
+			"{host}", "localhost",
+			"{port}", port,
+			"{user}", conn.User,
+			"{dbname}", conn.DBName,
+			"{engine}", engine,
+		)
+		for _, field := range strings.Fields(execValue) {
+			args = append(args, placeholders.Replace(field))
+		}
+	}
+
+	if conn.Password != "" {
+		if name := dbPasswordEnv(engine); name != "" {
+			env = append(env, name+"="+conn.Password)
+		}
+		if client == "" {
+			env = append(env, "AWSC_DB_PASSWORD="+conn.Password)
+		}
+	}
+	return args, env, nil
+}
+
+// dbSecret is the JSON layout of RDS credential secrets. Secrets managed by RDS only carry
+// the username and password.
+type dbSecret struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	DBName   string `json:"dbname"`
+}
+
+// dbCredentials resolves who the client connects as: an IAM auth token when iamUser is
+// set, otherwise the tagged secret, the RDS-managed master secret, or the master username
+// alone so the client prompts for the password.
+func (r *RDSManager) dbCredentials(ctx context.Context, instance RDSInstance, iamUser string) (dbConnection, error) {
+	conn := dbConnection{
+		User:   instance.MasterUsername,
+		DBName: instance.DatabaseName,
+	}
+	// RDS always creates this database, and psql would otherwise try one named after the user
+	if conn.DBName == "" && dbEngineFamily(instance.Engine) == "postgres" {
+		conn.DBName = "postgres"
+	}
+
+	if iamUser != "" {
+		token, err := r.GenerateIAMAuthToken(ctx, instance, iamUser)
+		if err != nil {
+			return conn, err
+		}
+		conn.User = iamUser
+		conn.Password = token
+		conn.IAM = true
+		fmt.Printf("Using IAM auth token for %s\n", iamUser)
+		return conn, nil
+	}
+
+	secretId := instance.TaggedSecret
+	if secretId == "" {
+		secretId = instance.SecretARN
+	}
+	if secretId == "" || r.secretsClient == nil {
+		fmt.Printf("No credentials secret found for %s, the client will prompt for a password\n", instance.Identifier)
+		return conn, nil
+	}
+
+	result, err := r.secretsClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretId),
+	})
+	if err != nil {
+		return conn, fmt.Errorf("failed to read credentials secret %s: %w", secretId, err)
+	}
+
+	var secret dbSecret
+	if err := json.Unmarshal([]byte(aws.ToString(result.SecretString)), &secret); err != nil {
+		return conn, fmt.Errorf("credentials secret %s isn't a JSON username/password secret", secretId)
+	}
+	if secret.Username != "" {
+		conn.User = secret.Username
+	}
+	if secret.DBName != "" {
+		conn.DBName = secret.DBName
+	}
+	conn.Password = secret.Password
+	fmt.Printf("Using credentials from secret %s\n", aws.ToString(result.Name))
+	return conn, nil
+}
+
+// execDBClient opens the tunnel, runs the --exec client against it and tears the tunnel
+// down when the client exits
+func (r *RDSManager) execDBClient(ctx context.Context, bastionId string, instance RDSInstance, localPort int32, opts RDSConnectOptions) error {
+	conn, err := r.dbCredentials(ctx, instance, opts.IAMUser)
+	if err != nil {
+		return err
+	}
+	conn.Port = localPort
+
+	args, env, err := dbClientCommand(opts.Exec, instance.Engine, conn)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return fmt.Errorf("%s not found in PATH", args[0])
+	}
+
+	cfg, err := loadAWSConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	pf := NewExternalPluginForwarder(regionalConfig(cfg, r.region))
+
+	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
+	return pf.RunWithPortForwarding(ctx, bastionId, instance.Endpoint, int(instance.Port), int(localPort), func() error {
+		fmt.Printf("Starting %s on localhost:%d...\n", args[0], localPort)
+		return runClientCommand(args, env)
+	})
+}
+
+// runClientCommand runs an interactive client in the foreground. Ctrl+C belongs to the
+// client, so awsc ignores interrupts until it exits.
+func runClientCommand(args, env []string) error {
+	child := exec.Command(args[0], args[1:]...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	child.Env = append(os.Environ(), env...)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err := child.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%s exited with code %d", args[0], exitErr.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %v", args[0], err)
+	}
+	return nil
+}
+
+func masterUserSecretARN(secret *rdstypes.MasterUserSecret) string {
+	if secret == nil {
+		return ""
+	}
+	return aws.ToString(secret.SecretArn)
+}
+
+func taggedSecret(tags []rdstypes.Tag) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == dbSecretTag {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDBClientCommand(t *testing.T) {
+	conn := dbConnection{Port: 15432, User: "admin", Password: "s3cret", DBName: "app"}
+
+	tests := []struct {
+		name         string
+		exec         string
+		engine       string
+		conn         dbConnection
+		expectedArgs []string
+		expectedEnv  []string
+		expectError  bool
+	}{
+		{
+			name:         "auto picks psql for postgres",
+			exec:         "auto",
+			engine:       "aurora-postgresql",
+			conn:         conn,
+			expectedArgs: []string{"psql", "-h", "localhost", "-p", "15432", "-U", "admin", "-d", "app"},
+			expectedEnv:  []string{"PGPASSWORD=s3cret"},
+		},
+		{
+			name:         "auto picks mysql for mariadb",
+			exec:         "auto",
+			engine:       "mariadb",
+			conn:         conn,
+			expectedArgs: []string{"mysql", "-h", "127.0.0.1", "-P", "15432", "-u", "admin", "app"},
+			expectedEnv:  []string{"MYSQL_PWD=s3cret"},
+		},
+		{
+			name:         "psql with an IAM token requires SSL",
+			exec:         "psql",
+			engine:       "postgres",
+			conn:         dbConnection{Port: 5432, User: "iam_user", Password: "token", DBName: "postgres", IAM: true},
+			expectedArgs: []string{"psql", "-h", "localhost", "-p", "5432", "-U", "iam_user", "-d", "postgres"},
+			expectedEnv:  []string{"PGSSLMODE=require", "PGPASSWORD=token"},
+		},
+		{
+			name:         "mysql with an IAM token sends it in cleartext over SSL",
+			exec:         "mysql",
+			engine:       "aurora-mysql",
+			conn:         dbConnection{Port: 3306, User: "iam_user", Password: "token", IAM: true},
+			expectedArgs: []string{"mysql", "-h", "127.0.0.1", "-P", "3306", "-u", "iam_user", "--enable-cleartext-plugin", "--ssl-mode=REQUIRED"},
+			expectedEnv:  []string{"MYSQL_PWD=token"},
+		},
+		{
+			name:         "no password leaves the client to prompt",
+			exec:         "psql",
+			engine:       "postgres",
+			conn:         dbConnection{Port: 5432, User: "admin"},
+			expectedArgs: []string{"psql", "-h", "localhost", "-p", "5432", "-U", "admin"},
+		},
+		{
+			name:         "template fills in placeholders",
+			exec:         "pgcli postgres://{user}@{host}:{port}/{dbname}",
+			engine:       "postgres",
+			conn:         conn,
+			expectedArgs: []string{"pgcli", "postgres://admin@localhost:15432/app"},
+			expectedEnv:  []string{"PGPASSWORD=s3cret", "AWSC_DB_PASSWORD=s3cret"},
+		},
+		{
+			name:         "template for an engine without a built-in client",
+			exec:         "sqlcmd -S {host},{port} -U {user}",
+			engine:       "sqlserver-se",
+			conn:         conn,
+			expectedArgs: []string{"sqlcmd", "-S", "localhost,15432", "-U", "admin"},
+			expectedEnv:  []string{"AWSC_DB_PASSWORD=s3cret"},
+		},
+		{
+			name:        "psql against mysql",
+			exec:        "psql",
+			engine:      "mysql",
+			conn:        conn,
+			expectError: true,
+		},
+		{
+			name:        "auto without a built-in client",
+			exec:        "auto",
+			engine:      "oracle-ee",
+			conn:        conn,
+			expectError: true,
+		},
+		{
+			name:        "blank template",
+			exec:        "  ",
+			engine:      "postgres",
+			conn:        conn,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, env, err := dbClientCommand(tt.exec, tt.engine, tt.conn)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if tt.expectError {
+				return
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+			if !reflect.DeepEqual(env, tt.expectedEnv) {
+				t.Errorf("Expected env %v, got %v", tt.expectedEnv, env)
+			}
+			// Passwords must never end up in the argv
+			if tt.conn.Password != "" && strings.Contains(strings.Join(args, " "), tt.conn.Password) {
+				t.Errorf("Password leaked into args: %v", args)
+			}
+		})
+	}
+}
+
+func TestRDSManager_dbCredentials(t *testing.T) {
+	tests := []struct {
+		name           string
+		instance       RDSInstance
+		iamUser        string
+		expectedSecret string
+		secretString   string
+		secretError    error
+		expected       dbConnection
+		expectError    bool
+	}{
+		{
+			name:           "RDS-managed master secret",
+			instance:       RDSInstance{Identifier: "db", Engine: "postgres", MasterUsername: "postgres", DatabaseName: "app", SecretARN: "arn:aws:secretsmanager:us-east-1:123456789012:secret:rds!db-1"},
+			expectedSecret: "arn:aws:secretsmanager:us-east-1:123456789012:secret:rds!db-1",
+			secretString:   `{"username":"postgres","password":"master-pw"}`,
+			expected:       dbConnection{User: "postgres", Password: "master-pw", DBName: "app"},
+		},
+		{
+			name:           "tagged secret wins over the master secret",
+			instance:       RDSInstance{Identifier: "db", Engine: "mysql", MasterUsername: "admin", SecretARN: "arn:master", TaggedSecret: "app/readonly"},
+			expectedSecret: "app/readonly",
+			secretString:   `{"username":"readonly","password":"ro-pw","dbname":"reports"}`,
+			expected:       dbConnection{User: "readonly", Password: "ro-pw", DBName: "reports"},
+		},
+		{
+			name:     "no secret falls back to the master username",
+			instance: RDSInstance{Identifier: "db", Engine: "aurora-postgresql", MasterUsername: "admin"},
+			expected: dbConnection{User: "admin", DBName: "postgres"},
+		},
+		{
+			name:     "IAM user skips the secrets",
+			instance: RDSInstance{Identifier: "db", Endpoint: "db.example.com", Port: 3306, Engine: "mysql", MasterUsername: "admin", SecretARN: "arn:master"},
+			iamUser:  "iam_user",
+			expected: dbConnection{User: "iam_user", IAM: true},
+		},
+		{
+			name:           "unreadable secret",
+			instance:       RDSInstance{Identifier: "db", Engine: "mysql", SecretARN: "arn:master"},
+			expectedSecret: "arn:master",
+			secretError:    errors.New("AccessDeniedException"),
+			expectError:    true,
+		},
+		{
+			name:           "secret that isn't JSON",
+			instance:       RDSInstance{Identifier: "db", Engine: "mysql", TaggedSecret: "plain"},
+			expectedSecret: "plain",
+			secretString:   "hunter2",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSecrets := mocks.NewMockSecretsManagerClient(ctrl)
+			if tt.expectedSecret != "" {
+				mockSecrets.EXPECT().
+					GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{SecretId: aws.String(tt.expectedSecret)}).
+					Return(&secretsmanager.GetSecretValueOutput{Name: aws.String(tt.expectedSecret), SecretString: aws.String(tt.secretString)}, tt.secretError)
+			}
+
+			manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+				RDSClient:     mocks.NewMockRDSClient(ctrl),
+				SecretsClient: mockSecrets,
+				Credentials:   credentials.NewStaticCredentialsProvider("AKIATEST", "secret", ""),
+				Region:        "us-east-1",
+			})
+			if err != nil {
+				t.Fatalf("Failed to create manager: %v", err)
+			}
+
+			conn, err := manager.dbCredentials(context.Background(), tt.instance, tt.iamUser)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if tt.expectError {
+				return
+			}
+			if tt.expected.IAM {
+				if !conn.IAM || !strings.Contains(conn.Password, "Action=connect") {
+					t.Errorf("Expected an IAM auth token, got %+v", conn)
+				}
+				conn.Password = ""
+			}
+			if conn != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, conn)
+			}
+		})
+	}
+}
+
+func TestTaggedSecret(t *testing.T) {
+	tags := []rdstypes.Tag{
+		{Key: aws.String("team"), Value: aws.String("data")},
+		{Key: aws.String(dbSecretTag), Value: aws.String("app/readonly")},
+	}
+	if got := taggedSecret(tags); got != "app/readonly" {
+		t.Errorf("Expected app/readonly, got %q", got)
+	}
+	if got := taggedSecret(nil); got != "" {
+		t.Errorf("Expected no secret without tags, got %q", got)
+	}
+	if got := masterUserSecretARN(nil); got != "" {
+		t.Errorf("Expected no ARN without a master user secret, got %q", got)
+	}
+}
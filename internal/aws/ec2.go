@@ -11,7 +11,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
-	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/blontic/awsc/internal/ui"
 )
 
@@ -24,6 +23,8 @@ type EC2Manager struct {
 	ec2Client EC2Client
 	ssmClient SSMClient
 	region    string
+	regions   []string               // Regions queried with --regions, in order
+	regional  map[string]*EC2Manager // One manager per queried region
 }
 
 type EC2Instance struct {
@@ -33,35 +34,93 @@ type EC2Instance struct {
 	State        string
 	Platform     string
 	IsSelectable bool
+	Region       string
 }
 
 type EC2ManagerOptions struct {
 	EC2Client EC2Client
 	SSMClient SSMClient
 	Region    string
+	Regions   []string // Regions to query instead of the profile's region
 }
 
 func NewEC2Manager(ctx context.Context, opts ...EC2ManagerOptions) (*EC2Manager, error) {
+	var regions []string
+	if len(opts) > 0 {
+		regions = opts[0].Regions
+	}
+
 	if len(opts) > 0 && opts[0].EC2Client != nil {
 		// Use provided clients (for testing)
-		return &EC2Manager{
+		manager := &EC2Manager{
 			ec2Client: opts[0].EC2Client,
 			ssmClient: opts[0].SSMClient,
 			region:    opts[0].Region,
-		}, nil
+		}
+		manager.addRegions(regions, func(region string) *EC2Manager {
+			regional := *manager
+			regional.region = region
+			return &regional
+		})
+		return manager, nil
 	}
 
 	// Production path
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(regions) == 1 {
+		cfg.Region = regions[0]
+	}
 
+	manager := newEC2ManagerFromConfig(cfg)
+	manager.addRegions(regions, func(region string) *EC2Manager {
+		return newEC2ManagerFromConfig(regionalConfig(cfg, region))
+	})
+	return manager, nil
+}
+
+func newEC2ManagerFromConfig(cfg aws.Config) *EC2Manager {
 	return &EC2Manager{
 		ec2Client: ec2.NewFromConfig(cfg),
 		ssmClient: ssm.NewFromConfig(cfg),
 		region:    cfg.Region,
-	}, nil
+	}
+}
+
+// addRegions creates a manager per region when more than one region is queried
+func (e *EC2Manager) addRegions(regions []string, newManager func(region string) *EC2Manager) {
+	if len(regions) == 1 {
+		e.region = regions[0]
+	}
+	if len(regions) < 2 {
+		return
+	}
+
+	// Create the regional managers first, so copies of this manager don't inherit its regions
+	regional := make(map[string]*EC2Manager, len(regions))
+	for _, region := range regions {
+		regional[region] = newManager(region)
+	}
+	e.regions = regions
+	e.regional = regional
+}
+
+// forRegion returns the manager for the region an instance was found in
+func (e *EC2Manager) forRegion(region string) *EC2Manager {
+	if regional, ok := e.regional[region]; ok {
+		return regional
+	}
+	return e
+}
+
+// regionDescription names the queried regions for messages
+func (e *EC2Manager) regionDescription() string {
+	if len(e.regions) > 0 {
+		return strings.Join(e.regions, ", ")
+	}
+	return e.region
 }
 
 func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
@@ -89,7 +148,7 @@ func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
 			fmt.Printf("Connecting to instance: %s (%s)\n", targetInstance.Name, targetInstance.InstanceId)
 
 			// Start SSM session for all instances
-			return e.StartSSMSession(ctx, targetInstance.InstanceId)
+			return e.forRegion(targetInstance.Region).StartSSMSession(ctx, targetInstance.InstanceId)
 		}
 
 		// Instance not found or not selectable - show error and fall through to list
@@ -139,14 +198,14 @@ func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
 		}
 
 		if runningInstances == 0 {
-			fmt.Printf("No running EC2 instances found in region %s.\n", e.region)
+			fmt.Printf("No running EC2 instances found in region %s.\n", e.regionDescription())
 			fmt.Printf("To use EC2 sessions, you need a running EC2 instance with:\n")
 			fmt.Printf("- SSM agent installed and configured\n")
 			fmt.Printf("- Proper IAM permissions for SSM\n")
 			if stoppedInstances > 0 {
 				return fmt.Errorf("no running EC2 instances with SSM agent found - %d stopped instances available", stoppedInstances)
 			}
-			return fmt.Errorf("no running EC2 instances found in region %s", e.region)
+			return fmt.Errorf("no running EC2 instances found in region %s", e.regionDescription())
 		} else {
 			fmt.Printf("Found %d running EC2 instances but none have SSM agent configured.\n", runningInstances)
 			fmt.Printf("Please ensure your instances have:\n")
@@ -163,7 +222,7 @@ func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
 	}
 
 	// Start SSM session for all instances
-	return e.StartSSMSession(ctx, selectedInstance.InstanceId)
+	return e.forRegion(selectedInstance.Region).StartSSMSession(ctx, selectedInstance.InstanceId)
 }
 
 func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort int32) error {
@@ -199,7 +258,7 @@ func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort in
 
 		if targetInstance != nil && targetInstance.IsSelectable {
 			fmt.Printf("Starting RDP to instance: %s (%s)\n", targetInstance.Name, targetInstance.InstanceId)
-			return e.startRDPPortForwarding(ctx, targetInstance.InstanceId, localPort)
+			return e.forRegion(targetInstance.Region).startRDPPortForwarding(ctx, targetInstance.InstanceId, localPort)
 		}
 
 		// Instance not found or not selectable - show error and fall through to list
@@ -217,10 +276,23 @@ func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort in
 	}
 
 	// Start RDP port forwarding
-	return e.startRDPPortForwarding(ctx, selectedInstance.InstanceId, localPort)
+	return e.forRegion(selectedInstance.Region).startRDPPortForwarding(ctx, selectedInstance.InstanceId, localPort)
 }
 
 func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error) {
+	if len(e.regions) > 0 {
+		instances, err := queryRegions(ctx, e.regions, func(ctx context.Context, region string) ([]EC2Instance, error) {
+			return e.regional[region].ListAllInstances(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(instances, func(i, j int) bool {
+			return instances[i].Name < instances[j].Name
+		})
+		return instances, nil
+	}
+
 	var allReservations []types.Reservation
 	var nextToken *string
 
@@ -229,25 +301,7 @@ func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error
 			NextToken: nextToken,
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					// Reload all clients with fresh credentials
-					if reloadErr := e.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					// Retry after re-authentication
-					result, err = e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
-						NextToken: nextToken,
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		allReservations = append(allReservations, result.Reservations...)
@@ -273,6 +327,7 @@ func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error
 				State:        string(inst.State.Name),
 				Platform:     e.getPlatform(inst),
 				IsSelectable: hasSSM, // Only running instances with SSM are selectable
+				Region:       e.region,
 			})
 		}
 	}
@@ -287,12 +342,12 @@ func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error
 
 func (e *EC2Manager) StartSSMSession(ctx context.Context, instanceId string) error {
 	// Start SSM session using external plugin
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	pf := NewExternalPluginForwarder(cfg)
+	pf := NewExternalPluginForwarder(regionalConfig(cfg, e.region))
 
 	// Start interactive session
 	return pf.StartInteractiveSession(ctx, instanceId)
@@ -309,30 +364,7 @@ func (e *EC2Manager) hasSSMAgent(ctx context.Context, instanceId string) bool {
 		},
 	})
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				// Reload all clients with fresh credentials
-				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
-					return false
-				}
-				// Retry after re-authentication
-				result, err = e.ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
-					Filters: []ssmtypes.InstanceInformationStringFilter{
-						{
-							Key:    aws.String("InstanceIds"),
-							Values: []string{instanceId},
-						},
-					},
-				})
-				if err != nil {
-					return false
-				}
-			} else {
-				return false
-			}
-		} else {
-			return false
-		}
+		return false
 	}
 
 	return len(result.InstanceInformationList) > 0
@@ -377,12 +409,12 @@ func (e *EC2Manager) getPlatform(instance types.Instance) string {
 }
 
 func (e *EC2Manager) startRDPPortForwarding(ctx context.Context, instanceId string, localPort int32) error {
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	pf := NewExternalPluginForwarder(cfg)
+	pf := NewExternalPluginForwarder(regionalConfig(cfg, e.region))
 	remotePort := 3389
 
 	fmt.Printf("Starting RDP port forwarding on localhost:%d...\n", localPort)
@@ -391,24 +423,12 @@ func (e *EC2Manager) startRDPPortForwarding(ctx context.Context, instanceId stri
 	return pf.StartPortForwardingToRemoteHost(ctx, instanceId, "localhost", int(remotePort), int(localPort))
 }
 
-func (e *EC2Manager) reloadClients(ctx context.Context) error {
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
-	if err != nil {
-		return err
-	}
-
-	e.ec2Client = ec2.NewFromConfig(cfg)
-	e.ssmClient = ssm.NewFromConfig(cfg)
-	e.region = cfg.Region
-
-	return nil
-}
-
 func (e *EC2Manager) selectInstance(title string, instances []EC2Instance) (*EC2Instance, error) {
 	// Create instance options for selection
 	instanceOptions := make([]string, len(instances))
 	for i, instance := range instances {
-		instanceOptions[i] = fmt.Sprintf("%s (%s) - %s - %s", instance.Name, instance.InstanceId, instance.Platform, instance.State)
+		label := fmt.Sprintf("%s (%s) - %s - %s", instance.Name, instance.InstanceId, instance.Platform, instance.State)
+		instanceOptions[i] = regionLabel(len(e.regions) > 0, instance.Region, label)
 	}
 
 	// Create selectability array
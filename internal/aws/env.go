@@ -0,0 +1,218 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// EnvShells are the output formats supported by FormatEnv
+var EnvShells = []string{"bash", "zsh", "fish", "powershell", "dotenv"}
+
+// SessionCredentials are the resolved credentials of the active profile, for tools that
+// read credentials from the environment instead of ~/.aws/config
+type SessionCredentials struct {
+	Profile         string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time // Zero if the credentials don't expire
+}
+
+// GetSessionCredentials resolves the credentials of the active profile. It never prompts:
+// callers that can interact handle IsAuthError themselves.
+func GetSessionCredentials(ctx context.Context) (*SessionCredentials, error) {
+	profileName, _, err := awscconfig.ResolveProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SessionCredentials{
+		Profile:         profileName,
+		Region:          cfg.Region,
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+	}
+	if creds.CanExpire {
+		result.Expiration = creds.Expires
+	}
+	return result, nil
+}
+
+// Environment returns the variables that carry the credentials, in a stable order
+func (c *SessionCredentials) Environment() [][2]string {
+	vars := [][2]string{
+		{"AWS_ACCESS_KEY_ID", c.AccessKeyID},
+		{"AWS_SECRET_ACCESS_KEY", c.SecretAccessKey},
+	}
+	if c.SessionToken != "" {
+		vars = append(vars, [2]string{"AWS_SESSION_TOKEN", c.SessionToken})
+	}
+	if !c.Expiration.IsZero() {
+		vars = append(vars, [2]string{"AWS_CREDENTIAL_EXPIRATION", c.Expiration.UTC().Format(time.RFC3339)})
+	}
+	if c.Region != "" {
+		vars = append(vars, [2]string{"AWS_REGION", c.Region}, [2]string{"AWS_DEFAULT_REGION", c.Region})
+	}
+	if c.Profile != "" {
+		vars = append(vars, [2]string{"AWSC_PROFILE", c.Profile})
+	}
+	return vars
+}
+
+// DetectShell returns the EnvShells format matching $SHELL, defaulting to bash
+func DetectShell() string {
+	switch filepath.Base(os.Getenv("SHELL")) {
+	case "zsh":
+		return "zsh"
+	case "fish":
+		return "fish"
+	case "pwsh", "powershell":
+		return "powershell"
+	default:
+		return "bash"
+	}
+}
+
+// FormatEnv renders the credentials as statements for the given shell, one per line
+func FormatEnv(creds *SessionCredentials, shell string) (string, error) {
+	var format func(name, value string) string
+	switch shell {
+	case "bash", "zsh":
+		format = func(name, value string) string {
+			return fmt.Sprintf("export %s=%s", name, quotePOSIX(value))
+		}
+	case "fish":
+		format = func(name, value string) string {
+			return fmt.Sprintf("set -gx %s %s;", name, quoteFish(value))
+		}
+	case "powershell":
+		format = func(name, value string) string {
+			return fmt.Sprintf("$Env:%s = '%s'", name, strings.ReplaceAll(value, "'", "''"))
+		}
+	case "dotenv":
+		format = func(name, value string) string {
+			return fmt.Sprintf("%s=%s", name, value)
+		}
+	default:
+		return "", fmt.Errorf("unsupported shell '%s' (expected %s)", shell, strings.Join(EnvShells, ", "))
+	}
+
+	var b strings.Builder
+	for _, v := range creds.Environment() {
+		b.WriteString(format(v[0], v[1]))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+func quotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func quoteFish(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+// RunWithCredentials runs command with the credentials in its environment, forwarding
+// signals to it, and returns its exit code. An error means the command couldn't be started.
+func RunWithCredentials(ctx context.Context, creds *SessionCredentials, command []string) (int, error) {
+	if len(command) == 0 {
+		return 1, fmt.Errorf("no command given")
+	}
+
+	child := exec.Command(command[0], command[1:]...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	child.Env = childEnvironment(os.Environ(), creds)
+
+	// Start listening before the child exists so no signal is lost in between
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if err := child.Start(); err != nil {
+		return 1, fmt.Errorf("failed to start %s: %v", command[0], err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				child.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := child.Wait()
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 1, err
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		// Match the shell convention for children killed by a signal
+		return 128 + int(status.Signal()), nil
+	}
+	return exitErr.ExitCode(), nil
+}
+
+// childEnvironment replaces any AWS profile or credential variables in environ with creds
+func childEnvironment(environ []string, creds *SessionCredentials) []string {
+	replaced := map[string]bool{
+		// A profile would take precedence over the credentials in some tools
+		"AWS_PROFILE":         true,
+		"AWS_DEFAULT_PROFILE": true,
+	}
+	vars := creds.Environment()
+	for _, v := range vars {
+		replaced[v[0]] = true
+	}
+	// Stale values from the parent must not leak into the child when creds omit them. Without
+	// a profile (exec --account), the terminal's AWSC_PROFILE would point awsc in the child
+	// at a different session.
+	for _, name := range []string{"AWS_SESSION_TOKEN", "AWS_CREDENTIAL_EXPIRATION", "AWS_SECURITY_TOKEN", "AWSC_PROFILE"} {
+		replaced[name] = true
+	}
+
+	env := make([]string, 0, len(environ)+len(vars))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if !replaced[name] {
+			env = append(env, entry)
+		}
+	}
+	for _, v := range vars {
+		env = append(env, v[0]+"="+v[1])
+	}
+	return env
+}
@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSessionCredentials() *SessionCredentials {
+	return &SessionCredentials{
+		Profile:         "awsc-dev-Admin",
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKIATEST",
+		SecretAccessKey: "sec'ret",
+		SessionToken:    "token",
+		Expiration:      time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestFormatEnv(t *testing.T) {
+	tests := []struct {
+		shell    string
+		expected []string
+	}{
+		{"bash", []string{"export AWS_ACCESS_KEY_ID='AKIATEST'", `export AWS_SECRET_ACCESS_KEY='sec'\''ret'`, "export AWSC_PROFILE='awsc-dev-Admin'"}},
+		{"zsh", []string{"export AWS_REGION='eu-west-1'", "export AWS_DEFAULT_REGION='eu-west-1'"}},
+		{"fish", []string{"set -gx AWS_ACCESS_KEY_ID 'AKIATEST';", `set -gx AWS_SECRET_ACCESS_KEY 'sec\'ret';`}},
+		{"powershell", []string{"$Env:AWS_ACCESS_KEY_ID = 'AKIATEST'", "$Env:AWS_SECRET_ACCESS_KEY = 'sec''ret'"}},
+		{"dotenv", []string{"AWS_SESSION_TOKEN=token", "AWS_CREDENTIAL_EXPIRATION=2030-01-02T03:04:05Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			output, err := FormatEnv(testSessionCredentials(), tt.shell)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			lines := strings.Split(output, "\n")
+			for _, expected := range tt.expected {
+				found := false
+				for _, line := range lines {
+					if line == expected {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("Expected line %q in:\n%s", expected, output)
+				}
+			}
+		})
+	}
+
+	if _, err := FormatEnv(testSessionCredentials(), "cmd"); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestSessionCredentials_EnvironmentOmitsEmpty(t *testing.T) {
+	creds := &SessionCredentials{AccessKeyID: "AKIATEST", SecretAccessKey: "secret"}
+
+	for _, v := range creds.Environment() {
+		switch v[0] {
+		case "AWS_SESSION_TOKEN", "AWS_CREDENTIAL_EXPIRATION", "AWS_REGION", "AWSC_PROFILE":
+			t.Errorf("Expected %s to be omitted", v[0])
+		}
+	}
+}
+
+func TestChildEnvironment(t *testing.T) {
+	creds := &SessionCredentials{Profile: "awsc-dev-Admin", AccessKeyID: "AKIANEW", SecretAccessKey: "secret"}
+	environ := []string{"PATH=/bin", "AWS_PROFILE=other", "AWS_ACCESS_KEY_ID=AKIAOLD", "AWS_SESSION_TOKEN=stale"}
+
+	env := strings.Join(childEnvironment(environ, creds), "\n")
+
+	for _, expected := range []string{"PATH=/bin", "AWS_ACCESS_KEY_ID=AKIANEW", "AWSC_PROFILE=awsc-dev-Admin"} {
+		if !strings.Contains(env, expected) {
+			t.Errorf("Expected %q in child environment", expected)
+		}
+	}
+	for _, unexpected := range []string{"AWS_PROFILE=", "AKIAOLD", "stale"} {
+		if strings.Contains(env, unexpected) {
+			t.Errorf("Expected %q removed from child environment", unexpected)
+		}
+	}
+}
+
+func TestChildEnvironment_WithoutProfile(t *testing.T) {
+	// exec --account: the terminal's profile belongs to a different session
+	creds := &SessionCredentials{AccessKeyID: "AKIANEW", SecretAccessKey: "secret"}
+	environ := []string{"PATH=/bin", "AWSC_PROFILE=awsc-prod-Admin"}
+
+	env := strings.Join(childEnvironment(environ, creds), "\n")
+	if strings.Contains(env, "AWSC_PROFILE") {
+		t.Errorf("Expected AWSC_PROFILE removed from child environment:\n%s", env)
+	}
+}
+
+func TestRunWithCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	tests := []struct {
+		name         string
+		script       string
+		expectedCode int
+	}{
+		{"success", "exit 0", 0},
+		{"exit code propagated", "exit 3", 3},
+		{"credentials in environment", `test "$AWS_ACCESS_KEY_ID" = AKIATEST && test "$AWSC_PROFILE" = awsc-dev-Admin`, 0},
+		{"killed by signal", "kill -TERM $$", 128 + 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := RunWithCredentials(context.Background(), testSessionCredentials(), []string{"sh", "-c", tt.script})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != tt.expectedCode {
+				t.Errorf("Expected exit code %d, got %d", tt.expectedCode, code)
+			}
+		})
+	}
+
+	if _, err := RunWithCredentials(context.Background(), testSessionCredentials(), []string{"awsc-no-such-command"}); err == nil {
+		t.Error("Expected error for missing command")
+	}
+}
+
+func TestDetectShell(t *testing.T) {
+	tests := map[string]string{
+		"/usr/bin/fish": "fish",
+		"/bin/zsh":      "zsh",
+		"/usr/bin/pwsh": "powershell",
+		"/bin/bash":     "bash",
+		"":              "bash",
+	}
+
+	original := os.Getenv("SHELL")
+	defer os.Setenv("SHELL", original)
+
+	for shellPath, expected := range tests {
+		os.Setenv("SHELL", shellPath)
+		if result := DetectShell(); result != expected {
+			t.Errorf("SHELL=%q: expected %s, got %s", shellPath, expected, result)
+		}
+	}
+}
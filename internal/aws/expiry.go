@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// CheckCredentialExpiry warns when the active credentials expire within the configured
+// margin and offers to log in again to the same account and role. Returns true if a new
+// login happened and clients need to be reloaded.
+func CheckCredentialExpiry(ctx context.Context) (bool, error) {
+	expiration := awscconfig.GetCredentialExpiration()
+	if expiration.IsZero() {
+		return false, nil
+	}
+
+	remaining := time.Until(expiration)
+	if remaining > awscconfig.GetExpiryWarningMargin() {
+		return false, nil
+	}
+
+	if remaining <= 0 {
+		fmt.Fprintf(os.Stderr, "Credentials have expired. Re-authenticate before starting the session? (y/n): ")
+	} else {
+		fmt.Fprintf(os.Stderr, "Credentials expire in %s. Re-authenticate before starting the session? (y/n): ",
+			strings.TrimSuffix(remaining.Round(time.Minute).String(), "0s"))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	if response != "y" && response != "yes" {
+		return false, nil
+	}
+
+	if err := reloginActiveTarget(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reloginActiveTarget logs in again to the account and role, or role chain, of the active profile
+func reloginActiveTarget(ctx context.Context) error {
+	accountName, roleName, chainName := activeLoginTarget()
+
+	ssoManager, err := NewSSOManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create SSO manager: %w", err)
+	}
+
+	if chainName != "" {
+		err = ssoManager.RunLoginChain(ctx, false, chainName)
+	} else {
+		err = ssoManager.RunLogin(ctx, false, accountName, roleName)
+	}
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	return nil
+}
+
+// activeLoginTarget returns the account and role, or the role chain, of the active profile
+// so re-login can skip the selection prompts
+func activeLoginTarget() (string, string, string) {
+	profileName, source, err := awscconfig.ResolveProfile()
+	if err != nil {
+		return "", "", ""
+	}
+
+	metadata, err := awscconfig.GetProfileMetadata(profileName)
+	if err == nil && metadata.Chain != "" {
+		return "", "", metadata.Chain
+	}
+
+	if source == awscconfig.SessionSourcePPID {
+		if session, err := awscconfig.GetCurrentSession(); err == nil {
+			return session.AccountName, session.RoleName, ""
+		}
+	}
+
+	if err != nil {
+		return "", "", ""
+	}
+	return metadata.AccountName, metadata.RoleName, ""
+}
@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/spf13/viper"
+)
+
+func TestCheckCredentialExpiry_NoPrompt(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	tests := []struct {
+		name       string
+		expiration time.Time
+	}{
+		{"profile refreshes its own credentials", time.Time{}},
+		{"expiry outside warning margin", time.Now().Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("AWSC_PROFILE", "")
+
+			if err := awscconfig.SaveSession(currentSessionKey(t), "awsc-prod", "123456789012", "prod", "Admin", tt.expiration); err != nil {
+				t.Fatalf("SaveSession failed: %v", err)
+			}
+
+			relogged, err := CheckCredentialExpiry(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if relogged {
+				t.Error("Expected no re-login")
+			}
+		})
+	}
+}
+
+func TestActiveLoginTarget(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWSC_PROFILE", "")
+
+	if err := awscconfig.SaveSession(currentSessionKey(t), "awsc-prod", "123456789012", "prod", "Admin", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	accountName, roleName, chainName := activeLoginTarget()
+	if accountName != "prod" || roleName != "Admin" || chainName != "" {
+		t.Errorf("Expected prod/Admin, got %s/%s (chain %q)", accountName, roleName, chainName)
+	}
+
+	// Chained profiles re-run the chain
+	if _, err := awscconfig.WriteChainProfile("prod-db", "prod", "123456789012", "db-admin", &types.RoleCredentials{
+		AccessKeyId:     aws.String("AKIA"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+	}); err != nil {
+		t.Fatalf("WriteChainProfile failed: %v", err)
+	}
+	if err := awscconfig.SaveSession(currentSessionKey(t), "awsc-prod-db", "123456789012", "prod", "db-admin", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	_, _, chainName = activeLoginTarget()
+	if chainName != "prod-db" {
+		t.Errorf("Expected chain prod-db, got %q", chainName)
+	}
+}
+
+// currentSessionKey returns the session key the code under test resolves for this process
+func currentSessionKey(t *testing.T) awscconfig.SessionKey {
+	t.Helper()
+	key, err := awscconfig.CurrentSessionKey()
+	if err != nil {
+		t.Fatalf("CurrentSessionKey failed: %v", err)
+	}
+	return key
+}
@@ -8,33 +8,118 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/blontic/awsc/internal/debug"
 )
 
 // ExternalPluginForwarder uses the external session-manager-plugin binary
 type ExternalPluginForwarder struct {
 	ssmClient *ssm.Client
 	region    string
+	endpoint  string // SSM endpoint in the region's partition, passed to the plugin
 }
 
 func NewExternalPluginForwarder(cfg aws.Config) *ExternalPluginForwarder {
 	return &ExternalPluginForwarder{
 		ssmClient: ssm.NewFromConfig(cfg),
 		region:    cfg.Region,
+		endpoint:  ssmEndpointURL(cfg),
 	}
 }
 
+// ssmEndpointURL returns the SSM endpoint for cfg, as the AWS CLI passes it to the plugin.
+// The plugin otherwise assumes the aws partition's domain, which fails in GovCloud and China.
+func ssmEndpointURL(cfg aws.Config) string {
+	if cfg.BaseEndpoint != nil {
+		return *cfg.BaseEndpoint
+	}
+	if cfg.Region == "" {
+		return ""
+	}
+
+	endpoint, err := ssm.NewDefaultEndpointResolverV2().ResolveEndpoint(context.Background(), ssm.EndpointParameters{
+		Region: aws.String(cfg.Region),
+	})
+	if err != nil {
+		debug.Printf("Failed to resolve the SSM endpoint for %s: %v\n", cfg.Region, err)
+		return ""
+	}
+	return endpoint.URI.String()
+}
+
 func (pf *ExternalPluginForwarder) StartPortForwardingToRemoteHost(ctx context.Context, bastionId, remoteHost string, remotePort, localPort int) error {
+	cmd, _, err := pf.portForwardingCommand(ctx, bastionId, remoteHost, remotePort, localPort)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	// Start the plugin and wait for it to complete
+	return cmd.Run()
+}
+
+// RunWithPortForwarding opens the same tunnel as StartPortForwardingToRemoteHost in the
+// background, calls run once the local port accepts connections, and tears the tunnel down
+// when run returns
+func (pf *ExternalPluginForwarder) RunWithPortForwarding(ctx context.Context, bastionId, remoteHost string, remotePort, localPort int, run func() error) error {
+	cmd, sessionId, err := pf.portForwardingCommand(ctx, bastionId, remoteHost, remotePort, localPort)
+	if err != nil {
+		return err
+	}
+
+	// The plugin's connection messages would interleave with the client's output, and
+	// terminal signals meant for the client must not close the tunnel
+	cmd.Stdout = debugWriter{}
+	cmd.Stderr = debugWriter{}
+	detachFromTerminal(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start session-manager-plugin: %v", err)
+	}
+
+	var waitErr error
+	exited := make(chan struct{})
+	go func() {
+		waitErr = cmd.Wait()
+		close(exited)
+	}()
+	defer pf.stopPortForwarding(cmd, sessionId, exited)
+
+	if err := waitForLocalPort(ctx, localPort, exited, tunnelReadyTimeout); err != nil {
+		select {
+		case <-exited:
+			if waitErr != nil {
+				return fmt.Errorf("%v: %v", err, waitErr)
+			}
+		default:
+		}
+		return err
+	}
+
+	return run()
+}
+
+// portForwardingCommand starts an SSM port forwarding session to remoteHost through the
+// bastion and returns the plugin command that carries it, not yet started
+func (pf *ExternalPluginForwarder) portForwardingCommand(ctx context.Context, bastionId, remoteHost string, remotePort, localPort int) (*exec.Cmd, string, error) {
 	// Check if session-manager-plugin is available
 	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
-		return pf.handleMissingPlugin()
+		return nil, "", pf.handleMissingPlugin()
 	}
 
 	// Check if local port is available
 	if err := pf.checkPortAvailable(localPort); err != nil {
-		return err
+		return nil, "", err
+	}
+
+	if err := ensureCredentialsValid(ctx); err != nil {
+		return nil, "", err
 	}
 
 	// Start SSM session
@@ -50,7 +135,7 @@ func (pf *ExternalPluginForwarder) StartPortForwardingToRemoteHost(ctx context.C
 
 	result, err := pf.ssmClient.StartSession(ctx, sessionInput)
 	if err != nil {
-		return fmt.Errorf("failed to start SSM session: %w", err)
+		return nil, "", fmt.Errorf("failed to start SSM session: %w", err)
 	}
 
 	// Prepare session response for plugin
@@ -71,14 +156,71 @@ func (pf *ExternalPluginForwarder) StartPortForwardingToRemoteHost(ctx context.C
 		"StartSession",       // Operation
 		"",                   // Profile (empty)
 		parametersJson,       // Parameters
-		"")                   // Endpoint (empty)
+		pf.endpoint)          // Endpoint
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+	return cmd, *result.SessionId, nil
+}
 
-	// Start the plugin and wait for it to complete
-	return cmd.Run()
+// stopPortForwarding stops the plugin, killing it if it doesn't exit in time, and ends the
+// SSM session so it doesn't linger until the idle timeout
+func (pf *ExternalPluginForwarder) stopPortForwarding(cmd *exec.Cmd, sessionId string, exited <-chan struct{}) {
+	select {
+	case <-exited:
+	default:
+		interruptProcess(cmd.Process)
+		select {
+		case <-exited:
+		case <-time.After(pluginStopTimeout):
+			cmd.Process.Kill()
+			<-exited
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginStopTimeout)
+	defer cancel()
+	if _, err := pf.ssmClient.TerminateSession(ctx, &ssm.TerminateSessionInput{SessionId: aws.String(sessionId)}); err != nil {
+		debug.Printf("Failed to terminate SSM session %s: %v\n", sessionId, err)
+	}
+}
+
+const (
+	// tunnelReadyTimeout is how long to wait for the plugin to accept local connections
+	tunnelReadyTimeout = 30 * time.Second
+	// pluginStopTimeout is how long the plugin gets to exit after an interrupt
+	pluginStopTimeout = 5 * time.Second
+)
+
+// waitForLocalPort polls 127.0.0.1:port until it accepts a connection. It fails early when
+// exited is closed, since the tunnel will never come up.
+func waitForLocalPort(ctx context.Context, port int, exited <-chan struct{}, timeout time.Duration) error {
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("tunnel on port %d wasn't ready after %s", port, timeout)
+		}
+
+		select {
+		case <-exited:
+			return fmt.Errorf("session-manager-plugin exited before the tunnel was ready")
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// debugWriter sends output to the verbose debug log
+type debugWriter struct{}
+
+func (debugWriter) Write(p []byte) (int, error) {
+	debug.Printf("%s", p)
+	return len(p), nil
 }
 
 func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context, instanceId string) error {
@@ -87,6 +229,10 @@ func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context,
 		return pf.handleMissingPlugin()
 	}
 
+	if err := ensureCredentialsValid(ctx); err != nil {
+		return err
+	}
+
 	// Start SSM session
 	sessionInput := &ssm.StartSessionInput{
 		Target: aws.String(instanceId),
@@ -114,7 +260,7 @@ func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context,
 		"StartSession",       // Operation
 		"",                   // Profile (empty)
 		parametersJson,       // Parameters
-		"")                   // Endpoint (empty)
+		pf.endpoint)          // Endpoint
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -124,6 +270,21 @@ func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context,
 	return cmd.Run()
 }
 
+// ensureCredentialsValid offers re-login if credentials expire soon, before work that can't
+// pick up new ones: a running plugin session, or an IAM auth token signed up front
+func ensureCredentialsValid(ctx context.Context) error {
+	relogged, err := CheckCredentialExpiry(ctx)
+	if err != nil {
+		return err
+	}
+	if !relogged {
+		return nil
+	}
+
+	// Switch every client, including the SSM and RDS ones, to the new credentials
+	return sharedReauth.reload(ctx)
+}
+
 func (pf *ExternalPluginForwarder) checkPortAvailable(port int) error {
 	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
 	if err != nil {
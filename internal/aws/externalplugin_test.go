@@ -1,7 +1,10 @@
 package aws
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
@@ -21,6 +24,28 @@ func TestNewExternalPluginForwarder(t *testing.T) {
 	}
 }
 
+func TestSSMEndpointURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      aws.Config
+		expected string
+	}{
+		{"aws partition", aws.Config{Region: "eu-west-1"}, "https://ssm.eu-west-1.amazonaws.com"},
+		{"govcloud", aws.Config{Region: "us-gov-west-1"}, "https://ssm.us-gov-west-1.amazonaws.com"},
+		{"china", aws.Config{Region: "cn-north-1"}, "https://ssm.cn-north-1.amazonaws.com.cn"},
+		{"custom endpoint", aws.Config{Region: "us-east-1", BaseEndpoint: aws.String("https://ssm.internal.example")}, "https://ssm.internal.example"},
+		{"no region", aws.Config{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ssmEndpointURL(tt.cfg); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestExternalPluginForwarder_handleMissingPlugin(t *testing.T) {
 	forwarder := &ExternalPluginForwarder{
 		region: "us-east-1",
@@ -46,3 +71,43 @@ func TestExternalPluginForwarder_StartInteractiveSession(t *testing.T) {
 	// and valid AWS credentials
 	t.Skip("Skipping StartInteractiveSession test - requires session-manager-plugin and AWS credentials")
 }
+
+func TestWaitForLocalPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	openPort := listener.Addr().(*net.TCPAddr).Port
+	defer listener.Close()
+
+	// A port that was free a moment ago, so nothing accepts on it
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	exited := make(chan struct{})
+	close(exited)
+
+	tests := []struct {
+		name        string
+		port        int
+		exited      <-chan struct{}
+		expectError bool
+	}{
+		{"tunnel accepting connections", openPort, make(chan struct{}), false},
+		{"plugin exited", closedPort, exited, true},
+		{"timed out", closedPort, make(chan struct{}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := waitForLocalPort(context.Background(), tt.port, tt.exited, 500*time.Millisecond)
+			if (err != nil) != tt.expectError {
+				t.Errorf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
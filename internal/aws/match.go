@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// matchName finds the name a user meant by query, trying in order: an exact ID, an exact
+// name (case-insensitive), a name prefix, a name substring and finally the query's letters
+// in order within a name (e.g. "prdadm" for "prod-admin"). It returns -1 if nothing matches
+// and an error if the first kind of match that finds anything finds several names.
+// ids may be nil for things without IDs, like roles.
+func matchName(query string, names, ids []string) (int, error) {
+	if query == "" {
+		return -1, nil
+	}
+	lowerQuery := strings.ToLower(query)
+
+	for i, id := range ids {
+		if id == query {
+			return i, nil
+		}
+	}
+
+	matchers := []func(name string) bool{
+		func(name string) bool { return name == lowerQuery },
+		func(name string) bool { return strings.HasPrefix(name, lowerQuery) },
+		func(name string) bool { return strings.Contains(name, lowerQuery) },
+		func(name string) bool { return isSubsequence(lowerQuery, name) },
+	}
+
+	for _, matches := range matchers {
+		var found []int
+		for i, name := range names {
+			if matches(strings.ToLower(name)) {
+				found = append(found, i)
+			}
+		}
+
+		switch {
+		case len(found) == 1:
+			return found[0], nil
+		case len(found) > 1:
+			candidates := make([]string, len(found))
+			for i, index := range found {
+				candidates[i] = names[index]
+			}
+			return -1, fmt.Errorf("'%s' is ambiguous, it matches %s", query, strings.Join(candidates, ", "))
+		}
+	}
+
+	return -1, nil
+}
+
+// isSubsequence reports whether the characters of query appear in order in s
+func isSubsequence(query, s string) bool {
+	remaining := []rune(query)
+	for _, r := range s {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
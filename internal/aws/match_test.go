@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchName(t *testing.T) {
+	names := []string{"Development", "Production", "Production-EU", "Shared-Services", "Staging"}
+	ids := []string{"111111111111", "222222222222", "333333333333", "444444444444", "555555555555"}
+
+	tests := []struct {
+		name      string
+		query     string
+		ids       []string
+		want      int
+		ambiguous bool
+	}{
+		{name: "empty query", query: "", ids: ids, want: -1},
+		{name: "account ID", query: "444444444444", ids: ids, want: 3},
+		{name: "exact name wins over prefix", query: "production", ids: ids, want: 1},
+		{name: "unique prefix", query: "dev", ids: ids, want: 0},
+		{name: "ambiguous prefix", query: "prod", ids: ids, ambiguous: true, want: -1},
+		{name: "ambiguous prefix across names", query: "s", ids: ids, ambiguous: true, want: -1},
+		{name: "substring", query: "services", ids: ids, want: 3},
+		{name: "subsequence", query: "stgng", ids: ids, want: 4},
+		{name: "no match", query: "sandbox", ids: ids, want: -1},
+		{name: "IDs not given", query: "444444444444", ids: nil, want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchName(tt.query, names, tt.ids)
+			if (err != nil) != tt.ambiguous {
+				t.Fatalf("matchName(%q) error = %v, ambiguous %v", tt.query, err, tt.ambiguous)
+			}
+			if got != tt.want {
+				t.Errorf("matchName(%q) = %d, want %d", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchName_AmbiguityError(t *testing.T) {
+	_, err := matchName("prod", []string{"Production", "Production-EU", "Staging"}, nil)
+	if err == nil {
+		t.Fatal("Expected ambiguity error")
+	}
+	for _, expected := range []string{"'prod' is ambiguous", "Production, Production-EU"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestIsSubsequence(t *testing.T) {
+	tests := []struct {
+		query, s string
+		want     bool
+	}{
+		{"prdadm", "prod-admin", true},
+		{"", "anything", true},
+		{"admprd", "prod-admin", false},
+		{"prod-admins", "prod-admin", false},
+	}
+
+	for _, tt := range tests {
+		if got := isSubsequence(tt.query, tt.s); got != tt.want {
+			t.Errorf("isSubsequence(%q, %q) = %v, want %v", tt.query, tt.s, got, tt.want)
+		}
+	}
+}
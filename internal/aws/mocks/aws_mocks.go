@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/blontic/awsc/internal/aws (interfaces: RDSClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient)
+// Source: github.com/blontic/awsc/internal/aws (interfaces: RDSClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient,OIDCClient,SSOClient,STSClient)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks/aws_mocks.go -package=mocks . RDSClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient
+//	mockgen -destination=mocks/aws_mocks.go -package=mocks . RDSClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient,OIDCClient,SSOClient,STSClient
 //
 
 // Package mocks is a generated GoMock package.
@@ -18,6 +18,9 @@ import (
 	rds "github.com/aws/aws-sdk-go-v2/service/rds"
 	secretsmanager "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	ssm "github.com/aws/aws-sdk-go-v2/service/ssm"
+	sso "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssooidc "github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -45,6 +48,26 @@ func (m *MockRDSClient) EXPECT() *MockRDSClientMockRecorder {
 	return m.recorder
 }
 
+// DescribeDBClusterEndpoints mocks base method.
+func (m *MockRDSClient) DescribeDBClusterEndpoints(ctx context.Context, params *rds.DescribeDBClusterEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBClusterEndpoints", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBClusterEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBClusterEndpoints indicates an expected call of DescribeDBClusterEndpoints.
+func (mr *MockRDSClientMockRecorder) DescribeDBClusterEndpoints(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBClusterEndpoints", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBClusterEndpoints), varargs...)
+}
+
 // DescribeDBClusters mocks base method.
 func (m *MockRDSClient) DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +108,46 @@ func (mr *MockRDSClientMockRecorder) DescribeDBInstances(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBInstances", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBInstances), varargs...)
 }
 
+// DescribeDBProxies mocks base method.
+func (m *MockRDSClient) DescribeDBProxies(ctx context.Context, params *rds.DescribeDBProxiesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxiesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBProxies", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBProxiesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBProxies indicates an expected call of DescribeDBProxies.
+func (mr *MockRDSClientMockRecorder) DescribeDBProxies(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBProxies", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBProxies), varargs...)
+}
+
+// DescribeDBProxyEndpoints mocks base method.
+func (m *MockRDSClient) DescribeDBProxyEndpoints(ctx context.Context, params *rds.DescribeDBProxyEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxyEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBProxyEndpoints", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBProxyEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBProxyEndpoints indicates an expected call of DescribeDBProxyEndpoints.
+func (mr *MockRDSClientMockRecorder) DescribeDBProxyEndpoints(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBProxyEndpoints", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBProxyEndpoints), varargs...)
+}
+
 // MockEC2Client is a mock of EC2Client interface.
 type MockEC2Client struct {
 	ctrl     *gomock.Controller
@@ -129,6 +192,26 @@ func (mr *MockEC2ClientMockRecorder) DescribeInstances(ctx, params any, optFns .
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstances", reflect.TypeOf((*MockEC2Client)(nil).DescribeInstances), varargs...)
 }
 
+// DescribeRegions mocks base method.
+func (m *MockEC2Client) DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeRegions", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeRegionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeRegions indicates an expected call of DescribeRegions.
+func (mr *MockEC2ClientMockRecorder) DescribeRegions(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRegions", reflect.TypeOf((*MockEC2Client)(nil).DescribeRegions), varargs...)
+}
+
 // DescribeSecurityGroups mocks base method.
 func (m *MockEC2Client) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
 	m.ctrl.T.Helper()
@@ -320,3 +403,255 @@ func (mr *MockOpenSearchClientMockRecorder) ListDomainNames(ctx, params any, opt
 	varargs := append([]any{ctx, params}, optFns...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomainNames", reflect.TypeOf((*MockOpenSearchClient)(nil).ListDomainNames), varargs...)
 }
+
+// MockOIDCClient is a mock of OIDCClient interface.
+type MockOIDCClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCClientMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCClientMockRecorder is the mock recorder for MockOIDCClient.
+type MockOIDCClientMockRecorder struct {
+	mock *MockOIDCClient
+}
+
+// NewMockOIDCClient creates a new mock instance.
+func NewMockOIDCClient(ctrl *gomock.Controller) *MockOIDCClient {
+	mock := &MockOIDCClient{ctrl: ctrl}
+	mock.recorder = &MockOIDCClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCClient) EXPECT() *MockOIDCClientMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *MockOIDCClient) CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateToken", varargs...)
+	ret0, _ := ret[0].(*ssooidc.CreateTokenOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockOIDCClientMockRecorder) CreateToken(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockOIDCClient)(nil).CreateToken), varargs...)
+}
+
+// RegisterClient mocks base method.
+func (m *MockOIDCClient) RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterClient", varargs...)
+	ret0, _ := ret[0].(*ssooidc.RegisterClientOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterClient indicates an expected call of RegisterClient.
+func (mr *MockOIDCClientMockRecorder) RegisterClient(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClient", reflect.TypeOf((*MockOIDCClient)(nil).RegisterClient), varargs...)
+}
+
+// StartDeviceAuthorization mocks base method.
+func (m *MockOIDCClient) StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartDeviceAuthorization", varargs...)
+	ret0, _ := ret[0].(*ssooidc.StartDeviceAuthorizationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartDeviceAuthorization indicates an expected call of StartDeviceAuthorization.
+func (mr *MockOIDCClientMockRecorder) StartDeviceAuthorization(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDeviceAuthorization", reflect.TypeOf((*MockOIDCClient)(nil).StartDeviceAuthorization), varargs...)
+}
+
+// MockSSOClient is a mock of SSOClient interface.
+type MockSSOClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSOClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSSOClientMockRecorder is the mock recorder for MockSSOClient.
+type MockSSOClientMockRecorder struct {
+	mock *MockSSOClient
+}
+
+// NewMockSSOClient creates a new mock instance.
+func NewMockSSOClient(ctrl *gomock.Controller) *MockSSOClient {
+	mock := &MockSSOClient{ctrl: ctrl}
+	mock.recorder = &MockSSOClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSOClient) EXPECT() *MockSSOClientMockRecorder {
+	return m.recorder
+}
+
+// GetRoleCredentials mocks base method.
+func (m *MockSSOClient) GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRoleCredentials", varargs...)
+	ret0, _ := ret[0].(*sso.GetRoleCredentialsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleCredentials indicates an expected call of GetRoleCredentials.
+func (mr *MockSSOClientMockRecorder) GetRoleCredentials(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleCredentials", reflect.TypeOf((*MockSSOClient)(nil).GetRoleCredentials), varargs...)
+}
+
+// ListAccountRoles mocks base method.
+func (m *MockSSOClient) ListAccountRoles(ctx context.Context, params *sso.ListAccountRolesInput, optFns ...func(*sso.Options)) (*sso.ListAccountRolesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccountRoles", varargs...)
+	ret0, _ := ret[0].(*sso.ListAccountRolesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountRoles indicates an expected call of ListAccountRoles.
+func (mr *MockSSOClientMockRecorder) ListAccountRoles(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountRoles", reflect.TypeOf((*MockSSOClient)(nil).ListAccountRoles), varargs...)
+}
+
+// ListAccounts mocks base method.
+func (m *MockSSOClient) ListAccounts(ctx context.Context, params *sso.ListAccountsInput, optFns ...func(*sso.Options)) (*sso.ListAccountsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccounts", varargs...)
+	ret0, _ := ret[0].(*sso.ListAccountsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockSSOClientMockRecorder) ListAccounts(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockSSOClient)(nil).ListAccounts), varargs...)
+}
+
+// Logout mocks base method.
+func (m *MockSSOClient) Logout(ctx context.Context, params *sso.LogoutInput, optFns ...func(*sso.Options)) (*sso.LogoutOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Logout", varargs...)
+	ret0, _ := ret[0].(*sso.LogoutOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockSSOClientMockRecorder) Logout(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockSSOClient)(nil).Logout), varargs...)
+}
+
+// MockSTSClient is a mock of STSClient interface.
+type MockSTSClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSTSClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSTSClientMockRecorder is the mock recorder for MockSTSClient.
+type MockSTSClientMockRecorder struct {
+	mock *MockSTSClient
+}
+
+// NewMockSTSClient creates a new mock instance.
+func NewMockSTSClient(ctrl *gomock.Controller) *MockSTSClient {
+	mock := &MockSTSClient{ctrl: ctrl}
+	mock.recorder = &MockSTSClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSTSClient) EXPECT() *MockSTSClientMockRecorder {
+	return m.recorder
+}
+
+// AssumeRole mocks base method.
+func (m *MockSTSClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AssumeRole", varargs...)
+	ret0, _ := ret[0].(*sts.AssumeRoleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssumeRole indicates an expected call of AssumeRole.
+func (mr *MockSTSClientMockRecorder) AssumeRole(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssumeRole", reflect.TypeOf((*MockSTSClient)(nil).AssumeRole), varargs...)
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockSTSClient) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockSTSClientMockRecorder) GetCallerIdentity(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockSTSClient)(nil).GetCallerIdentity), varargs...)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/opensearch"
 	ssmservice "github.com/aws/aws-sdk-go-v2/service/ssm"
-	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/blontic/awsc/internal/debug"
 	"github.com/blontic/awsc/internal/ui"
 )
@@ -26,6 +25,8 @@ type OpenSearchManager struct {
 	ec2Client        EC2Client
 	ssmClient        *ssmservice.Client
 	region           string
+	regions          []string                      // Regions queried with --regions, in order
+	regional         map[string]*OpenSearchManager // One manager per queried region
 }
 
 type OpenSearchDomain struct {
@@ -33,6 +34,7 @@ type OpenSearchDomain struct {
 	Endpoint string
 	Port     int32
 	Version  string
+	Region   string
 }
 
 type OpenSearchManagerOptions struct {
@@ -40,31 +42,80 @@ type OpenSearchManagerOptions struct {
 	EC2Client        EC2Client
 	SSMClient        *ssmservice.Client
 	Region           string
+	Regions          []string // Regions to query instead of the profile's region
 }
 
 func NewOpenSearchManager(ctx context.Context, opts ...OpenSearchManagerOptions) (*OpenSearchManager, error) {
+	var regions []string
+	if len(opts) > 0 {
+		regions = opts[0].Regions
+	}
+
 	if len(opts) > 0 && opts[0].OpenSearchClient != nil {
 		// Use provided clients (for testing)
-		return &OpenSearchManager{
+		manager := &OpenSearchManager{
 			opensearchClient: opts[0].OpenSearchClient,
 			ec2Client:        opts[0].EC2Client,
 			ssmClient:        opts[0].SSMClient,
 			region:           opts[0].Region,
-		}, nil
+		}
+		manager.addRegions(regions, func(region string) *OpenSearchManager {
+			regional := *manager
+			regional.region = region
+			return &regional
+		})
+		return manager, nil
 	}
 
 	// Production path
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(regions) == 1 {
+		cfg.Region = regions[0]
+	}
 
+	manager := newOpenSearchManagerFromConfig(cfg)
+	manager.addRegions(regions, func(region string) *OpenSearchManager {
+		return newOpenSearchManagerFromConfig(regionalConfig(cfg, region))
+	})
+	return manager, nil
+}
+
+func newOpenSearchManagerFromConfig(cfg aws.Config) *OpenSearchManager {
 	return &OpenSearchManager{
 		opensearchClient: opensearch.NewFromConfig(cfg),
 		ec2Client:        ec2.NewFromConfig(cfg),
 		ssmClient:        ssmservice.NewFromConfig(cfg),
 		region:           cfg.Region,
-	}, nil
+	}
+}
+
+// addRegions creates a manager per region when more than one region is queried
+func (o *OpenSearchManager) addRegions(regions []string, newManager func(region string) *OpenSearchManager) {
+	if len(regions) == 1 {
+		o.region = regions[0]
+	}
+	if len(regions) < 2 {
+		return
+	}
+
+	// Create the regional managers first, so copies of this manager don't inherit its regions
+	regional := make(map[string]*OpenSearchManager, len(regions))
+	for _, region := range regions {
+		regional[region] = newManager(region)
+	}
+	o.regions = regions
+	o.regional = regional
+}
+
+// forRegion returns the manager for the region a domain was found in
+func (o *OpenSearchManager) forRegion(region string) *OpenSearchManager {
+	if regional, ok := o.regional[region]; ok {
+		return regional
+	}
+	return o
 }
 
 func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, localPort int32) error {
@@ -82,18 +133,21 @@ func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, l
 
 	// If domain name provided, try to connect directly
 	if domainName != "" {
-		var targetDomain *OpenSearchDomain
+		var matches []OpenSearchDomain
 		for _, domain := range domains {
 			if domain.Name == domainName {
-				targetDomain = &domain
-				break
+				matches = append(matches, domain)
 			}
 		}
 
-		if targetDomain != nil {
-			fmt.Printf("Connecting to OpenSearch domain: %s\n", targetDomain.Name)
-			selectedDomain = *targetDomain
-		} else {
+		switch {
+		case len(matches) == 1:
+			fmt.Printf("Connecting to OpenSearch domain: %s\n", matches[0].Name)
+			selectedDomain = matches[0]
+		case len(matches) > 1:
+			fmt.Printf("OpenSearch domain '%s' exists in several regions:\n\n", domainName)
+			domains = matches
+		default:
 			fmt.Printf("OpenSearch domain '%s' not found. Available domains:\n\n", domainName)
 			// Fall through to show list of available domains
 		}
@@ -104,7 +158,7 @@ func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, l
 		// Create domain options for selection
 		domainOptions := make([]string, len(domains))
 		for i, domain := range domains {
-			domainOptions[i] = fmt.Sprintf("%s (%s)", domain.Name, domain.Version)
+			domainOptions[i] = regionLabel(len(o.regions) > 0, domain.Region, fmt.Sprintf("%s (%s)", domain.Name, domain.Version))
 		}
 
 		// Interactive domain selection
@@ -122,8 +176,11 @@ func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, l
 		fmt.Printf("✓ Selected: %s\n", selectedDomain.Name)
 	}
 
+	// Bastions and the SSM session must be in the domain's region
+	regional := o.forRegion(selectedDomain.Region)
+
 	// Find bastion hosts
-	bastions, err := o.FindBastionHosts(ctx, selectedDomain)
+	bastions, err := regional.FindBastionHosts(ctx, selectedDomain)
 	if err != nil {
 		return err
 	}
@@ -137,28 +194,20 @@ func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, l
 	fmt.Printf("Using bastion: %s\n", bastion.Name)
 
 	// Start port forwarding
-	return o.StartPortForwarding(ctx, bastion.InstanceId, selectedDomain.Endpoint, selectedDomain.Port, localPort)
+	return regional.StartPortForwarding(ctx, bastion.InstanceId, selectedDomain.Endpoint, selectedDomain.Port, localPort)
 }
 
 func (o *OpenSearchManager) ListOpenSearchDomains(ctx context.Context) ([]OpenSearchDomain, error) {
+	if len(o.regions) > 0 {
+		return queryRegions(ctx, o.regions, func(ctx context.Context, region string) ([]OpenSearchDomain, error) {
+			return o.regional[region].ListOpenSearchDomains(ctx)
+		})
+	}
+
 	// List domain names
 	result, err := o.opensearchClient.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-					return nil, reloadErr
-				}
-				result, err = o.opensearchClient.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var domains []OpenSearchDomain
@@ -172,25 +221,12 @@ func (o *OpenSearchManager) ListOpenSearchDomains(ctx context.Context) ([]OpenSe
 			DomainName: domainInfo.DomainName,
 		})
 		if err != nil {
+			// Re-login was declined, so every remaining domain would fail the same way
 			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					domainDetail, err = o.opensearchClient.DescribeDomain(ctx, &opensearch.DescribeDomainInput{
-						DomainName: domainInfo.DomainName,
-					})
-					if err != nil {
-						debug.Printf("Error describing domain %s: %v\n", *domainInfo.DomainName, err)
-						continue
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				debug.Printf("Error describing domain %s: %v\n", *domainInfo.DomainName, err)
-				continue
+				return nil, err
 			}
+			debug.Printf("Error describing domain %s: %v\n", *domainInfo.DomainName, err)
+			continue
 		}
 
 		domain := domainDetail.DomainStatus
@@ -237,6 +273,7 @@ func (o *OpenSearchManager) ListOpenSearchDomains(ctx context.Context) ([]OpenSe
 			Endpoint: endpoint,
 			Port:     port,
 			Version:  version,
+			Region:   o.region,
 		})
 	}
 
@@ -261,23 +298,7 @@ func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSea
 			NextToken: nextToken,
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					result, err = o.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
-						NextToken: nextToken,
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		allReservations = append(allReservations, result.Reservations...)
@@ -367,12 +388,12 @@ func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSea
 
 func (o *OpenSearchManager) StartPortForwarding(ctx context.Context, bastionId, opensearchEndpoint string, opensearchPort, localPort int32) error {
 	// Create port forwarder
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	pf := NewExternalPluginForwarder(cfg)
+	pf := NewExternalPluginForwarder(regionalConfig(cfg, o.region))
 
 	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
 
@@ -385,23 +406,7 @@ func (o *OpenSearchManager) getOpenSearchSecurityGroups(ctx context.Context, dom
 		DomainName: aws.String(domain.Name),
 	})
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-					return nil, reloadErr
-				}
-				result, err = o.opensearchClient.DescribeDomain(ctx, &opensearch.DescribeDomainInput{
-					DomainName: aws.String(domain.Name),
-				})
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	if result.DomainStatus == nil || result.DomainStatus.VPCOptions == nil {
@@ -432,26 +437,8 @@ func (o *OpenSearchManager) checkSecurityGroupRules(ctx context.Context, opensea
 		GroupIds: []string{opensearchSgId},
 	})
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-					debug.Printf("  Error reloading clients: %v\n", reloadErr)
-					return false
-				}
-				result, err = o.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
-					GroupIds: []string{opensearchSgId},
-				})
-				if err != nil {
-					debug.Printf("  Error describing security group %s after retry: %v\n", opensearchSgId, err)
-					return false
-				}
-			} else {
-				return false
-			}
-		} else {
-			debug.Printf("  Error describing security group %s: %v\n", opensearchSgId, err)
-			return false
-		}
+		debug.Printf("  Error describing security group %s: %v\n", opensearchSgId, err)
+		return false
 	}
 
 	if len(result.SecurityGroups) == 0 {
@@ -519,17 +506,3 @@ func (o *OpenSearchManager) getSecurityGroupIds(sgs []types.GroupIdentifier) []s
 	}
 	return ids
 }
-
-func (o *OpenSearchManager) reloadClients(ctx context.Context) error {
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
-	if err != nil {
-		return err
-	}
-
-	o.opensearchClient = opensearch.NewFromConfig(cfg)
-	o.ec2Client = ec2.NewFromConfig(cfg)
-	o.ssmClient = ssmservice.NewFromConfig(cfg)
-	o.region = cfg.Region
-
-	return nil
-}
@@ -0,0 +1,177 @@
+package aws
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// pkceLoginTimeout is how long to wait for the browser to return to the callback listener
+var pkceLoginTimeout = 5 * time.Minute
+
+// authenticatePKCE runs the authorization code flow with PKCE, receiving the code on a
+// temporary 127.0.0.1 listener
+func (c *CredentialsManager) authenticatePKCE(ctx context.Context, startURL, ssoRegion string) error {
+	registration, err := c.getClientRegistration(ctx, startURL, awscconfig.LoginMethodPKCE)
+	if err != nil {
+		return fmt.Errorf("failed to register client: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start callback listener: %v", err)
+	}
+	defer listener.Close()
+
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/oauth/callback", listener.Addr().(*net.TCPAddr).Port)
+
+	verifier, challenge, err := newPKCEChallenge()
+	if err != nil {
+		return err
+	}
+	state, err := randomURLSafeString(32)
+	if err != nil {
+		return err
+	}
+
+	authorizeURL, err := buildAuthorizeURL(ctx, ssoRegion, registration.ClientID, redirectURI, state, challenge)
+	if err != nil {
+		return err
+	}
+
+	presentLoginURL(authorizeURL, "")
+	fmt.Fprintf(os.Stderr, "Waiting for authentication (timeout in %d minutes)...\n", int(pkceLoginTimeout.Minutes()))
+
+	code, err := waitForAuthorizationCode(ctx, listener, state, pkceLoginTimeout)
+	if err != nil {
+		return err
+	}
+
+	tokenResp, err := c.oidcClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
+		ClientId:     aws.String(registration.ClientID),
+		ClientSecret: aws.String(registration.ClientSecret),
+		GrantType:    aws.String(authorizationCodeGrantType),
+		Code:         aws.String(code),
+		CodeVerifier: aws.String(verifier),
+		RedirectUri:  aws.String(redirectURI),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create token: %v", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "Authentication successful!")
+	return c.storeToken(registration, tokenResp, startURL, ssoRegion)
+}
+
+// buildAuthorizeURL returns the IAM Identity Center OIDC authorize URL for the PKCE flow. The
+// OIDC host comes from the SDK's endpoint resolver, so it follows the SSO region's partition.
+func buildAuthorizeURL(ctx context.Context, ssoRegion, clientID, redirectURI, state, challenge string) (string, error) {
+	endpoint, err := ssooidc.NewDefaultEndpointResolverV2().ResolveEndpoint(ctx, ssooidc.EndpointParameters{
+		Region: aws.String(ssoRegion),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the OIDC endpoint for %s: %v", ssoRegion, err)
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", clientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("state", state)
+	params.Set("code_challenge_method", "S256")
+	params.Set("code_challenge", challenge)
+	params.Set("scopes", "sso:account:access")
+	authorizeURL := endpoint.URI
+	authorizeURL.Path = strings.TrimSuffix(authorizeURL.Path, "/") + "/authorize"
+	authorizeURL.RawQuery = params.Encode()
+	return authorizeURL.String(), nil
+}
+
+// newPKCEChallenge returns a code verifier and its S256 code challenge
+func newPKCEChallenge() (string, string, error) {
+	verifier, err := randomURLSafeString(64)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func randomURLSafeString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// waitForAuthorizationCode serves the OAuth callback on listener until the browser returns
+// the authorization code for the expected state
+func waitForAuthorizationCode(ctx context.Context, listener net.Listener, expectedState string, timeout time.Duration) (string, error) {
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var result callbackResult
+		switch {
+		case query.Get("state") != expectedState:
+			result.err = fmt.Errorf("authorization response state mismatch")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			result.err = fmt.Errorf("authorization response missing code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, "awsc login failed. Return to your terminal for details.", http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "awsc login complete. You can close this window.")
+		}
+
+		select {
+		case results <- result:
+		default: // Only the first callback counts
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			select {
+			case results <- callbackResult{err: fmt.Errorf("callback listener failed: %v", err)}:
+			default:
+			}
+		}
+	}()
+	defer server.Close()
+
+	select {
+	case result := <-results:
+		return result.code, result.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("authentication timed out - please try again")
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
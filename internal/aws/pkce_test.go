@@ -0,0 +1,283 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/spf13/viper"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewPKCEChallenge(t *testing.T) {
+	verifier, challenge, err := newPKCEChallenge()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// RFC 7636 requires a 43-128 character verifier
+	if len(verifier) < 43 || len(verifier) > 128 {
+		t.Errorf("Verifier length %d outside RFC 7636 bounds", len(verifier))
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	if expected := base64.RawURLEncoding.EncodeToString(sum[:]); challenge != expected {
+		t.Errorf("Expected challenge %s, got %s", expected, challenge)
+	}
+}
+
+func TestBuildAuthorizeURL(t *testing.T) {
+	authURL, err := buildAuthorizeURL(context.Background(), "eu-west-1", "client-id", "http://127.0.0.1:1234/oauth/callback", "state-1", "challenge-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Invalid URL: %v", err)
+	}
+	if parsed.Host != "oidc.eu-west-1.amazonaws.com" || parsed.Path != "/authorize" {
+		t.Errorf("Unexpected authorize endpoint: %s", authURL)
+	}
+
+	expected := map[string]string{
+		"response_type":         "code",
+		"client_id":             "client-id",
+		"redirect_uri":          "http://127.0.0.1:1234/oauth/callback",
+		"state":                 "state-1",
+		"code_challenge":        "challenge-1",
+		"code_challenge_method": "S256",
+		"scopes":                "sso:account:access",
+	}
+	for key, value := range expected {
+		if got := parsed.Query().Get(key); got != value {
+			t.Errorf("Expected %s=%s, got %s", key, value, got)
+		}
+	}
+}
+
+func TestBuildAuthorizeURL_Partitions(t *testing.T) {
+	tests := []struct {
+		region       string
+		expectedHost string
+	}{
+		{"us-east-1", "oidc.us-east-1.amazonaws.com"},
+		{"us-gov-west-1", "oidc.us-gov-west-1.amazonaws.com"},
+		{"cn-north-1", "oidc.cn-north-1.amazonaws.com.cn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			authURL, err := buildAuthorizeURL(context.Background(), tt.region, "client-id", "http://127.0.0.1:1234/oauth/callback", "state", "challenge")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			parsed, err := url.Parse(authURL)
+			if err != nil {
+				t.Fatalf("Invalid URL: %v", err)
+			}
+			if parsed.Host != tt.expectedHost || parsed.Path != "/authorize" {
+				t.Errorf("Expected https://%s/authorize, got %s", tt.expectedHost, authURL)
+			}
+		})
+	}
+}
+
+func TestWaitForAuthorizationCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expectCode  string
+		expectError bool
+	}{
+		{"success", "state=expected&code=auth-code", "auth-code", false},
+		{"state mismatch", "state=other&code=auth-code", "", true},
+		{"authorization denied", "state=expected&error=access_denied", "", true},
+		{"missing code", "state=expected", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+			defer listener.Close()
+
+			go func() {
+				resp, err := http.Get(fmt.Sprintf("http://%s/oauth/callback?%s", listener.Addr(), tt.query))
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+
+			code, err := waitForAuthorizationCode(context.Background(), listener, "expected", 5*time.Second)
+			if (err != nil) != tt.expectError {
+				t.Errorf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if code != tt.expectCode {
+				t.Errorf("Expected code %q, got %q", tt.expectCode, code)
+			}
+		})
+	}
+}
+
+func TestWaitForAuthorizationCode_Timeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	if _, err := waitForAuthorizationCode(context.Background(), listener, "expected", 10*time.Millisecond); err == nil {
+		t.Error("Expected timeout error")
+	}
+}
+
+func TestCredentialsManager_authenticatePKCE(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sso.start_url", "https://test.awsapps.com/start")
+	viper.Set("sso.login_method", awscconfig.LoginMethodPKCE)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOIDC := mocks.NewMockOIDCClient(ctrl)
+	manager, err := NewCredentialsManager(context.Background(), CredentialsManagerOptions{OIDCClient: mockOIDC})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A cached device flow registration must not be reused for PKCE
+	if err := manager.saveTokenToCache(&SSOCache{
+		ClientID:              "device-client",
+		ClientSecret:          "device-secret",
+		RegistrationExpiresAt: time.Now().Add(24 * time.Hour),
+		StartURL:              "https://test.awsapps.com/start",
+	}); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+
+	mockOIDC.EXPECT().RegisterClient(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+			if input.GrantTypes[0] != authorizationCodeGrantType || len(input.RedirectUris) != 1 || aws.ToString(input.IssuerUrl) == "" {
+				t.Errorf("Unexpected PKCE registration input: %+v", input)
+			}
+			return &ssooidc.RegisterClientOutput{
+				ClientId:              aws.String("pkce-client"),
+				ClientSecret:          aws.String("pkce-secret"),
+				ClientSecretExpiresAt: time.Now().Add(90 * 24 * time.Hour).Unix(),
+			}, nil
+		})
+
+	var redirectURI string
+	mockOIDC.EXPECT().CreateToken(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+			if aws.ToString(input.Code) != "auth-code" || aws.ToString(input.CodeVerifier) == "" || aws.ToString(input.RedirectUri) != redirectURI {
+				t.Errorf("Unexpected CreateToken input: %+v", input)
+			}
+			return &ssooidc.CreateTokenOutput{
+				AccessToken:  aws.String("pkce-token"),
+				ExpiresIn:    3600,
+				RefreshToken: aws.String("pkce-refresh"),
+			}, nil
+		})
+
+	// Act as the browser: follow the authorize URL back to the callback listener
+	originalOpener := browserOpener
+	defer func() { browserOpener = originalOpener }()
+	browserOpener = func(authURL string) error {
+		parsed, _ := url.Parse(authURL)
+		redirectURI = parsed.Query().Get("redirect_uri")
+		go func() {
+			resp, err := http.Get(fmt.Sprintf("%s?state=%s&code=auth-code", redirectURI, parsed.Query().Get("state")))
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	if err := manager.Authenticate(context.Background(), "https://test.awsapps.com/start", "us-east-1"); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+
+	cache, err := manager.loadTokenFromCache("https://test.awsapps.com/start")
+	if err != nil {
+		t.Fatalf("loadTokenFromCache failed: %v", err)
+	}
+	if cache.AccessToken != "pkce-token" || cache.RefreshToken != "pkce-refresh" || cache.ClientID != "pkce-client" {
+		t.Errorf("Unexpected cache: %+v", cache)
+	}
+	if cache.LoginMethod != awscconfig.LoginMethodPKCE {
+		t.Errorf("Expected registration method pkce, got %q", cache.LoginMethod)
+	}
+}
+
+func TestCredentialsManager_Authenticate_UnknownMethod(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sso.login_method", "carrier-pigeon")
+
+	manager := &CredentialsManager{}
+	if err := manager.Authenticate(context.Background(), "https://test.awsapps.com/start", "us-east-1"); err == nil {
+		t.Error("Expected error for unknown login method")
+	}
+}
+
+func TestCredentialsManager_Authenticate_PKCENoBrowser(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sso.login_method", awscconfig.LoginMethodPKCE)
+	viper.Set("sso.no_browser", true)
+
+	// No clients: the combination must be rejected before any SSO call
+	manager := &CredentialsManager{}
+	err := manager.Authenticate(context.Background(), "https://test.awsapps.com/start", "us-east-1")
+	if err == nil || !strings.Contains(err.Error(), "--login-method device") {
+		t.Errorf("Expected an error pointing to the device flow, got %v", err)
+	}
+}
+
+func TestPresentLoginURL_NoBrowser(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("sso.no_browser", true)
+
+	originalOpener := browserOpener
+	defer func() { browserOpener = originalOpener }()
+	browserOpener = func(string) error {
+		t.Error("Browser should not be opened in no-browser mode")
+		return nil
+	}
+
+	originalStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	presentLoginURL("https://device.sso.us-east-1.amazonaws.com/?user_code=ABCD-EFGH", "ABCD-EFGH")
+	w.Close()
+	os.Stderr = originalStderr
+
+	output, _ := io.ReadAll(r)
+	expected := "AWSC_SSO_URL=https://device.sso.us-east-1.amazonaws.com/?user_code=ABCD-EFGH\nAWSC_SSO_CODE=ABCD-EFGH\n"
+	if string(output) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(output))
+	}
+}
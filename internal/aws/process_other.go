@@ -0,0 +1,16 @@
+//go:build !unix
+
+package aws
+
+import (
+	"os"
+	"os/exec"
+)
+
+// detachFromTerminal is a no-op where process groups aren't available
+func detachFromTerminal(cmd *exec.Cmd) {}
+
+// interruptProcess kills process, since interrupts can't be sent to other processes here
+func interruptProcess(process *os.Process) {
+	process.Kill()
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package aws
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detachFromTerminal starts cmd in its own process group, so Ctrl-C in the terminal reaches
+// the foreground client but not cmd
+func detachFromTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptProcess asks process to exit cleanly
+func interruptProcess(process *os.Process) {
+	process.Signal(os.Interrupt)
+}
@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,8 +11,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	ssmservice "github.com/aws/aws-sdk-go-v2/service/ssm"
-	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/blontic/awsc/internal/debug"
 	"github.com/blontic/awsc/internal/ui"
 )
@@ -20,28 +21,47 @@ import (
 type RDSClient interface {
 	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
 	DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+	DescribeDBClusterEndpoints(ctx context.Context, params *rds.DescribeDBClusterEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterEndpointsOutput, error)
+	DescribeDBProxies(ctx context.Context, params *rds.DescribeDBProxiesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxiesOutput, error)
+	DescribeDBProxyEndpoints(ctx context.Context, params *rds.DescribeDBProxyEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxyEndpointsOutput, error)
 }
 
 // EC2Client interface for mocking
 type EC2Client interface {
 	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
 	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)
 }
 
 type RDSManager struct {
-	rdsClient RDSClient
-	ec2Client EC2Client
-	ssmClient *ssmservice.Client
-	region    string
+	rdsClient     RDSClient
+	ec2Client     EC2Client
+	ssmClient     *ssmservice.Client
+	secretsClient SecretsManagerClient    // Reads database credentials for --exec
+	credentials   aws.CredentialsProvider // Signs IAM database auth tokens
+	region        string
+	regions       []string               // Regions queried with --regions, in order
+	regional      map[string]*RDSManager // One manager per queried region
 }
 
 type RDSInstance struct {
-	Identifier   string
-	Endpoint     string
-	Port         int32
-	Engine       string
-	EndpointType string // "instance", "cluster-writer", "cluster-reader"
-	ClusterName  string // For cluster endpoints
+	Identifier       string
+	Endpoint         string
+	Port             int32
+	Engine           string
+	EndpointType     string // "instance", "cluster-writer", "cluster-reader", "cluster-custom", "cluster-instance", "proxy", "proxy-endpoint"
+	ClusterName      string // For cluster endpoints
+	ProxyName        string // For proxies and their endpoints
+	EndpointName     string // For additional proxy endpoints, custom cluster endpoints and cluster members
+	Role             string // "writer" or "reader", for proxies, custom cluster endpoints and cluster members
+	AvailabilityZone string // For cluster members
+	Region           string
+
+	// Connection details for --exec
+	MasterUsername string
+	DatabaseName   string
+	SecretARN      string // RDS-managed master user secret, or the secret a proxy authenticates with
+	TaggedSecret   string // Secret named in the awsc:secret tag, preferred over SecretARN
 }
 
 type BastionHost struct {
@@ -51,38 +71,104 @@ type BastionHost struct {
 }
 
 type RDSManagerOptions struct {
-	RDSClient RDSClient
-	EC2Client EC2Client
-	SSMClient *ssmservice.Client
-	Region    string
+	RDSClient     RDSClient
+	EC2Client     EC2Client
+	SSMClient     *ssmservice.Client
+	SecretsClient SecretsManagerClient
+	Credentials   aws.CredentialsProvider
+	Region        string
+	Regions       []string // Regions to query instead of the profile's region
+}
+
+// RDSConnectOptions are the choices of an rds connect run
+type RDSConnectOptions struct {
+	Name      string // Instance to connect to directly, selected interactively when empty
+	LocalPort int32  // Defaults to the instance's port
+	IAMUser   string // Database user to print an IAM auth token for
+	Exec      string // Client to start through the tunnel: auto, psql, mysql or a command template
 }
 
 func NewRDSManager(ctx context.Context, opts ...RDSManagerOptions) (*RDSManager, error) {
+	var regions []string
+	if len(opts) > 0 {
+		regions = opts[0].Regions
+	}
+
 	if len(opts) > 0 && opts[0].RDSClient != nil {
 		// Use provided clients (for testing)
-		return &RDSManager{
-			rdsClient: opts[0].RDSClient,
-			ec2Client: opts[0].EC2Client,
-			ssmClient: opts[0].SSMClient,
-			region:    opts[0].Region,
-		}, nil
+		manager := &RDSManager{
+			rdsClient:     opts[0].RDSClient,
+			ec2Client:     opts[0].EC2Client,
+			ssmClient:     opts[0].SSMClient,
+			secretsClient: opts[0].SecretsClient,
+			credentials:   opts[0].Credentials,
+			region:        opts[0].Region,
+		}
+		manager.addRegions(regions, func(region string) *RDSManager {
+			regional := *manager
+			regional.region = region
+			return &regional
+		})
+		return manager, nil
 	}
 
 	// Production path
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(regions) == 1 {
+		cfg.Region = regions[0]
+	}
+
+	manager := newRDSManagerFromConfig(cfg)
+	manager.addRegions(regions, func(region string) *RDSManager {
+		return newRDSManagerFromConfig(regionalConfig(cfg, region))
+	})
+	return manager, nil
+}
 
+func newRDSManagerFromConfig(cfg aws.Config) *RDSManager {
 	return &RDSManager{
-		rdsClient: rds.NewFromConfig(cfg),
-		ec2Client: ec2.NewFromConfig(cfg),
-		ssmClient: ssmservice.NewFromConfig(cfg),
-		region:    cfg.Region,
-	}, nil
+		rdsClient:     rds.NewFromConfig(cfg),
+		ec2Client:     ec2.NewFromConfig(cfg),
+		ssmClient:     ssmservice.NewFromConfig(cfg),
+		secretsClient: secretsmanager.NewFromConfig(cfg),
+		credentials:   cfg.Credentials,
+		region:        cfg.Region,
+	}
+}
+
+// addRegions creates a manager per region when more than one region is queried
+func (r *RDSManager) addRegions(regions []string, newManager func(region string) *RDSManager) {
+	if len(regions) == 1 {
+		r.region = regions[0]
+	}
+	if len(regions) < 2 {
+		return
+	}
+
+	// Create the regional managers first, so copies of this manager don't inherit its regions
+	regional := make(map[string]*RDSManager, len(regions))
+	for _, region := range regions {
+		regional[region] = newManager(region)
+	}
+	r.regions = regions
+	r.regional = regional
+}
+
+// forRegion returns the manager for the region a resource was found in
+func (r *RDSManager) forRegion(region string) *RDSManager {
+	if regional, ok := r.regional[region]; ok {
+		return regional
+	}
+	return r
 }
 
-func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localPort int32) error {
+func (r *RDSManager) RunConnect(ctx context.Context, opts RDSConnectOptions) error {
+	instanceName := opts.Name
+	localPort := opts.LocalPort
+
 	// List RDS instances
 	instances, err := r.ListRDSInstances(ctx)
 	if err != nil {
@@ -97,18 +183,21 @@ func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localP
 
 	// If instance name provided, try to connect directly
 	if instanceName != "" {
-		var targetInstance *RDSInstance
+		var matches []RDSInstance
 		for _, instance := range instances {
 			if instance.Identifier == instanceName {
-				targetInstance = &instance
-				break
+				matches = append(matches, instance)
 			}
 		}
 
-		if targetInstance != nil {
-			fmt.Printf("Connecting to RDS instance: %s\n", targetInstance.Identifier)
-			selectedInstance = *targetInstance
-		} else {
+		switch {
+		case len(matches) == 1:
+			fmt.Printf("Connecting to RDS instance: %s\n", matches[0].Identifier)
+			selectedInstance = matches[0]
+		case len(matches) > 1:
+			fmt.Printf("RDS instance '%s' exists in several regions:\n\n", instanceName)
+			instances = matches
+		default:
 			fmt.Printf("RDS instance '%s' not found. Available instances:\n\n", instanceName)
 			// Fall through to show list of available instances
 		}
@@ -119,14 +208,35 @@ func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localP
 		// Create instance options for selection
 		instanceOptions := make([]string, len(instances))
 		for i, instance := range instances {
+			var label string
 			switch instance.EndpointType {
 			case "cluster-writer":
-				instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Writer]", instance.Identifier, instance.Engine, instance.Port)
+				label = fmt.Sprintf("%s (%s:%d) [Writer]", instance.Identifier, instance.Engine, instance.Port)
 			case "cluster-reader":
-				instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Reader]", instance.Identifier, instance.Engine, instance.Port)
+				label = fmt.Sprintf("%s (%s:%d) [Reader]", instance.Identifier, instance.Engine, instance.Port)
+			case "cluster-custom":
+				if instance.Role == "reader" {
+					label = fmt.Sprintf("%s (%s:%d) [Custom Reader]", instance.Identifier, instance.Engine, instance.Port)
+				} else {
+					label = fmt.Sprintf("%s (%s:%d) [Custom]", instance.Identifier, instance.Engine, instance.Port)
+				}
+			case "cluster-instance":
+				// Indented to sit under the cluster's endpoints
+				role := "Reader"
+				if instance.Role == "writer" {
+					role = "Writer"
+				}
+				label = fmt.Sprintf("  %s (%s:%d) [%s, %s]", instance.Identifier, instance.Engine, instance.Port, role, instance.AvailabilityZone)
+			case "proxy", "proxy-endpoint":
+				if instance.Role == "reader" {
+					label = fmt.Sprintf("%s (%s:%d) [Proxy Read-Only]", instance.Identifier, instance.Engine, instance.Port)
+				} else {
+					label = fmt.Sprintf("%s (%s:%d) [Proxy]", instance.Identifier, instance.Engine, instance.Port)
+				}
 			default:
-				instanceOptions[i] = fmt.Sprintf("%s (%s:%d)", instance.Identifier, instance.Engine, instance.Port)
+				label = fmt.Sprintf("%s (%s:%d)", instance.Identifier, instance.Engine, instance.Port)
 			}
+			instanceOptions[i] = regionLabel(len(r.regions) > 0, instance.Region, label)
 		}
 
 		// Interactive instance selection
@@ -144,8 +254,21 @@ func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localP
 		fmt.Printf("✓ Selected: %s\n", selectedInstance.Identifier)
 	}
 
+	// Fail before opening the tunnel when no token can be made for this engine
+	if opts.IAMUser != "" && dbPasswordEnv(selectedInstance.Engine) == "" {
+		return fmt.Errorf("IAM database authentication isn't supported for %s engines", selectedInstance.Engine)
+	}
+	if opts.Exec != "" {
+		if _, err := dbClientName(opts.Exec, selectedInstance.Engine); err != nil {
+			return err
+		}
+	}
+
+	// Bastions and the SSM session must be in the instance's region
+	regional := r.forRegion(selectedInstance.Region)
+
 	// Find bastion hosts
-	bastions, err := r.FindBastionHosts(ctx, selectedInstance)
+	bastions, err := regional.FindBastionHosts(ctx, selectedInstance)
 	if err != nil {
 		return err
 	}
@@ -163,31 +286,66 @@ func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localP
 		localPort = selectedInstance.Port
 	}
 
+	// The token is signed with the current credentials, so renew them first rather than
+	// at the tunnel's own expiry check, which comes after the token is made
+	if opts.IAMUser != "" {
+		if err := ensureCredentialsValid(ctx); err != nil {
+			return err
+		}
+	}
+
+	if opts.Exec != "" {
+		return regional.execDBClient(ctx, bastion.InstanceId, selectedInstance, localPort, opts)
+	}
+
+	if opts.IAMUser != "" {
+		token, err := regional.GenerateIAMAuthToken(ctx, selectedInstance, opts.IAMUser)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("IAM auth token for %s (valid for %d minutes, SSL required):\n", opts.IAMUser, int(rdsAuthTokenExpiry.Minutes()))
+		fmt.Printf("export %s=%s\n", dbPasswordEnv(selectedInstance.Engine), quotePOSIX(token))
+	}
+
 	// Start port forwarding
-	return r.StartPortForwarding(ctx, bastion.InstanceId, selectedInstance.Endpoint, selectedInstance.Port, localPort)
+	return regional.StartPortForwarding(ctx, bastion.InstanceId, selectedInstance.Endpoint, selectedInstance.Port, localPort)
 }
 
 func (r *RDSManager) ListRDSInstances(ctx context.Context) ([]RDSInstance, error) {
+	if len(r.regions) > 0 {
+		return queryRegions(ctx, r.regions, func(ctx context.Context, region string) ([]RDSInstance, error) {
+			return r.regional[region].ListRDSInstances(ctx)
+		})
+	}
+
 	var instances []RDSInstance
 
-	// Get standalone DB instances
-	dbInstances, err := r.getDBInstances(ctx)
+	// Instances are described once: standalone ones are listed directly, and cluster
+	// members are listed under their cluster
+	allDBInstances, err := r.describeDBInstances(ctx)
 	if err != nil {
 		return nil, err
 	}
-	instances = append(instances, dbInstances...)
+	instances = append(instances, r.getDBInstances(allDBInstances)...)
 
 	// Get Aurora cluster endpoints
-	clusterEndpoints, err := r.getClusterEndpoints(ctx)
+	clusterEndpoints, err := r.getClusterEndpoints(ctx, allDBInstances)
 	if err != nil {
 		return nil, err
 	}
 	instances = append(instances, clusterEndpoints...)
 
+	// Get RDS Proxy endpoints
+	proxyEndpoints, err := r.getProxyEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instances = append(instances, proxyEndpoints...)
+
 	return instances, nil
 }
 
-func (r *RDSManager) getDBInstances(ctx context.Context) ([]RDSInstance, error) {
+func (r *RDSManager) describeDBInstances(ctx context.Context) ([]rdstypes.DBInstance, error) {
 	var allDBInstances []rdstypes.DBInstance
 	var marker *string
 
@@ -196,23 +354,7 @@ func (r *RDSManager) getDBInstances(ctx context.Context) ([]RDSInstance, error)
 			Marker: marker,
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					result, err = r.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
-						Marker: marker,
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		allDBInstances = append(allDBInstances, result.DBInstances...)
@@ -223,24 +365,35 @@ func (r *RDSManager) getDBInstances(ctx context.Context) ([]RDSInstance, error)
 		marker = result.Marker
 	}
 
+	return allDBInstances, nil
+}
+
+func (r *RDSManager) getDBInstances(allDBInstances []rdstypes.DBInstance) []RDSInstance {
 	var instances []RDSInstance
 	for _, db := range allDBInstances {
 		if db.DBInstanceStatus != nil && *db.DBInstanceStatus == "available" && db.DBClusterIdentifier == nil {
 			// Only include standalone instances (not part of a cluster)
 			instances = append(instances, RDSInstance{
-				Identifier:   *db.DBInstanceIdentifier,
-				Endpoint:     *db.Endpoint.Address,
-				Port:         *db.Endpoint.Port,
-				Engine:       *db.Engine,
-				EndpointType: "instance",
+				Identifier:     *db.DBInstanceIdentifier,
+				Endpoint:       *db.Endpoint.Address,
+				Port:           *db.Endpoint.Port,
+				Engine:         *db.Engine,
+				EndpointType:   "instance",
+				Region:         r.region,
+				MasterUsername: aws.ToString(db.MasterUsername),
+				DatabaseName:   aws.ToString(db.DBName),
+				SecretARN:      masterUserSecretARN(db.MasterUserSecret),
+				TaggedSecret:   taggedSecret(db.TagList),
 			})
 		}
 	}
 
-	return instances, nil
+	return instances
 }
 
-func (r *RDSManager) getClusterEndpoints(ctx context.Context) ([]RDSInstance, error) {
+// getClusterEndpoints lists each available cluster's writer, reader and custom endpoints,
+// followed by its member instances (writer first) from allDBInstances
+func (r *RDSManager) getClusterEndpoints(ctx context.Context, allDBInstances []rdstypes.DBInstance) ([]RDSInstance, error) {
 	var allClusters []rdstypes.DBCluster
 	var marker *string
 
@@ -249,23 +402,7 @@ func (r *RDSManager) getClusterEndpoints(ctx context.Context) ([]RDSInstance, er
 			Marker: marker,
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					result, err = r.rdsClient.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
-						Marker: marker,
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		allClusters = append(allClusters, result.DBClusters...)
@@ -276,38 +413,125 @@ func (r *RDSManager) getClusterEndpoints(ctx context.Context) ([]RDSInstance, er
 		marker = result.Marker
 	}
 
-	var instances []RDSInstance
+	var available []rdstypes.DBCluster
 	for _, cluster := range allClusters {
 		if cluster.Status != nil && *cluster.Status == "available" {
-			// Add cluster writer endpoint
-			if cluster.Endpoint != nil {
-				instances = append(instances, RDSInstance{
-					Identifier:   *cluster.DBClusterIdentifier + " (writer)",
-					Endpoint:     *cluster.Endpoint,
-					Port:         *cluster.Port,
-					Engine:       *cluster.Engine,
-					EndpointType: "cluster-writer",
-					ClusterName:  *cluster.DBClusterIdentifier,
-				})
+			available = append(available, cluster)
+		}
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+
+	customEndpoints, err := r.getCustomClusterEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbInstancesById := make(map[string]rdstypes.DBInstance)
+	for _, db := range allDBInstances {
+		dbInstancesById[aws.ToString(db.DBInstanceIdentifier)] = db
+	}
+
+	var instances []RDSInstance
+	for _, cluster := range available {
+		// Add cluster writer endpoint
+		if cluster.Endpoint != nil {
+			instances = append(instances, r.clusterEntry(cluster, "writer", *cluster.Endpoint, *cluster.Port, "cluster-writer"))
+		}
+
+		// Add cluster reader endpoint
+		if cluster.ReaderEndpoint != nil {
+			instances = append(instances, r.clusterEntry(cluster, "reader", *cluster.ReaderEndpoint, *cluster.Port, "cluster-reader"))
+		}
+
+		// Add custom endpoints
+		for _, endpoint := range customEndpoints[*cluster.DBClusterIdentifier] {
+			name := aws.ToString(endpoint.DBClusterEndpointIdentifier)
+			entry := r.clusterEntry(cluster, "custom:"+name, *endpoint.Endpoint, *cluster.Port, "cluster-custom")
+			entry.EndpointName = name
+			if aws.ToString(endpoint.CustomEndpointType) == "READER" {
+				entry.Role = "reader"
 			}
+			instances = append(instances, entry)
+		}
 
-			// Add cluster reader endpoint
-			if cluster.ReaderEndpoint != nil {
-				instances = append(instances, RDSInstance{
-					Identifier:   *cluster.DBClusterIdentifier + " (reader)",
-					Endpoint:     *cluster.ReaderEndpoint,
-					Port:         *cluster.Port,
-					Engine:       *cluster.Engine,
-					EndpointType: "cluster-reader",
-					ClusterName:  *cluster.DBClusterIdentifier,
-				})
+		// Add member instances, writer first
+		members := append([]rdstypes.DBClusterMember(nil), cluster.DBClusterMembers...)
+		sort.SliceStable(members, func(i, j int) bool {
+			return aws.ToBool(members[i].IsClusterWriter) && !aws.ToBool(members[j].IsClusterWriter)
+		})
+		for _, member := range members {
+			db, ok := dbInstancesById[aws.ToString(member.DBInstanceIdentifier)]
+			if !ok || aws.ToString(db.DBInstanceStatus) != "available" || db.Endpoint == nil {
+				continue
+			}
+			entry := r.clusterEntry(cluster, "instance:"+*db.DBInstanceIdentifier, *db.Endpoint.Address, *db.Endpoint.Port, "cluster-instance")
+			entry.EndpointName = *db.DBInstanceIdentifier
+			entry.AvailabilityZone = aws.ToString(db.AvailabilityZone)
+			entry.Role = "reader"
+			if aws.ToBool(member.IsClusterWriter) {
+				entry.Role = "writer"
 			}
+			instances = append(instances, entry)
 		}
 	}
 
 	return instances, nil
 }
 
+// clusterEntry builds a selector entry for one of a cluster's endpoints, named
+// "cluster (label)" so it can be passed to --name
+func (r *RDSManager) clusterEntry(cluster rdstypes.DBCluster, label, endpoint string, port int32, endpointType string) RDSInstance {
+	return RDSInstance{
+		Identifier:     *cluster.DBClusterIdentifier + " (" + label + ")",
+		Endpoint:       endpoint,
+		Port:           port,
+		Engine:         *cluster.Engine,
+		EndpointType:   endpointType,
+		ClusterName:    *cluster.DBClusterIdentifier,
+		Region:         r.region,
+		MasterUsername: aws.ToString(cluster.MasterUsername),
+		DatabaseName:   aws.ToString(cluster.DatabaseName),
+		SecretARN:      masterUserSecretARN(cluster.MasterUserSecret),
+		TaggedSecret:   taggedSecret(cluster.TagList),
+	}
+}
+
+// getCustomClusterEndpoints returns the available custom endpoints by cluster. Like proxies,
+// they're optional, so errors other than expired credentials only skip them.
+func (r *RDSManager) getCustomClusterEndpoints(ctx context.Context) (map[string][]rdstypes.DBClusterEndpoint, error) {
+	endpoints := make(map[string][]rdstypes.DBClusterEndpoint)
+	var marker *string
+
+	for {
+		result, err := r.rdsClient.DescribeDBClusterEndpoints(ctx, &rds.DescribeDBClusterEndpointsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				return nil, err
+			}
+			debug.Printf("Skipping custom cluster endpoints: %v\n", err)
+			return nil, nil
+		}
+
+		for _, endpoint := range result.DBClusterEndpoints {
+			if aws.ToString(endpoint.EndpointType) == "CUSTOM" && aws.ToString(endpoint.Status) == "available" && endpoint.Endpoint != nil {
+				cluster := aws.ToString(endpoint.DBClusterIdentifier)
+				endpoints[cluster] = append(endpoints[cluster], endpoint)
+			}
+		}
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return endpoints, nil
+}
+
 func (r *RDSManager) FindBastionHosts(ctx context.Context, rdsInstance RDSInstance) ([]BastionHost, error) {
 	// Get RDS security groups
 	rdsSecurityGroups, err := r.getRDSSecurityGroups(ctx, rdsInstance)
@@ -326,25 +550,7 @@ func (r *RDSManager) FindBastionHosts(ctx context.Context, rdsInstance RDSInstan
 			NextToken: nextToken,
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					// Reload all clients with fresh credentials
-					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					// Retry after re-authentication
-					result, err = r.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
-						NextToken: nextToken,
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		allReservations = append(allReservations, result.Reservations...)
@@ -435,12 +641,12 @@ func (r *RDSManager) FindBastionHosts(ctx context.Context, rdsInstance RDSInstan
 
 func (r *RDSManager) StartPortForwarding(ctx context.Context, bastionId, rdsEndpoint string, rdsPort, localPort int32) error {
 	// Create port forwarder
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	pf := NewExternalPluginForwarder(cfg)
+	pf := NewExternalPluginForwarder(regionalConfig(cfg, r.region))
 
 	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
 
@@ -449,29 +655,16 @@ func (r *RDSManager) StartPortForwarding(ctx context.Context, bastionId, rdsEndp
 }
 
 func (r *RDSManager) getRDSSecurityGroups(ctx context.Context, rdsInstance RDSInstance) ([]string, error) {
-	if rdsInstance.EndpointType == "cluster-writer" || rdsInstance.EndpointType == "cluster-reader" {
+	if rdsInstance.EndpointType == "proxy" || rdsInstance.EndpointType == "proxy-endpoint" {
+		return r.getProxySecurityGroups(ctx, rdsInstance)
+	}
+	if rdsInstance.EndpointType == "cluster-writer" || rdsInstance.EndpointType == "cluster-reader" || rdsInstance.EndpointType == "cluster-custom" {
 		// Get security groups from cluster
 		result, err := r.rdsClient.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
 			DBClusterIdentifier: aws.String(rdsInstance.ClusterName),
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					result, err = r.rdsClient.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
-						DBClusterIdentifier: aws.String(rdsInstance.ClusterName),
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		if len(result.DBClusters) == 0 {
@@ -484,28 +677,16 @@ func (r *RDSManager) getRDSSecurityGroups(ctx context.Context, rdsInstance RDSIn
 		}
 		return sgIds, nil
 	} else {
-		// Get security groups from instance
+		// Get security groups from instance, which for cluster members is the member itself
+		instanceId := rdsInstance.Identifier
+		if rdsInstance.EndpointType == "cluster-instance" {
+			instanceId = rdsInstance.EndpointName
+		}
 		result, err := r.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
-			DBInstanceIdentifier: aws.String(rdsInstance.Identifier),
+			DBInstanceIdentifier: aws.String(instanceId),
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					result, err = r.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
-						DBInstanceIdentifier: aws.String(rdsInstance.Identifier),
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		if len(result.DBInstances) == 0 {
@@ -541,28 +722,8 @@ func (r *RDSManager) checkSecurityGroupRules(ctx context.Context, rdsSgId string
 		GroupIds: []string{rdsSgId},
 	})
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				// Reload all clients with fresh credentials
-				if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-					debug.Printf("  Error reloading clients: %v\n", reloadErr)
-					return false
-				}
-				// Retry after re-authentication
-				result, err = r.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
-					GroupIds: []string{rdsSgId},
-				})
-				if err != nil {
-					debug.Printf("  Error describing security group %s after retry: %v\n", rdsSgId, err)
-					return false
-				}
-			} else {
-				return false
-			}
-		} else {
-			debug.Printf("  Error describing security group %s: %v\n", rdsSgId, err)
-			return false
-		}
+		debug.Printf("  Error describing security group %s: %v\n", rdsSgId, err)
+		return false
 	}
 
 	if len(result.SecurityGroups) == 0 {
@@ -630,17 +791,3 @@ func (r *RDSManager) getSecurityGroupIds(sgs []types.GroupIdentifier) []string {
 	}
 	return ids
 }
-
-func (r *RDSManager) reloadClients(ctx context.Context) error {
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
-	if err != nil {
-		return err
-	}
-
-	r.rdsClient = rds.NewFromConfig(cfg)
-	r.ec2Client = ec2.NewFromConfig(cfg)
-	r.ssmClient = ssmservice.NewFromConfig(cfg)
-	r.region = cfg.Region
-
-	return nil
-}
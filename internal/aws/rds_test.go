@@ -154,6 +154,12 @@ func TestRDSManager_ListRDSInstances(t *testing.T) {
 				Return(&rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{}}, nil).
 				Times(1)
 
+			// Mock DescribeDBProxies call for proxy endpoints
+			mockRDS.EXPECT().
+				DescribeDBProxies(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBProxiesOutput{}, nil).
+				Times(1)
+
 			instances, err := manager.ListRDSInstances(context.Background())
 
 			if tt.expectedError && err == nil {
@@ -712,7 +718,12 @@ func TestRDSManager_getClusterEndpoints(t *testing.T) {
 				Return(tt.mockResponse, nil).
 				Times(1)
 
-			instances, err := manager.getClusterEndpoints(context.Background())
+			mockRDS.EXPECT().
+				DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+				AnyTimes()
+
+			instances, err := manager.getClusterEndpoints(context.Background(), nil)
 
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
@@ -883,6 +894,16 @@ func TestRDSManager_ListRDSInstances_WithClusters(t *testing.T) {
 		}, nil).
 		Times(1)
 
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{}, nil).
+		Times(1)
+
 	instances, err := manager.ListRDSInstances(context.Background())
 
 	if err != nil {
@@ -996,3 +1017,222 @@ func TestRDSManager_FindBastionHosts_WithStoppedInstances(t *testing.T) {
 		t.Errorf("Expected error about stopped instances, got: %v", err)
 	}
 }
+
+func TestRDSManager_ListRDSInstances_ConnectionDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBInstancesOutput{
+		DBInstances: []rdstypes.DBInstance{
+			{
+				DBInstanceIdentifier: aws.String("app-db"),
+				DBInstanceStatus:     aws.String("available"),
+				Engine:               aws.String("postgres"),
+				Endpoint:             &rdstypes.Endpoint{Address: aws.String("app-db.rds.amazonaws.com"), Port: aws.Int32(5432)},
+				MasterUsername:       aws.String("postgres"),
+				DBName:               aws.String("app"),
+				MasterUserSecret:     &rdstypes.MasterUserSecret{SecretArn: aws.String("arn:master")},
+			},
+		},
+	}, nil)
+	mockRDS.EXPECT().DescribeDBClusters(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBClustersOutput{
+		DBClusters: []rdstypes.DBCluster{
+			{
+				DBClusterIdentifier: aws.String("orders"),
+				Status:              aws.String("available"),
+				Engine:              aws.String("aurora-mysql"),
+				Endpoint:            aws.String("orders.cluster-xyz.rds.amazonaws.com"),
+				Port:                aws.Int32(3306),
+				MasterUsername:      aws.String("admin"),
+				DatabaseName:        aws.String("orders"),
+				TagList:             []rdstypes.Tag{{Key: aws.String("awsc:secret"), Value: aws.String("orders/app")}},
+			},
+		},
+	}, nil)
+	mockRDS.EXPECT().DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBClusterEndpointsOutput{}, nil)
+	mockRDS.EXPECT().DescribeDBProxies(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBProxiesOutput{}, nil)
+
+	instances, err := manager.ListRDSInstances(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(instances))
+	}
+
+	byType := map[string]RDSInstance{}
+	for _, instance := range instances {
+		byType[instance.EndpointType] = instance
+	}
+	db := byType["instance"]
+	if db.MasterUsername != "postgres" || db.DatabaseName != "app" || db.SecretARN != "arn:master" || db.TaggedSecret != "" {
+		t.Errorf("Unexpected instance connection details: %+v", db)
+	}
+	writer := byType["cluster-writer"]
+	if writer.MasterUsername != "admin" || writer.DatabaseName != "orders" || writer.SecretARN != "" || writer.TaggedSecret != "orders/app" {
+		t.Errorf("Unexpected cluster connection details: %+v", writer)
+	}
+}
+
+func TestRDSManager_getClusterEndpoints_CustomAndMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().DescribeDBClusters(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBClustersOutput{
+		DBClusters: []rdstypes.DBCluster{
+			{
+				DBClusterIdentifier: aws.String("orders"),
+				Status:              aws.String("available"),
+				Engine:              aws.String("aurora-postgresql"),
+				Port:                aws.Int32(5432),
+				Endpoint:            aws.String("orders.cluster-xyz.rds.amazonaws.com"),
+				DBClusterMembers: []rdstypes.DBClusterMember{
+					{DBInstanceIdentifier: aws.String("orders-2"), IsClusterWriter: aws.Bool(false)},
+					{DBInstanceIdentifier: aws.String("orders-1"), IsClusterWriter: aws.Bool(true)},
+					{DBInstanceIdentifier: aws.String("orders-3"), IsClusterWriter: aws.Bool(false)},
+				},
+			},
+		},
+	}, nil)
+	mockRDS.EXPECT().DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBClusterEndpointsOutput{
+		DBClusterEndpoints: []rdstypes.DBClusterEndpoint{
+			{
+				DBClusterIdentifier: aws.String("orders"),
+				Endpoint:            aws.String("orders.cluster-ro-xyz.rds.amazonaws.com"),
+				EndpointType:        aws.String("READER"),
+				Status:              aws.String("available"),
+			},
+			{
+				DBClusterIdentifier:         aws.String("orders"),
+				DBClusterEndpointIdentifier: aws.String("analytics"),
+				Endpoint:                    aws.String("analytics.cluster-custom-xyz.rds.amazonaws.com"),
+				EndpointType:                aws.String("CUSTOM"),
+				CustomEndpointType:          aws.String("READER"),
+				Status:                      aws.String("available"),
+			},
+			{
+				DBClusterIdentifier:         aws.String("orders"),
+				DBClusterEndpointIdentifier: aws.String("batch"),
+				Endpoint:                    aws.String("batch.cluster-custom-xyz.rds.amazonaws.com"),
+				EndpointType:                aws.String("CUSTOM"),
+				CustomEndpointType:          aws.String("ANY"),
+				Status:                      aws.String("available"),
+			},
+			{
+				DBClusterIdentifier:         aws.String("orders"),
+				DBClusterEndpointIdentifier: aws.String("new"),
+				Endpoint:                    aws.String("new.cluster-custom-xyz.rds.amazonaws.com"),
+				EndpointType:                aws.String("CUSTOM"),
+				Status:                      aws.String("creating"),
+			},
+		},
+	}, nil)
+
+	member := func(id, az, status string) rdstypes.DBInstance {
+		return rdstypes.DBInstance{
+			DBInstanceIdentifier: aws.String(id),
+			DBClusterIdentifier:  aws.String("orders"),
+			DBInstanceStatus:     aws.String(status),
+			AvailabilityZone:     aws.String(az),
+			Endpoint:             &rdstypes.Endpoint{Address: aws.String(id + ".xyz.rds.amazonaws.com"), Port: aws.Int32(5432)},
+		}
+	}
+	dbInstances := []rdstypes.DBInstance{
+		member("orders-1", "us-east-1a", "available"),
+		member("orders-2", "us-east-1b", "available"),
+		member("orders-3", "us-east-1c", "rebooting"),
+	}
+
+	instances, err := manager.getClusterEndpoints(context.Background(), dbInstances)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		identifier   string
+		endpointType string
+		endpoint     string
+		endpointName string
+		role         string
+		az           string
+	}{
+		{"orders (writer)", "cluster-writer", "orders.cluster-xyz.rds.amazonaws.com", "", "", ""},
+		{"orders (custom:analytics)", "cluster-custom", "analytics.cluster-custom-xyz.rds.amazonaws.com", "analytics", "reader", ""},
+		{"orders (custom:batch)", "cluster-custom", "batch.cluster-custom-xyz.rds.amazonaws.com", "batch", "", ""},
+		{"orders (instance:orders-1)", "cluster-instance", "orders-1.xyz.rds.amazonaws.com", "orders-1", "writer", "us-east-1a"},
+		{"orders (instance:orders-2)", "cluster-instance", "orders-2.xyz.rds.amazonaws.com", "orders-2", "reader", "us-east-1b"},
+	}
+	if len(instances) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(expected), len(instances), instances)
+	}
+	for i, want := range expected {
+		got := instances[i]
+		if got.Identifier != want.identifier || got.EndpointType != want.endpointType || got.Endpoint != want.endpoint ||
+			got.EndpointName != want.endpointName || got.Role != want.role || got.AvailabilityZone != want.az {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.ClusterName != "orders" {
+			t.Errorf("Entry %d: expected cluster orders, got %q", i, got.ClusterName)
+		}
+	}
+}
+
+func TestRDSManager_getRDSSecurityGroups_CustomAndMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String("orders")}).
+		Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{{VpcSecurityGroups: []rdstypes.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String("sg-cluster")}}}},
+		}, nil)
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String("orders-2")}).
+		Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{{VpcSecurityGroups: []rdstypes.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String("sg-member")}}}},
+		}, nil)
+
+	custom, err := manager.getRDSSecurityGroups(context.Background(), RDSInstance{
+		Identifier: "orders (custom:analytics)", EndpointType: "cluster-custom", ClusterName: "orders", EndpointName: "analytics",
+	})
+	if err != nil || len(custom) != 1 || custom[0] != "sg-cluster" {
+		t.Errorf("Expected the cluster's security group for a custom endpoint, got %v (err: %v)", custom, err)
+	}
+
+	memberGroups, err := manager.getRDSSecurityGroups(context.Background(), RDSInstance{
+		Identifier: "orders (instance:orders-2)", EndpointType: "cluster-instance", ClusterName: "orders", EndpointName: "orders-2",
+	})
+	if err != nil || len(memberGroups) != 1 || memberGroups[0] != "sg-member" {
+		t.Errorf("Expected the member's security group, got %v (err: %v)", memberGroups, err)
+	}
+}
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	rdsauth "github.com/aws/aws-sdk-go-v2/feature/rds/auth"
+)
+
+// rdsAuthTokenExpiry is how long an IAM database auth token can be used to open a
+// connection; BuildAuthToken signs tokens for the 15 minutes RDS allows
+const rdsAuthTokenExpiry = 15 * time.Minute
+
+// dbPasswordEnv returns the environment variable the engine's standard client reads its
+// password from, or "" for engines without IAM database authentication
+func dbPasswordEnv(engine string) string {
+	switch dbEngineFamily(engine) {
+	case "postgres":
+		return "PGPASSWORD"
+	case "mysql":
+		return "MYSQL_PWD"
+	default:
+		return ""
+	}
+}
+
+// GenerateIAMAuthToken signs an IAM database authentication token for user with the
+// session's credentials. The token is bound to the instance's real endpoint and port, which
+// is what RDS checks, not to the local end of the tunnel.
+func (r *RDSManager) GenerateIAMAuthToken(ctx context.Context, instance RDSInstance, user string) (string, error) {
+	if dbPasswordEnv(instance.Engine) == "" {
+		return "", fmt.Errorf("IAM database authentication isn't supported for %s engines", instance.Engine)
+	}
+	if r.credentials == nil {
+		return "", fmt.Errorf("no credentials available to sign the IAM auth token")
+	}
+	if instance.Endpoint == "" || instance.Port == 0 {
+		return "", fmt.Errorf("the endpoint and port are required for an IAM auth token")
+	}
+	if user == "" {
+		return "", fmt.Errorf("a database user is required for an IAM auth token")
+	}
+
+	region := instance.Region
+	if region == "" {
+		region = r.region
+	}
+	endpoint := net.JoinHostPort(instance.Endpoint, strconv.Itoa(int(instance.Port)))
+	token, err := rdsauth.BuildAuthToken(ctx, endpoint, region, user, r.credentials)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign IAM auth token: %v", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,109 @@
+package aws
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRDSManager_GenerateIAMAuthToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient:   mocks.NewMockRDSClient(ctrl),
+		Credentials: credentials.NewStaticCredentialsProvider("AKIATEST", "secret", ""),
+		Region:      "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		instance       RDSInstance
+		user           string
+		expectError    bool
+		expectedRegion string
+	}{
+		{
+			name:           "postgres in the manager's region",
+			instance:       RDSInstance{Endpoint: "db.example.com", Port: 5432, Engine: "postgres"},
+			user:           "app_user",
+			expectedRegion: "us-east-1",
+		},
+		{
+			name:           "aurora mysql found in another region",
+			instance:       RDSInstance{Endpoint: "db.example.com", Port: 3306, Engine: "aurora-mysql", Region: "eu-west-1"},
+			user:           "app_user",
+			expectedRegion: "eu-west-1",
+		},
+		{
+			name:        "engine without IAM auth",
+			instance:    RDSInstance{Endpoint: "db.example.com", Port: 1433, Engine: "sqlserver-se"},
+			user:        "app_user",
+			expectError: true,
+		},
+		{
+			name:        "missing endpoint",
+			instance:    RDSInstance{Port: 5432, Engine: "postgres"},
+			user:        "app_user",
+			expectError: true,
+		},
+		{
+			name:        "missing database user",
+			instance:    RDSInstance{Endpoint: "db.example.com", Port: 5432, Engine: "postgres"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := manager.GenerateIAMAuthToken(context.Background(), tt.instance, tt.user)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if tt.expectError {
+				return
+			}
+
+			// The token is the presigned connect URL for the real endpoint, without a scheme
+			host, rawQuery, found := strings.Cut(token, "?")
+			if !found || host != tt.instance.Endpoint+":"+strconv.Itoa(int(tt.instance.Port)) {
+				t.Fatalf("Expected token for %s:%d, got %s", tt.instance.Endpoint, tt.instance.Port, token)
+			}
+			query, err := url.ParseQuery(rawQuery)
+			if err != nil {
+				t.Fatalf("Invalid token query: %v", err)
+			}
+			if query.Get("Action") != "connect" || query.Get("DBUser") != "app_user" || query.Get("X-Amz-Expires") != "900" {
+				t.Errorf("Unexpected token parameters: %v", query)
+			}
+			if !strings.Contains(query.Get("X-Amz-Credential"), "/"+tt.expectedRegion+"/rds-db/") {
+				t.Errorf("Expected token signed for %s, got %s", tt.expectedRegion, token)
+			}
+		})
+	}
+}
+
+func TestDBPasswordEnv(t *testing.T) {
+	tests := map[string]string{
+		"postgres":          "PGPASSWORD",
+		"aurora-postgresql": "PGPASSWORD",
+		"mysql":             "MYSQL_PWD",
+		"mariadb":           "MYSQL_PWD",
+		"aurora-mysql":      "MYSQL_PWD",
+		"oracle-ee":         "",
+	}
+	for engine, expected := range tests {
+		if got := dbPasswordEnv(engine); got != expected {
+			t.Errorf("dbPasswordEnv(%q) = %q, want %q", engine, got, expected)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// proxyEngines maps a proxy's engine family to the engine name used for clients and IAM
+// auth, and the port the proxy listens on, which is always the family's default
+var proxyEngines = map[string]struct {
+	engine string
+	port   int32
+}{
+	"MYSQL":      {"mysql", 3306},
+	"POSTGRESQL": {"postgres", 5432},
+	"SQLSERVER":  {"sqlserver", 1433},
+}
+
+// getProxyEndpoints lists available RDS Proxies and their additional endpoints. Proxies are
+// optional, so errors other than expired credentials only skip them.
+func (r *RDSManager) getProxyEndpoints(ctx context.Context) ([]RDSInstance, error) {
+	proxies, err := r.describeDBProxies(ctx, nil)
+	if err != nil {
+		if IsAuthError(err) {
+			return nil, err
+		}
+		debug.Printf("Skipping RDS Proxies: %v\n", err)
+		return nil, nil
+	}
+
+	var instances []RDSInstance
+	byName := make(map[string]rdstypes.DBProxy)
+	for _, proxy := range proxies {
+		family, ok := proxyEngines[aws.ToString(proxy.EngineFamily)]
+		if proxy.Status != rdstypes.DBProxyStatusAvailable || proxy.Endpoint == nil || !ok {
+			continue
+		}
+		byName[aws.ToString(proxy.DBProxyName)] = proxy
+		instances = append(instances, RDSInstance{
+			Identifier:   aws.ToString(proxy.DBProxyName) + " (proxy)",
+			Endpoint:     *proxy.Endpoint,
+			Port:         family.port,
+			Engine:       family.engine,
+			EndpointType: "proxy",
+			ProxyName:    aws.ToString(proxy.DBProxyName),
+			Role:         "writer",
+			Region:       r.region,
+			SecretARN:    proxySecretARN(proxy),
+		})
+	}
+	if len(byName) == 0 {
+		return instances, nil
+	}
+
+	endpoints, err := r.describeDBProxyEndpoints(ctx)
+	if err != nil {
+		if IsAuthError(err) {
+			return nil, err
+		}
+		debug.Printf("Skipping RDS Proxy endpoints: %v\n", err)
+		return instances, nil
+	}
+
+	for _, endpoint := range endpoints {
+		proxy, ok := byName[aws.ToString(endpoint.DBProxyName)]
+		// The default endpoint is the proxy's own, listed above
+		if !ok || aws.ToBool(endpoint.IsDefault) || endpoint.Status != rdstypes.DBProxyEndpointStatusAvailable || endpoint.Endpoint == nil {
+			continue
+		}
+		family := proxyEngines[aws.ToString(proxy.EngineFamily)]
+		role := "writer"
+		if endpoint.TargetRole == rdstypes.DBProxyEndpointTargetRoleReadOnly {
+			role = "reader"
+		}
+		instances = append(instances, RDSInstance{
+			Identifier:   fmt.Sprintf("%s (proxy:%s)", aws.ToString(endpoint.DBProxyName), aws.ToString(endpoint.DBProxyEndpointName)),
+			Endpoint:     *endpoint.Endpoint,
+			Port:         family.port,
+			Engine:       family.engine,
+			EndpointType: "proxy-endpoint",
+			ProxyName:    aws.ToString(endpoint.DBProxyName),
+			EndpointName: aws.ToString(endpoint.DBProxyEndpointName),
+			Role:         role,
+			Region:       r.region,
+			SecretARN:    proxySecretARN(proxy),
+		})
+	}
+
+	return instances, nil
+}
+
+// getProxySecurityGroups returns the VPC security groups of a proxy, or of one of its
+// additional endpoints, which can have their own
+func (r *RDSManager) getProxySecurityGroups(ctx context.Context, rdsInstance RDSInstance) ([]string, error) {
+	if rdsInstance.EndpointType == "proxy-endpoint" {
+		result, err := r.rdsClient.DescribeDBProxyEndpoints(ctx, &rds.DescribeDBProxyEndpointsInput{
+			DBProxyName:         aws.String(rdsInstance.ProxyName),
+			DBProxyEndpointName: aws.String(rdsInstance.EndpointName),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.DBProxyEndpoints) == 0 {
+			return nil, fmt.Errorf("RDS Proxy endpoint not found")
+		}
+		return result.DBProxyEndpoints[0].VpcSecurityGroupIds, nil
+	}
+
+	proxies, err := r.describeDBProxies(ctx, aws.String(rdsInstance.ProxyName))
+	if err != nil {
+		return nil, err
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("RDS Proxy not found")
+	}
+	return proxies[0].VpcSecurityGroupIds, nil
+}
+
+func (r *RDSManager) describeDBProxies(ctx context.Context, name *string) ([]rdstypes.DBProxy, error) {
+	var proxies []rdstypes.DBProxy
+	var marker *string
+
+	for {
+		result, err := r.rdsClient.DescribeDBProxies(ctx, &rds.DescribeDBProxiesInput{
+			DBProxyName: name,
+			Marker:      marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		proxies = append(proxies, result.DBProxies...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return proxies, nil
+}
+
+func (r *RDSManager) describeDBProxyEndpoints(ctx context.Context) ([]rdstypes.DBProxyEndpoint, error) {
+	var endpoints []rdstypes.DBProxyEndpoint
+	var marker *string
+
+	for {
+		result, err := r.rdsClient.DescribeDBProxyEndpoints(ctx, &rds.DescribeDBProxyEndpointsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, result.DBProxyEndpoints...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return endpoints, nil
+}
+
+// proxySecretARN returns the first secret the proxy authenticates with. Clients log in to
+// the proxy with the same username and password.
+func proxySecretARN(proxy rdstypes.DBProxy) string {
+	for _, auth := range proxy.Auth {
+		if auth.SecretArn != nil {
+			return *auth.SecretArn
+		}
+	}
+	return ""
+}
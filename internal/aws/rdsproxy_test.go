@@ -0,0 +1,201 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRDSManager_getProxyEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().DescribeDBProxies(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBProxiesOutput{
+		DBProxies: []rdstypes.DBProxy{
+			{
+				DBProxyName:  aws.String("app-proxy"),
+				Endpoint:     aws.String("app-proxy.proxy-xyz.us-east-1.rds.amazonaws.com"),
+				EngineFamily: aws.String("POSTGRESQL"),
+				Status:       rdstypes.DBProxyStatusAvailable,
+				Auth:         []rdstypes.UserAuthConfigInfo{{SecretArn: aws.String("arn:app-secret")}},
+			},
+			{
+				DBProxyName:  aws.String("new-proxy"),
+				Endpoint:     aws.String("new-proxy.proxy-xyz.us-east-1.rds.amazonaws.com"),
+				EngineFamily: aws.String("MYSQL"),
+				Status:       rdstypes.DBProxyStatusCreating,
+			},
+		},
+	}, nil)
+	mockRDS.EXPECT().DescribeDBProxyEndpoints(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBProxyEndpointsOutput{
+		DBProxyEndpoints: []rdstypes.DBProxyEndpoint{
+			{
+				DBProxyName:         aws.String("app-proxy"),
+				DBProxyEndpointName: aws.String("default"),
+				Endpoint:            aws.String("app-proxy.proxy-xyz.us-east-1.rds.amazonaws.com"),
+				IsDefault:           aws.Bool(true),
+				Status:              rdstypes.DBProxyEndpointStatusAvailable,
+			},
+			{
+				DBProxyName:         aws.String("app-proxy"),
+				DBProxyEndpointName: aws.String("reporting"),
+				Endpoint:            aws.String("reporting.endpoint.proxy-xyz.us-east-1.rds.amazonaws.com"),
+				IsDefault:           aws.Bool(false),
+				Status:              rdstypes.DBProxyEndpointStatusAvailable,
+				TargetRole:          rdstypes.DBProxyEndpointTargetRoleReadOnly,
+			},
+			{
+				DBProxyName:         aws.String("new-proxy"),
+				DBProxyEndpointName: aws.String("writes"),
+				Endpoint:            aws.String("writes.endpoint.proxy-xyz.us-east-1.rds.amazonaws.com"),
+				Status:              rdstypes.DBProxyEndpointStatusAvailable,
+			},
+		},
+	}, nil)
+
+	instances, err := manager.getProxyEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []RDSInstance{
+		{
+			Identifier:   "app-proxy (proxy)",
+			Endpoint:     "app-proxy.proxy-xyz.us-east-1.rds.amazonaws.com",
+			Port:         5432,
+			Engine:       "postgres",
+			EndpointType: "proxy",
+			ProxyName:    "app-proxy",
+			Role:         "writer",
+			Region:       "us-east-1",
+			SecretARN:    "arn:app-secret",
+		},
+		{
+			Identifier:   "app-proxy (proxy:reporting)",
+			Endpoint:     "reporting.endpoint.proxy-xyz.us-east-1.rds.amazonaws.com",
+			Port:         5432,
+			Engine:       "postgres",
+			EndpointType: "proxy-endpoint",
+			ProxyName:    "app-proxy",
+			EndpointName: "reporting",
+			Role:         "reader",
+			Region:       "us-east-1",
+			SecretARN:    "arn:app-secret",
+		},
+	}
+	if !reflect.DeepEqual(instances, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, instances)
+	}
+}
+
+func TestRDSManager_getProxyEndpoints_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		expectError bool
+	}{
+		{"missing permission skips proxies", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"expired credentials", &smithy.GenericAPIError{Code: "ExpiredToken"}, true},
+		{"network error skips proxies", errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRDS := mocks.NewMockRDSClient(ctrl)
+			manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+				RDSClient: mockRDS,
+				Region:    "us-east-1",
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error creating manager: %v", err)
+			}
+
+			mockRDS.EXPECT().DescribeDBProxies(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			instances, err := manager.getProxyEndpoints(context.Background())
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if len(instances) != 0 {
+				t.Errorf("Expected no proxies, got %d", len(instances))
+			}
+		})
+	}
+}
+
+func TestRDSManager_getRDSSecurityGroups_Proxy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), &rds.DescribeDBProxiesInput{DBProxyName: aws.String("app-proxy")}).
+		Return(&rds.DescribeDBProxiesOutput{
+			DBProxies: []rdstypes.DBProxy{{DBProxyName: aws.String("app-proxy"), VpcSecurityGroupIds: []string{"sg-proxy"}}},
+		}, nil)
+	mockRDS.EXPECT().
+		DescribeDBProxyEndpoints(gomock.Any(), &rds.DescribeDBProxyEndpointsInput{
+			DBProxyName:         aws.String("app-proxy"),
+			DBProxyEndpointName: aws.String("reporting"),
+		}).
+		Return(&rds.DescribeDBProxyEndpointsOutput{
+			DBProxyEndpoints: []rdstypes.DBProxyEndpoint{{VpcSecurityGroupIds: []string{"sg-reporting", "sg-shared"}}},
+		}, nil)
+
+	tests := []struct {
+		name     string
+		instance RDSInstance
+		expected []string
+	}{
+		{
+			name:     "proxy",
+			instance: RDSInstance{Identifier: "app-proxy (proxy)", EndpointType: "proxy", ProxyName: "app-proxy"},
+			expected: []string{"sg-proxy"},
+		},
+		{
+			name:     "additional proxy endpoint",
+			instance: RDSInstance{Identifier: "app-proxy (proxy:reporting)", EndpointType: "proxy-endpoint", ProxyName: "app-proxy", EndpointName: "reporting"},
+			expected: []string{"sg-reporting", "sg-shared"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sgIds, err := manager.getRDSSecurityGroups(context.Background(), tt.instance)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(sgIds, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, sgIds)
+			}
+		})
+	}
+}
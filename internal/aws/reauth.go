@@ -0,0 +1,194 @@
+package aws
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// reauthenticator is the single re-authentication layer behind every client built from
+// loadAWSConfig. A credential provider failure or a service rejecting the credentials
+// prompts once, logs in again, swaps the credentials for every client and retries the
+// call, so managers never handle expired credentials themselves.
+//
+// The SSO portal calls (ListAccounts, ListAccountRoles, GetRoleCredentials) are the one
+// exception. They authorize with the SSO access token rather than the profile's credentials,
+// and renewing that token is what login itself does: SSOManager.authenticate refreshes the
+// cached token or signs in again. Routing them through this layer would make login prompt
+// to log in.
+type reauthenticator struct {
+	mu         sync.Mutex
+	provider   aws.CredentialsProvider // Credentials of the active profile
+	cache      *aws.CredentialsCache   // Shared by every client, invalidated after re-login
+	generation int                     // Incremented after every re-login
+	declined   int                     // Generation the user declined to re-authenticate at
+
+	// login prompts for and runs a re-login, reporting whether it happened
+	login func(ctx context.Context) (bool, error)
+	// loadProvider returns the credentials of the active profile after a re-login
+	loadProvider func(ctx context.Context) (aws.CredentialsProvider, error)
+}
+
+// sharedReauth is process-wide: every config loads the same per-terminal profile, so one
+// re-login serves all of them
+var sharedReauth = newReauthenticator(PromptForReauth, func(ctx context.Context) (aws.CredentialsProvider, error) {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Credentials, nil
+})
+
+func newReauthenticator(login func(context.Context) (bool, error), loadProvider func(context.Context) (aws.CredentialsProvider, error)) *reauthenticator {
+	r := &reauthenticator{
+		declined:     -1,
+		login:        login,
+		loadProvider: loadProvider,
+	}
+	r.cache = aws.NewCredentialsCache(aws.CredentialsProviderFunc(r.retrieve))
+	return r
+}
+
+// loadAWSConfig loads the active profile's config with re-authentication attached. Managers
+// use it instead of awscconfig.LoadAWSConfigWithProfile.
+func loadAWSConfig(ctx context.Context) (aws.Config, error) {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	return sharedReauth.attach(cfg), nil
+}
+
+// attach routes cfg's credentials through the shared cache and adds the retry middleware
+func (r *reauthenticator) attach(cfg aws.Config) aws.Config {
+	r.mu.Lock()
+	// A freshly loaded profile is never older than the one already held
+	r.provider = cfg.Credentials
+	r.cache.Invalidate()
+	r.mu.Unlock()
+
+	cfg.Credentials = r.cache
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		// Outside the SDK retry loop so the retried call gets fresh credentials and signature
+		return stack.Finalize.Add(&reauthMiddleware{reauth: r}, middleware.Before)
+	})
+	return cfg
+}
+
+// reload switches every client to the active profile's credentials after a login that
+// happened outside this layer (e.g. the expiry check before an SSM session)
+func (r *reauthenticator) reload(ctx context.Context) error {
+	provider, err := r.loadProvider(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider = provider
+	r.generation++
+	r.cache.Invalidate()
+	return nil
+}
+
+func (r *reauthenticator) current() (aws.CredentialsProvider, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.provider, r.generation
+}
+
+// retrieve resolves credentials from the active profile, re-logging in when the provider
+// reports they are missing or expired
+func (r *reauthenticator) retrieve(ctx context.Context) (aws.Credentials, error) {
+	provider, seen := r.current()
+	if provider == nil {
+		return aws.Credentials{}, awscconfig.ErrNoActiveSession
+	}
+
+	creds, err := provider.Retrieve(ctx)
+	if err == nil || !IsAuthError(err) {
+		return creds, err
+	}
+
+	relogged, loginErr := r.relogin(ctx, seen)
+	if loginErr != nil {
+		return aws.Credentials{}, loginErr
+	}
+	if !relogged {
+		return creds, err
+	}
+
+	provider, _ = r.current()
+	return provider.Retrieve(ctx)
+}
+
+// relogin runs one re-login for callers whose credentials were rejected at generation seen.
+// Concurrent callers wait for the first one: if it logged in they retry with the new
+// credentials, and if the user declined they fail without prompting again.
+func (r *reauthenticator) relogin(ctx context.Context, seen int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.generation != seen {
+		return true, nil
+	}
+	if r.declined == seen {
+		return false, nil
+	}
+
+	relogged, err := r.login(ctx)
+	if err != nil || !relogged {
+		r.declined = seen
+		return false, err
+	}
+
+	provider, err := r.loadProvider(ctx)
+	if err != nil {
+		r.declined = seen
+		return false, err
+	}
+
+	r.provider = provider
+	r.generation++
+	r.cache.Invalidate()
+	return true, nil
+}
+
+// reauthMiddleware retries a call once after re-login when the service rejects its credentials
+type reauthMiddleware struct {
+	reauth *reauthenticator
+}
+
+func (*reauthMiddleware) ID() string {
+	return "AwscReauth"
+}
+
+func (m *reauthMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	middleware.FinalizeOutput, middleware.Metadata, error,
+) {
+	_, seen := m.reauth.current()
+
+	out, metadata, err := next.HandleFinalize(ctx, in)
+	if err == nil || !IsAuthError(err) {
+		return out, metadata, err
+	}
+
+	relogged, loginErr := m.reauth.relogin(ctx, seen)
+	if loginErr != nil {
+		return out, metadata, loginErr
+	}
+	if !relogged {
+		return out, metadata, err
+	}
+
+	if req, ok := in.Request.(*smithyhttp.Request); ok {
+		if rewindErr := req.RewindStream(); rewindErr != nil {
+			return out, metadata, err
+		}
+	}
+	return next.HandleFinalize(ctx, in)
+}
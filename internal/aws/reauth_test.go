@@ -0,0 +1,176 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+func staticProvider(accessKey string) aws.CredentialsProvider {
+	return credentials.NewStaticCredentialsProvider(accessKey, "secret", "token")
+}
+
+func TestReauthenticator_ConcurrentCallersShareOneLogin(t *testing.T) {
+	var logins atomic.Int32
+	r := newReauthenticator(func(ctx context.Context) (bool, error) {
+		logins.Add(1)
+		time.Sleep(20 * time.Millisecond) // Hold the prompt while the other callers arrive
+		return true, nil
+	}, func(ctx context.Context) (aws.CredentialsProvider, error) {
+		return staticProvider("AKIANEW"), nil
+	})
+
+	var wg sync.WaitGroup
+	results := make([]bool, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = r.relogin(context.Background(), 0)
+		}(i)
+	}
+	wg.Wait()
+
+	if logins.Load() != 1 {
+		t.Errorf("Expected 1 login, got %d", logins.Load())
+	}
+	for i, relogged := range results {
+		if !relogged {
+			t.Errorf("Caller %d: expected to retry after the shared login", i)
+		}
+	}
+}
+
+func TestReauthenticator_DeclinedLoginNotRepeated(t *testing.T) {
+	logins := 0
+	r := newReauthenticator(func(ctx context.Context) (bool, error) {
+		logins++
+		return false, nil
+	}, func(ctx context.Context) (aws.CredentialsProvider, error) {
+		t.Fatal("Provider should not be reloaded after a declined login")
+		return nil, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if relogged, err := r.relogin(context.Background(), 0); relogged || err != nil {
+			t.Errorf("Expected declined login, got relogged=%v err=%v", relogged, err)
+		}
+	}
+	if logins != 1 {
+		t.Errorf("Expected 1 prompt, got %d", logins)
+	}
+}
+
+func TestReauthenticator_RetrieveRelogsInOnProviderError(t *testing.T) {
+	logins := 0
+	r := newReauthenticator(func(ctx context.Context) (bool, error) {
+		logins++
+		return true, nil
+	}, func(ctx context.Context) (aws.CredentialsProvider, error) {
+		return staticProvider("AKIANEW"), nil
+	})
+
+	cfg := r.attach(aws.Config{Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, &ssocreds.InvalidTokenError{}
+	})})
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.AccessKeyID != "AKIANEW" {
+		t.Errorf("Expected credentials after re-login, got %s", creds.AccessKeyID)
+	}
+	if logins != 1 {
+		t.Errorf("Expected 1 login, got %d", logins)
+	}
+}
+
+// fakeHTTPClient returns the queued responses in order and records the requests
+type fakeHTTPClient struct {
+	responses []*http.Response
+	requests  []*http.Request
+}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestReauthMiddleware_RetriesRejectedCall(t *testing.T) {
+	tests := []struct {
+		name            string
+		loginSucceeds   bool
+		expectedLogins  int
+		expectedCalls   int
+		expectError     bool
+		expectedRetryAK string
+	}{
+		{"re-login retries with new credentials", true, 1, 2, false, "AKIANEW"},
+		{"declined re-login returns the error", false, 1, 1, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins := 0
+			r := newReauthenticator(func(ctx context.Context) (bool, error) {
+				logins++
+				return tt.loginSucceeds, nil
+			}, func(ctx context.Context) (aws.CredentialsProvider, error) {
+				return staticProvider("AKIANEW"), nil
+			})
+
+			httpClient := &fakeHTTPClient{responses: []*http.Response{
+				jsonResponse(400, `{"__type":"ExpiredTokenException","message":"The security token included in the request is expired"}`),
+				jsonResponse(200, `{"Name":"db","SecretString":"s3cret"}`),
+			}}
+			cfg := r.attach(aws.Config{
+				Region:      "us-east-1",
+				Credentials: staticProvider("AKIAOLD"),
+				HTTPClient:  httpClient,
+			})
+
+			client := secretsmanager.NewFromConfig(cfg)
+			result, err := client.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String("db")})
+
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if !tt.expectError && aws.ToString(result.SecretString) != "s3cret" {
+				t.Errorf("Expected secret from retried call, got %v", result.SecretString)
+			}
+			if logins != tt.expectedLogins {
+				t.Errorf("Expected %d logins, got %d", tt.expectedLogins, logins)
+			}
+			if len(httpClient.requests) != tt.expectedCalls {
+				t.Fatalf("Expected %d requests, got %d", tt.expectedCalls, len(httpClient.requests))
+			}
+			if tt.expectedRetryAK != "" {
+				auth := httpClient.requests[len(httpClient.requests)-1].Header.Get("Authorization")
+				if !strings.Contains(auth, tt.expectedRetryAK) {
+					t.Errorf("Expected retry signed with %s, got %s", tt.expectedRetryAK, auth)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// RegionsAll is the --regions value that queries every region enabled for the account
+const RegionsAll = "all"
+
+// ParseRegions splits a comma-separated --regions value into unique region names. It returns
+// nil for an empty value; "all" must be expanded with EnabledRegions instead.
+func ParseRegions(value string) ([]string, error) {
+	var regions []string
+	seen := make(map[string]bool)
+	for _, region := range strings.Split(value, ",") {
+		region = strings.ToLower(strings.TrimSpace(region))
+		if region == "" || seen[region] {
+			continue
+		}
+		if region == RegionsAll {
+			return nil, fmt.Errorf("'all' can't be combined with other regions")
+		}
+		if err := awscconfig.ValidateRegion(region); err != nil {
+			return nil, err
+		}
+		seen[region] = true
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+// ResolveRegions expands a --regions value into the regions to query: nil for an empty
+// value (the profile's region), every enabled region for "all", or the listed regions
+func ResolveRegions(ctx context.Context, value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	if strings.EqualFold(strings.TrimSpace(value), RegionsAll) {
+		cfg, err := loadAWSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return EnabledRegions(ctx, ec2.NewFromConfig(cfg))
+	}
+	return ParseRegions(value)
+}
+
+// EnabledRegions returns the regions enabled for the account, sorted by name
+func EnabledRegions(ctx context.Context, client EC2Client) ([]string, error) {
+	result, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{
+		AllRegions: aws.Bool(false), // Opt-in regions the account hasn't enabled reject every call
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing regions: %w", err)
+	}
+
+	var regions []string
+	for _, region := range result.Regions {
+		if region.RegionName != nil {
+			regions = append(regions, *region.RegionName)
+		}
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// queryRegions runs query for every region concurrently and merges the results in region
+// order. A region that fails is reported and skipped, unless every region fails.
+func queryRegions[T any](ctx context.Context, regions []string, query func(ctx context.Context, region string) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(regions))
+	errs := make([]error, len(regions))
+
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = query(ctx, region)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed++
+		// Re-login was declined, so every region failed the same way
+		if IsAuthError(err) {
+			return nil, err
+		}
+	}
+	if failed > 0 && failed == len(regions) {
+		return nil, fmt.Errorf("all regions failed: %v", errs[0])
+	}
+
+	var merged []T
+	for i, region := range regions {
+		if errs[i] != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping region %s: %v\n", region, errs[i])
+			continue
+		}
+		merged = append(merged, results[i]...)
+	}
+	return merged, nil
+}
+
+// regionalConfig returns a copy of cfg for region, sharing its credentials. An empty region
+// keeps cfg's region.
+func regionalConfig(cfg aws.Config, region string) aws.Config {
+	regional := cfg.Copy()
+	if region != "" {
+		regional.Region = region
+	}
+	return regional
+}
+
+// regionLabel prefixes a selector label with its region when several regions are listed
+func regionLabel(multiRegion bool, region, label string) string {
+	if !multiRegion {
+		return label
+	}
+	return fmt.Sprintf("%-15s %s", region, label)
+}
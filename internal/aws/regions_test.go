@@ -0,0 +1,293 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretstypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseRegions(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "empty", value: "", expected: nil},
+		{name: "single region", value: "us-east-1", expected: []string{"us-east-1"}},
+		{name: "several regions keep their order", value: "eu-west-1,us-east-1", expected: []string{"eu-west-1", "us-east-1"}},
+		{name: "spaces, case and duplicates", value: " US-EAST-1 , eu-west-1,us-east-1,", expected: []string{"us-east-1", "eu-west-1"}},
+		{name: "govcloud region", value: "us-gov-west-1", expected: []string{"us-gov-west-1"}},
+		{name: "invalid region", value: "us-east-1,useast1", wantErr: true},
+		{name: "all mixed with regions", value: "all,us-east-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regions, err := ParseRegions(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %v", regions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(regions, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, regions)
+			}
+		})
+	}
+}
+
+func TestResolveRegions_ExplicitList(t *testing.T) {
+	// Listed regions are resolved without loading AWS config
+	regions, err := ResolveRegions(context.Background(), "us-east-1,eu-west-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(regions, []string{"us-east-1", "eu-west-1"}) {
+		t.Errorf("Unexpected regions: %v", regions)
+	}
+
+	regions, err = ResolveRegions(context.Background(), "")
+	if err != nil || regions != nil {
+		t.Errorf("Expected no regions for an empty value, got %v (%v)", regions, err)
+	}
+}
+
+func TestEnabledRegions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockEC2.EXPECT().
+		DescribeRegions(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+			if input.AllRegions == nil || *input.AllRegions {
+				t.Error("Expected only enabled regions to be requested")
+			}
+			return &ec2.DescribeRegionsOutput{
+				Regions: []ec2types.Region{
+					{RegionName: aws.String("us-west-2")},
+					{RegionName: aws.String("eu-west-1")},
+					{RegionName: aws.String("us-east-1")},
+				},
+			}, nil
+		})
+
+	regions, err := EnabledRegions(context.Background(), mockEC2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"eu-west-1", "us-east-1", "us-west-2"}
+	if !reflect.DeepEqual(regions, expected) {
+		t.Errorf("Expected %v, got %v", expected, regions)
+	}
+}
+
+func TestQueryRegions(t *testing.T) {
+	regions := []string{"us-east-1", "eu-west-1", "ap-southeast-2"}
+
+	tests := []struct {
+		name     string
+		failing  map[string]error
+		expected []string
+		wantErr  bool
+		wantAuth bool
+	}{
+		{
+			name:     "merges results in region order",
+			expected: []string{"us-east-1", "eu-west-1", "ap-southeast-2"},
+		},
+		{
+			name:     "skips a failing region",
+			failing:  map[string]error{"eu-west-1": errors.New("UnrecognizedClientException")},
+			expected: []string{"us-east-1", "ap-southeast-2"},
+		},
+		{
+			name: "fails when every region fails",
+			failing: map[string]error{
+				"us-east-1":      errors.New("boom"),
+				"eu-west-1":      errors.New("boom"),
+				"ap-southeast-2": errors.New("boom"),
+			},
+			wantErr: true,
+		},
+		{
+			name:     "returns auth errors",
+			failing:  map[string]error{"ap-southeast-2": awscconfig.ErrNoActiveSession},
+			wantErr:  true,
+			wantAuth: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := queryRegions(context.Background(), regions, func(ctx context.Context, region string) ([]string, error) {
+				if err := tt.failing[region]; err != nil {
+					return nil, err
+				}
+				return []string{region}, nil
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %v", results)
+				}
+				if IsAuthError(err) != tt.wantAuth {
+					t.Errorf("Expected IsAuthError %v for %v", tt.wantAuth, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(results, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, results)
+			}
+		})
+	}
+}
+
+func TestRegionLabel(t *testing.T) {
+	if got := regionLabel(false, "eu-west-1", "db (postgres:5432)"); got != "db (postgres:5432)" {
+		t.Errorf("Expected the label unchanged for a single region, got %q", got)
+	}
+	if got := regionLabel(true, "eu-west-1", "db (postgres:5432)"); got != "eu-west-1       db (postgres:5432)" {
+		t.Errorf("Expected a region column, got %q", got)
+	}
+}
+
+func TestRegionalConfig(t *testing.T) {
+	cfg := aws.Config{Region: "us-east-1"}
+
+	if regional := regionalConfig(cfg, "eu-west-1"); regional.Region != "eu-west-1" {
+		t.Errorf("Expected eu-west-1, got %s", regional.Region)
+	}
+	if regional := regionalConfig(cfg, ""); regional.Region != "us-east-1" {
+		t.Errorf("Expected the config's region to be kept, got %s", regional.Region)
+	}
+	if cfg.Region != "us-east-1" {
+		t.Errorf("Expected the original config to be unchanged, got %s", cfg.Region)
+	}
+}
+
+func TestNewManagers_Regions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	regions := []string{"us-east-1", "eu-west-1"}
+
+	rdsManager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mocks.NewMockRDSClient(ctrl),
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		Regions:   regions,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rdsManager.regional) != 2 || rdsManager.forRegion("eu-west-1").region != "eu-west-1" {
+		t.Errorf("Expected a manager per region, got %v", rdsManager.regional)
+	}
+	if rdsManager.forRegion("") != rdsManager {
+		t.Error("Expected an unknown region to fall back to the manager itself")
+	}
+
+	// A single region replaces the profile's region instead of creating regional managers
+	ec2Manager, err := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		SSMClient: mocks.NewMockSSMClient(ctrl),
+		Region:    "us-east-1",
+		Regions:   []string{"ap-southeast-2"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ec2Manager.region != "ap-southeast-2" || ec2Manager.regional != nil {
+		t.Errorf("Expected a single ap-southeast-2 manager, got region %s and %v", ec2Manager.region, ec2Manager.regional)
+	}
+}
+
+func TestSecretsManager_ListSecrets_Regions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewSecretsManager(context.Background(), SecretsManagerOptions{
+		Client:  mocks.NewMockSecretsManagerClient(ctrl),
+		Regions: []string{"us-east-1", "eu-west-1"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Each region has its own client, as in production
+	for _, region := range manager.regions {
+		client := mocks.NewMockSecretsManagerClient(ctrl)
+		client.EXPECT().ListSecrets(gomock.Any(), gomock.Any()).Return(&secretsmanager.ListSecretsOutput{
+			SecretList: []secretstypes.SecretListEntry{
+				{
+					Name: aws.String("app/db"),
+					ARN:  aws.String("arn:aws:secretsmanager:" + region + ":123456789012:secret:app/db-abc123"),
+				},
+			},
+		}, nil)
+		manager.regional[region].client = client
+	}
+
+	secrets, err := manager.ListSecrets(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(secrets) != 2 {
+		t.Fatalf("Expected a secret per region, got %d", len(secrets))
+	}
+	for i, region := range manager.regions {
+		if secrets[i].Region != region {
+			t.Errorf("Expected secret %d in %s, got %s", i, region, secrets[i].Region)
+		}
+	}
+}
+
+func TestSecretsManager_RunShowSecrets_RegionsByName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewSecretsManager(context.Background(), SecretsManagerOptions{
+		Client:  mocks.NewMockSecretsManagerClient(ctrl),
+		Regions: []string{"us-east-1", "eu-west-1"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	usClient := mocks.NewMockSecretsManagerClient(ctrl)
+	usClient.EXPECT().ListSecrets(gomock.Any(), gomock.Any()).Return(&secretsmanager.ListSecretsOutput{}, nil)
+	manager.regional["us-east-1"].client = usClient
+
+	// The secret only exists in eu-west-1, so its value must be read there
+	euClient := mocks.NewMockSecretsManagerClient(ctrl)
+	euClient.EXPECT().ListSecrets(gomock.Any(), gomock.Any()).Return(&secretsmanager.ListSecretsOutput{
+		SecretList: []secretstypes.SecretListEntry{
+			{Name: aws.String("app/db"), ARN: aws.String("arn:aws:secretsmanager:eu-west-1:123456789012:secret:app/db-abc123")},
+		},
+	}, nil)
+	euClient.EXPECT().GetSecretValue(gomock.Any(), gomock.Any()).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("value"),
+	}, nil)
+	manager.regional["eu-west-1"].client = euClient
+
+	if err := manager.RunShowSecrets(context.Background(), "app/db"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
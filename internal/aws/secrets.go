@@ -8,7 +8,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	secretstypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
-	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/blontic/awsc/internal/ui"
 )
 
@@ -19,43 +18,101 @@ type SecretsManagerClient interface {
 }
 
 type SecretsManager struct {
-	client SecretsManagerClient
-	region string
+	client   SecretsManagerClient
+	region   string
+	regions  []string                   // Regions queried with --regions, in order
+	regional map[string]*SecretsManager // One manager per queried region
 }
 
 type Secret struct {
 	Name        string
 	Description string
 	ARN         string
+	Region      string
 }
 
 type SecretsManagerOptions struct {
-	Client SecretsManagerClient
-	Region string
+	Client  SecretsManagerClient
+	Region  string
+	Regions []string // Regions to query instead of the profile's region
 }
 
 func NewSecretsManager(ctx context.Context, opts ...SecretsManagerOptions) (*SecretsManager, error) {
+	var regions []string
+	if len(opts) > 0 {
+		regions = opts[0].Regions
+	}
+
 	if len(opts) > 0 && opts[0].Client != nil {
 		// Use provided client (for testing)
-		return &SecretsManager{
+		manager := &SecretsManager{
 			client: opts[0].Client,
 			region: opts[0].Region,
-		}, nil
+		}
+		manager.addRegions(regions, func(region string) *SecretsManager {
+			regional := *manager
+			regional.region = region
+			return &regional
+		})
+		return manager, nil
 	}
 
 	// Production path
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	cfg, err := loadAWSConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(regions) == 1 {
+		cfg.Region = regions[0]
+	}
+
+	manager := newSecretsManagerFromConfig(cfg)
+	manager.addRegions(regions, func(region string) *SecretsManager {
+		return newSecretsManagerFromConfig(regionalConfig(cfg, region))
+	})
+	return manager, nil
+}
 
+func newSecretsManagerFromConfig(cfg aws.Config) *SecretsManager {
 	return &SecretsManager{
 		client: secretsmanager.NewFromConfig(cfg),
 		region: cfg.Region,
-	}, nil
+	}
+}
+
+// addRegions creates a manager per region when more than one region is queried
+func (s *SecretsManager) addRegions(regions []string, newManager func(region string) *SecretsManager) {
+	if len(regions) == 1 {
+		s.region = regions[0]
+	}
+	if len(regions) < 2 {
+		return
+	}
+
+	// Create the regional managers first, so copies of this manager don't inherit its regions
+	regional := make(map[string]*SecretsManager, len(regions))
+	for _, region := range regions {
+		regional[region] = newManager(region)
+	}
+	s.regions = regions
+	s.regional = regional
+}
+
+// forRegion returns the manager for the region a secret was found in
+func (s *SecretsManager) forRegion(region string) *SecretsManager {
+	if regional, ok := s.regional[region]; ok {
+		return regional
+	}
+	return s
 }
 
 func (s *SecretsManager) ListSecrets(ctx context.Context) ([]Secret, error) {
+	if len(s.regions) > 0 {
+		return queryRegions(ctx, s.regions, func(ctx context.Context, region string) ([]Secret, error) {
+			return s.regional[region].ListSecrets(ctx)
+		})
+	}
+
 	var allSecrets []secretstypes.SecretListEntry
 	var nextToken *string
 
@@ -64,25 +121,7 @@ func (s *SecretsManager) ListSecrets(ctx context.Context) ([]Secret, error) {
 			NextToken: nextToken,
 		})
 		if err != nil {
-			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					// Reload client with fresh credentials
-					if reloadErr := s.reloadClient(ctx); reloadErr != nil {
-						return nil, reloadErr
-					}
-					// Retry after re-authentication
-					result, err = s.client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{
-						NextToken: nextToken,
-					})
-					if err != nil {
-						return nil, err
-					}
-				} else {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
+			return nil, err
 		}
 
 		allSecrets = append(allSecrets, result.SecretList...)
@@ -105,6 +144,7 @@ func (s *SecretsManager) ListSecrets(ctx context.Context) ([]Secret, error) {
 			Name:        *secret.Name,
 			Description: description,
 			ARN:         *secret.ARN,
+			Region:      s.region,
 		})
 	}
 
@@ -116,25 +156,7 @@ func (s *SecretsManager) GetSecretValue(ctx context.Context, secretName string)
 		SecretId: aws.String(secretName),
 	})
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				// Reload client with fresh credentials
-				if reloadErr := s.reloadClient(ctx); reloadErr != nil {
-					return "", reloadErr
-				}
-				// Retry after re-authentication
-				result, err = s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
-					SecretId: aws.String(secretName),
-				})
-				if err != nil {
-					return "", err
-				}
-			} else {
-				return "", err
-			}
-		} else {
-			return "", err
-		}
+		return "", err
 	}
 
 	if result.SecretString != nil {
@@ -159,6 +181,10 @@ func (s *SecretsManager) DisplaySecret(ctx context.Context, secretName, secretVa
 }
 
 func (s *SecretsManager) RunShowSecrets(ctx context.Context, secretName string) error {
+	if len(s.regions) > 0 {
+		return s.runShowSecretsInRegions(ctx, secretName)
+	}
+
 	// If secret name provided, try to show it directly
 	if secretName != "" {
 		fmt.Printf("Showing secret: %s\n", secretName)
@@ -181,6 +207,41 @@ func (s *SecretsManager) RunShowSecrets(ctx context.Context, secretName string)
 		return fmt.Errorf("error listing secrets: %v", err)
 	}
 
+	return s.selectAndShowSecret(ctx, secrets)
+}
+
+// runShowSecretsInRegions looks a named secret up in every queried region, since the same
+// name can exist in several of them
+func (s *SecretsManager) runShowSecretsInRegions(ctx context.Context, secretName string) error {
+	secrets, err := s.ListSecrets(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing secrets: %v", err)
+	}
+
+	if secretName != "" {
+		var matches []Secret
+		for _, secret := range secrets {
+			if secret.Name == secretName || secret.ARN == secretName {
+				matches = append(matches, secret)
+			}
+		}
+
+		switch {
+		case len(matches) == 1:
+			fmt.Printf("Showing secret: %s\n", secretName)
+			return s.showSecret(ctx, matches[0])
+		case len(matches) > 1:
+			fmt.Printf("Secret '%s' exists in several regions:\n\n", secretName)
+			secrets = matches
+		default:
+			fmt.Printf("Secret '%s' not found. Available secrets:\n\n", secretName)
+		}
+	}
+
+	return s.selectAndShowSecret(ctx, secrets)
+}
+
+func (s *SecretsManager) selectAndShowSecret(ctx context.Context, secrets []Secret) error {
 	if len(secrets) == 0 {
 		fmt.Printf("No secrets found in this account\n")
 		return nil
@@ -193,7 +254,7 @@ func (s *SecretsManager) RunShowSecrets(ctx context.Context, secretName string)
 		if description == "" {
 			description = "No description"
 		}
-		choices = append(choices, fmt.Sprintf("%s - %s", secret.Name, description))
+		choices = append(choices, regionLabel(len(s.regions) > 0, secret.Region, fmt.Sprintf("%s - %s", secret.Name, description)))
 	}
 
 	// Interactive secret selection
@@ -205,28 +266,20 @@ func (s *SecretsManager) RunShowSecrets(ctx context.Context, secretName string)
 		return fmt.Errorf("no secret selected")
 	}
 
-	selectedSecret := secrets[selectedIndex].Name
-	fmt.Printf("✓ Selected: %s\n", selectedSecret)
+	selectedSecret := secrets[selectedIndex]
+	fmt.Printf("✓ Selected: %s\n", selectedSecret.Name)
 
-	// Get secret value
-	secretValue, err := s.GetSecretValue(ctx, selectedSecret)
-	if err != nil {
-		return fmt.Errorf("error getting secret value: %v", err)
-	}
-
-	// Display the secret
-	s.DisplaySecret(ctx, selectedSecret, secretValue)
-	return nil
+	return s.showSecret(ctx, selectedSecret)
 }
 
-func (s *SecretsManager) reloadClient(ctx context.Context) error {
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+// showSecret fetches the secret from the region it was listed in and displays it
+func (s *SecretsManager) showSecret(ctx context.Context, secret Secret) error {
+	secretValue, err := s.forRegion(secret.Region).GetSecretValue(ctx, secret.Name)
 	if err != nil {
-		return err
+		return fmt.Errorf("error getting secret value: %v", err)
 	}
 
-	s.client = secretsmanager.NewFromConfig(cfg)
-	s.region = cfg.Region
-
+	// Display the secret
+	s.DisplaySecret(ctx, secret.Name, secretValue)
 	return nil
 }
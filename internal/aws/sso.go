@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"os"
 	"sort"
-	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sso"
 	"github.com/aws/aws-sdk-go-v2/service/sso/types"
 	awscconfig "github.com/blontic/awsc/internal/config"
@@ -14,12 +15,37 @@ import (
 	"github.com/spf13/viper"
 )
 
+// SSOClient interface for mocking
+type SSOClient interface {
+	ListAccounts(ctx context.Context, params *sso.ListAccountsInput, optFns ...func(*sso.Options)) (*sso.ListAccountsOutput, error)
+	ListAccountRoles(ctx context.Context, params *sso.ListAccountRolesInput, optFns ...func(*sso.Options)) (*sso.ListAccountRolesOutput, error)
+	GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error)
+	Logout(ctx context.Context, params *sso.LogoutInput, optFns ...func(*sso.Options)) (*sso.LogoutOutput, error)
+}
+
 type SSOManager struct {
-	client *sso.Client
+	client     SSOClient
+	stsClient  STSClient  // Only set in tests; chains build one from the source role credentials
+	oidcClient OIDCClient // Only set in tests; token refreshes otherwise build their own
+}
+
+type SSOManagerOptions struct {
+	Client     SSOClient
+	STSClient  STSClient
+	OIDCClient OIDCClient
 }
 
-func NewSSOManager(ctx context.Context) (*SSOManager, error) {
-	cfg, err := awscconfig.LoadAWSConfig(ctx)
+func NewSSOManager(ctx context.Context, opts ...SSOManagerOptions) (*SSOManager, error) {
+	if len(opts) > 0 && opts[0].Client != nil {
+		// Use provided client (for testing)
+		return &SSOManager{
+			client:     opts[0].Client,
+			stsClient:  opts[0].STSClient,
+			oidcClient: opts[0].OIDCClient,
+		}, nil
+	}
+
+	cfg, err := awscconfig.LoadSSOConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -99,101 +125,231 @@ func (s *SSOManager) GetRoleCredentials(ctx context.Context, accessToken, accoun
 	return result.RoleCredentials, nil
 }
 
+// Logout revokes the SSO access token and all role credentials issued with it
+func (s *SSOManager) Logout(ctx context.Context, accessToken string) error {
+	_, err := s.client.Logout(ctx, &sso.LogoutInput{
+		AccessToken: &accessToken,
+	})
+	return err
+}
+
+// RunLogout revokes the SSO token and removes the awsc profiles and sessions, either for
+// the current shell or, with all, for every shell
+func (s *SSOManager) RunLogout(ctx context.Context, all bool) error {
+	if viper.GetString("sso.start_url") == "" {
+		return fmt.Errorf("no SSO configuration found. Please run 'awsc config init' first")
+	}
+
+	credentialsManager, err := NewCredentialsManager(ctx, CredentialsManagerOptions{SSOManager: s})
+	if err != nil {
+		return fmt.Errorf("failed to create credentials manager: %v", err)
+	}
+
+	if err := credentialsManager.Logout(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Signed out of AWS SSO\n")
+
+	return removeLocalState(all)
+}
+
+// removeLocalState deletes awsc profiles, cached role credentials and session files
+func removeLocalState(all bool) error {
+	if all {
+		removed, err := awscconfig.RemoveAllProfiles()
+		if err != nil {
+			return fmt.Errorf("error removing profiles: %v", err)
+		}
+		for _, profileName := range removed {
+			fmt.Printf("✓ Removed profile: %s\n", profileName)
+		}
+		if err := awscconfig.ClearCredentialCache(); err != nil {
+			return fmt.Errorf("error removing cached credentials: %v", err)
+		}
+		if err := awscconfig.DeleteAllSessions(); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	session, err := awscconfig.GetCurrentSession()
+	if err != nil {
+		fmt.Printf("No active session in this shell\n")
+		return nil
+	}
+
+	if err := awscconfig.RemoveProfiles([]string{session.ProfileName}); err != nil {
+		return fmt.Errorf("error removing profile: %v", err)
+	}
+	fmt.Printf("✓ Removed profile: %s\n", session.ProfileName)
+
+	if err := awscconfig.DeleteCachedCredentials(session.AccountID, session.RoleName); err != nil {
+		return fmt.Errorf("error removing cached credentials: %v", err)
+	}
+
+	key, err := awscconfig.CurrentSessionKey()
+	if err != nil {
+		return err
+	}
+	return awscconfig.DeleteSession(key)
+}
+
 // RunLogin handles the complete SSO login workflow
 func (s *SSOManager) RunLogin(ctx context.Context, force bool, accountName, roleName string) error {
+	accessToken, accounts, refresh, err := s.loginAccounts(ctx, force)
+	if err != nil {
+		return err
+	}
+
+	return s.handleAccountRoleSelection(ctx, accessToken, accounts, refresh, accountName, roleName)
+}
+
+// authenticate returns a working SSO access token and the accounts it can access,
+// reusing the cached token when possible and running the device flow otherwise
+func (s *SSOManager) authenticate(ctx context.Context, force bool) (string, []types.AccountInfo, error) {
 
 	// Check if config exists
 	if viper.GetString("sso.start_url") == "" {
-		return fmt.Errorf("no SSO configuration found. Please run 'awsc config init' first")
+		return "", nil, fmt.Errorf("no SSO configuration found. Please run 'awsc config init' first")
 	}
 
 	// Create credentials manager for authentication
 	credentialsManager, err := NewCredentialsManager(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create credentials manager: %v", err)
+		return "", nil, fmt.Errorf("failed to create credentials manager: %v", err)
 	}
 
 	// Try to get cached SSO token and use it if valid (unless force is true)
 	if !force {
-		accessToken, err := credentialsManager.GetCachedToken()
+		accessToken, err := credentialsManager.GetCachedToken(ctx)
 		if err == nil {
 			// Try listing accounts to see if SSO token works
 			accounts, listErr := s.ListAccounts(ctx, *accessToken)
 			if listErr == nil && len(accounts) > 0 {
-				// SSO token works, save account cache and proceed with account/role selection
+				// SSO token works, save account cache and proceed
 				if err := awscconfig.SaveAccountCache(accounts); err != nil {
 					// Don't fail login if cache save fails
-					fmt.Printf("Warning: failed to save account cache: %v\n", err)
+					fmt.Fprintf(os.Stderr, "Warning: failed to save account cache: %v\n", err)
 				}
-				return s.handleAccountRoleSelection(ctx, *accessToken, accounts, accountName, roleName)
+				return *accessToken, accounts, nil
 			}
 		}
 	}
 
 	// If we get here, need to re-authenticate
-	fmt.Printf("Starting SSO authentication...\n")
+	fmt.Fprintf(os.Stderr, "Starting SSO authentication...\n")
 
 	// Try authentication
 	startURL := viper.GetString("sso.start_url")
 	ssoRegion := viper.GetString("sso.region")
 
 	if err := credentialsManager.Authenticate(ctx, startURL, ssoRegion); err != nil {
-		return fmt.Errorf("SSO authentication failed: %v", err)
+		return "", nil, fmt.Errorf("SSO authentication failed: %v", err)
 	}
 
 	// Get fresh access token
-	accessToken, err := credentialsManager.GetCachedToken()
+	accessToken, err := credentialsManager.GetCachedToken(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get access token: %v", err)
+		return "", nil, fmt.Errorf("failed to get access token: %v", err)
 	}
 
 	// List accounts for selection
 	accounts, err := s.ListAccounts(ctx, *accessToken)
 	if err != nil {
-		return fmt.Errorf("error listing accounts: %v", err)
+		return "", nil, fmt.Errorf("error listing accounts: %v", err)
 	}
 
 	if len(accounts) == 0 {
-		return fmt.Errorf("no accounts found")
+		return "", nil, fmt.Errorf("no accounts found")
 	}
 
 	// Save account cache
 	if err := awscconfig.SaveAccountCache(accounts); err != nil {
 		// Don't fail login if cache save fails, just log it
-		fmt.Printf("Warning: failed to save account cache: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to save account cache: %v\n", err)
 	}
 
-	return s.handleAccountRoleSelection(ctx, *accessToken, accounts, accountName, roleName)
+	return *accessToken, accounts, nil
 }
 
-func (s *SSOManager) handleAccountRoleSelection(ctx context.Context, accessToken string, accounts []types.AccountInfo, accountName, roleName string) error {
-	// Sort accounts alphabetically
-	sort.Slice(accounts, func(i, j int) bool {
-		return *accounts[i].AccountName < *accounts[j].AccountName
-	})
+func (s *SSOManager) handleAccountRoleSelection(ctx context.Context, accessToken string, accounts []types.AccountInfo, refresh *cacheRefresh, accountName, roleName string) error {
+	selectedAccount, selectedRole, err := s.selectAccountRole(ctx, accessToken, accounts, refresh, accountName, roleName)
+	if err != nil {
+		if refresh != nil {
+			refresh.wait() // Let the cache update finish
+		}
+		return err
+	}
 
-	var selectedAccount types.AccountInfo
-	var selectedAccountIndex int = -1
+	if accessToken, err = s.finishRefresh(ctx, refresh, accessToken); err != nil {
+		return err
+	}
 
-	// If account name provided, try to find exact match
-	if accountName != "" {
-		for i, account := range accounts {
-			if strings.EqualFold(*account.AccountName, accountName) {
-				selectedAccount = account
-				selectedAccountIndex = i
-				break
-			}
+	// Write profile to ~/.aws/config
+	profileName, expiration, err := s.writeProfile(ctx, accessToken, *selectedAccount.AccountName, *selectedAccount.AccountId, *selectedRole.RoleName)
+	if err != nil {
+		return err
+	}
+
+	return saveLoginSession(profileName, *selectedAccount.AccountId, *selectedAccount.AccountName, *selectedRole.RoleName, expiration)
+}
+
+// CredentialsFor returns credentials for an account and role without writing a profile or
+// binding them to this terminal, prompting for whichever of them isn't given or found.
+// Profiles are shared by every terminal using the account, so they must stay untouched.
+func (s *SSOManager) CredentialsFor(ctx context.Context, accountName, roleName string) (*SessionCredentials, error) {
+	accessToken, accounts, refresh, err := s.loginAccounts(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	account, role, err := s.selectAccountRole(ctx, accessToken, accounts, refresh, accountName, roleName)
+	if err != nil {
+		if refresh != nil {
+			refresh.wait() // Let the cache update finish
 		}
-		if selectedAccountIndex == -1 {
-			fmt.Printf("Account '%s' not found. Available accounts:\n\n", accountName)
-			// Fall through to show account list
-		} else {
-			fmt.Printf("Found account: %s\n", *selectedAccount.AccountName)
+		return nil, err
+	}
+
+	if accessToken, err = s.finishRefresh(ctx, refresh, accessToken); err != nil {
+		return nil, err
+	}
+
+	creds, err := s.GetRoleCredentials(ctx, accessToken, *account.AccountId, *role.RoleName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting role credentials: %v", err)
+	}
+
+	return &SessionCredentials{
+		Region:          viper.GetString("default_region"),
+		AccessKeyID:     aws.ToString(creds.AccessKeyId),
+		SecretAccessKey: aws.ToString(creds.SecretAccessKey),
+		SessionToken:    aws.ToString(creds.SessionToken),
+		Expiration:      time.UnixMilli(creds.Expiration),
+	}, nil
+}
+
+// selectAccountRole matches the account and role by ID, name, prefix or fuzzy match, falling
+// back to interactive selection when nothing matches. refresh is set when accounts came from
+// the account cache; the selection waits for it if the account isn't among the cached ones.
+func (s *SSOManager) selectAccountRole(ctx context.Context, accessToken string, accounts []types.AccountInfo, refresh *cacheRefresh, accountName, roleName string) (types.AccountInfo, types.RoleInfo, error) {
+	selectedAccount, err := selectAccount(accounts, accountName)
+	if err == nil && selectedAccount == nil && accountName != "" && refresh != nil {
+		// The account may be new since the cache was fetched
+		if fresh := refresh.freshAccounts(); fresh != nil {
+			accounts = fresh
+			selectedAccount, err = selectAccount(accounts, accountName)
 		}
 	}
+	if err != nil {
+		return types.AccountInfo{}, types.RoleInfo{}, err
+	}
+
+	if selectedAccount == nil {
+		if accountName != "" {
+			fmt.Fprintf(os.Stderr, "Account '%s' not found. Available accounts:\n\n", accountName)
+		}
 
-	// Show account selection if no account specified or account not found
-	if accountName == "" || selectedAccountIndex == -1 {
 		// Create account options
 		accountOptions := make([]string, len(accounts))
 		for i, account := range accounts {
@@ -203,23 +359,25 @@ func (s *SSOManager) handleAccountRoleSelection(ctx context.Context, accessToken
 		// Interactive account selection
 		selectedAccountIndex, err := ui.RunSelector("Select AWS Account:", accountOptions)
 		if err != nil {
-			return fmt.Errorf("error selecting account: %v", err)
+			return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("error selecting account: %v", err)
 		}
 		if selectedAccountIndex == -1 {
-			return fmt.Errorf("no account selected")
+			return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("no account selected")
 		}
-		selectedAccount = accounts[selectedAccountIndex]
+		selectedAccount = &accounts[selectedAccountIndex]
+	} else if accountName != "" {
+		fmt.Fprintf(os.Stderr, "Found account: %s\n", *selectedAccount.AccountName)
 	}
-	fmt.Printf("✓ Selected: %s\n", *selectedAccount.AccountName)
+	fmt.Fprintf(os.Stderr, "✓ Selected: %s\n", *selectedAccount.AccountName)
 
 	// List roles
-	roles, err := s.ListRoles(ctx, accessToken, *selectedAccount.AccountId)
+	roles, err := s.accountRoles(ctx, accessToken, *selectedAccount.AccountId, refresh)
 	if err != nil {
-		return fmt.Errorf("error listing roles: %v", err)
+		return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("error listing roles: %v", err)
 	}
 
 	if len(roles) == 0 {
-		return fmt.Errorf("no roles found for this account")
+		return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("no roles found for this account")
 	}
 
 	// Sort roles alphabetically
@@ -227,69 +385,109 @@ func (s *SSOManager) handleAccountRoleSelection(ctx context.Context, accessToken
 		return *roles[i].RoleName < *roles[j].RoleName
 	})
 
-	var selectedRole types.RoleInfo
-	var selectedRoleIndex int = -1
-
-	// If role name provided, try to find exact match
-	if roleName != "" {
-		for i, role := range roles {
-			if strings.EqualFold(*role.RoleName, roleName) {
-				selectedRole = role
-				selectedRoleIndex = i
-				break
-			}
-		}
-		if selectedRoleIndex == -1 {
-			fmt.Printf("Role '%s' not found in account %s. Available roles:\n\n", roleName, *selectedAccount.AccountName)
-			// Fall through to show role list
-		} else {
-			fmt.Printf("Found role: %s\n", *selectedRole.RoleName)
-		}
+	names := roleNames(roles)
+	selectedRoleIndex, err := matchName(roleName, names, nil)
+	if err != nil {
+		return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("role %v in account %s", err, *selectedAccount.AccountName)
 	}
 
-	// Show role selection if no role specified or role not found
-	if roleName == "" || selectedRoleIndex == -1 {
-		// Create role options
-		roleOptions := make([]string, len(roles))
-		for i, role := range roles {
-			roleOptions[i] = *role.RoleName
+	if selectedRoleIndex == -1 {
+		if roleName != "" {
+			fmt.Fprintf(os.Stderr, "Role '%s' not found in account %s. Available roles:\n\n", roleName, *selectedAccount.AccountName)
 		}
 
 		// Interactive role selection
-		selectedRoleIndex, err := ui.RunSelector(fmt.Sprintf("Select role for %s:", *selectedAccount.AccountName), roleOptions)
+		selectedRoleIndex, err = ui.RunSelector(fmt.Sprintf("Select role for %s:", *selectedAccount.AccountName), names)
 		if err != nil {
-			return fmt.Errorf("error selecting role: %v", err)
+			return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("error selecting role: %v", err)
 		}
 		if selectedRoleIndex == -1 {
-			return fmt.Errorf("no role selected")
+			return types.AccountInfo{}, types.RoleInfo{}, fmt.Errorf("no role selected")
 		}
-		selectedRole = roles[selectedRoleIndex]
+	} else if roleName != "" {
+		fmt.Fprintf(os.Stderr, "Found role: %s\n", names[selectedRoleIndex])
 	}
-	fmt.Printf("✓ Selected: %s\n", *selectedRole.RoleName)
+	selectedRole := roles[selectedRoleIndex]
+	fmt.Fprintf(os.Stderr, "✓ Selected: %s\n", *selectedRole.RoleName)
 
-	// Get credentials (AWS SSO automatically uses max duration for the role)
-	creds, err := s.GetRoleCredentials(ctx, accessToken, *selectedAccount.AccountId, *selectedRole.RoleName)
-	if err != nil {
-		return fmt.Errorf("error getting role credentials: %v", err)
+	return *selectedAccount, selectedRole, nil
+}
+
+// selectAccount sorts accounts by name and returns the one matching accountName, nil if
+// none matches, or an error if the match is ambiguous
+func selectAccount(accounts []types.AccountInfo, accountName string) (*types.AccountInfo, error) {
+	sort.Slice(accounts, func(i, j int) bool {
+		return *accounts[i].AccountName < *accounts[j].AccountName
+	})
+
+	names := make([]string, len(accounts))
+	ids := make([]string, len(accounts))
+	for i, account := range accounts {
+		names[i] = *account.AccountName
+		ids[i] = *account.AccountId
 	}
 
-	// Write profile to ~/.aws/config
-	profileName, err := awscconfig.WriteProfile(*selectedAccount.AccountName, *selectedAccount.AccountId, *selectedRole.RoleName, creds)
+	index, err := matchName(accountName, names, ids)
 	if err != nil {
-		return fmt.Errorf("error writing profile: %v", err)
+		return nil, fmt.Errorf("account %v (use a longer name or the account ID)", err)
+	}
+	if index == -1 {
+		return nil, nil
 	}
+	return &accounts[index], nil
+}
 
+// saveLoginSession binds the profile to the current shell and prints the login summary
+func saveLoginSession(profileName, accountID, accountName, roleName string, expiration time.Time) error {
 	// Save session for current shell
-	ppid := os.Getppid()
-	if err := awscconfig.SaveSession(ppid, profileName, *selectedAccount.AccountId, *selectedAccount.AccountName, *selectedRole.RoleName); err != nil {
+	key, err := awscconfig.CurrentSessionKey()
+	if err != nil {
+		return fmt.Errorf("error saving session: %v", err)
+	}
+	if err := awscconfig.SaveSession(key, profileName, accountID, accountName, roleName, expiration); err != nil {
 		return fmt.Errorf("error saving session: %v", err)
 	}
 
 	// Cleanup stale sessions (best effort, ignore errors)
 	_ = awscconfig.CleanupStaleSessions()
 
-	fmt.Printf("\nSuccessfully authenticated to %s (%s) as %s\n", *selectedAccount.AccountName, *selectedAccount.AccountId, *selectedRole.RoleName)
-	fmt.Printf("Profile: %s\n", profileName)
-	fmt.Printf("Use with AWS CLI: aws <command> --profile %s\n", profileName)
+	fmt.Fprintf(os.Stderr, "\nSuccessfully authenticated to %s (%s) as %s\n", accountName, accountID, roleName)
+	fmt.Fprintf(os.Stderr, "Profile: %s\n", profileName)
+	fmt.Fprintf(os.Stderr, "Use with AWS CLI: aws <command> --profile %s\n", profileName)
 	return nil
 }
+
+// writeProfile writes the awsc profile for the selected account and role using the configured
+// profile type, returning the profile name and when its credentials expire (zero if they refresh)
+func (s *SSOManager) writeProfile(ctx context.Context, accessToken, accountName, accountID, roleName string) (string, time.Time, error) {
+	if awscconfig.GetProfileType() == awscconfig.ProfileTypeSSO {
+		// Credentials are resolved by the SDK from the shared SSO token cache
+		profileName, err := awscconfig.WriteSSOProfile(accountName, accountID, roleName)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("error writing profile: %v", err)
+		}
+		return profileName, time.Time{}, nil
+	}
+
+	// Get credentials (AWS SSO automatically uses max duration for the role)
+	creds, err := s.GetRoleCredentials(ctx, accessToken, accountID, roleName)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error getting role credentials: %v", err)
+	}
+
+	if awscconfig.GetProfileType() == awscconfig.ProfileTypeCredentialProcess {
+		// Prime the credential_process cache so the first use doesn't need another API call
+		cacheRoleCredentials(accountID, roleName, creds)
+		profileName, err := awscconfig.WriteCredentialProcessProfile(accountName, accountID, roleName)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("error writing profile: %v", err)
+		}
+		return profileName, time.Time{}, nil
+	}
+
+	profileName, err := awscconfig.WriteProfile(accountName, accountID, roleName, creds)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error writing profile: %v", err)
+	}
+	return profileName, time.UnixMilli(creds.Expiration), nil
+}
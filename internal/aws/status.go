@@ -0,0 +1,146 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// STSClient interface for mocking
+type STSClient interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+}
+
+type StatusManager struct {
+	stsClient   STSClient
+	credentials aws.CredentialsProvider
+	region      string
+}
+
+type StatusManagerOptions struct {
+	STSClient   STSClient
+	Credentials aws.CredentialsProvider
+	Region      string
+}
+
+// Status describes the identity and expiry of the active awsc session
+type Status struct {
+	Profile               string     `json:"profile"`
+	Source                string     `json:"source"`
+	AccountID             string     `json:"account_id,omitempty"`
+	AccountName           string     `json:"account_name,omitempty"`
+	RoleName              string     `json:"role_name,omitempty"`
+	Arn                   string     `json:"arn,omitempty"`
+	Region                string     `json:"region"`
+	CredentialsExpiration *time.Time `json:"credentials_expiration,omitempty"`
+	SSOTokenExpiration    *time.Time `json:"sso_token_expiration,omitempty"`
+	Error                 string     `json:"error,omitempty"`
+}
+
+func NewStatusManager(ctx context.Context, opts ...StatusManagerOptions) (*StatusManager, error) {
+	if len(opts) > 0 && opts[0].STSClient != nil {
+		// Use provided clients (for testing)
+		return &StatusManager{
+			stsClient:   opts[0].STSClient,
+			credentials: opts[0].Credentials,
+			region:      opts[0].Region,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusManager{
+		stsClient:   sts.NewFromConfig(cfg),
+		credentials: cfg.Credentials,
+		region:      cfg.Region,
+	}, nil
+}
+
+// GetStatus resolves the active profile and verifies it against STS. Failures to reach
+// STS are reported in Status.Error so expired sessions can still be inspected.
+func (s *StatusManager) GetStatus(ctx context.Context) (*Status, error) {
+	profileName, source, err := awscconfig.ResolveProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		Profile: profileName,
+		Source:  source,
+		Region:  s.region,
+	}
+
+	// The PPID session knows the role name even if STS is unreachable
+	if source == awscconfig.SessionSourcePPID {
+		if session, err := awscconfig.GetCurrentSession(); err == nil {
+			status.AccountID = session.AccountID
+			status.AccountName = session.AccountName
+			status.RoleName = session.RoleName
+		}
+	}
+
+	if s.credentials != nil {
+		if creds, err := s.credentials.Retrieve(ctx); err != nil {
+			debug.Printf("Failed to retrieve credentials: %v\n", err)
+		} else if creds.CanExpire {
+			expires := creds.Expires
+			status.CredentialsExpiration = &expires
+		}
+	}
+
+	// Static credentials don't carry an expiry, so fall back to the recorded one
+	if status.CredentialsExpiration == nil {
+		if expires := awscconfig.GetCredentialExpiration(); !expires.IsZero() {
+			status.CredentialsExpiration = &expires
+		}
+	}
+
+	if credentialsManager, err := NewCredentialsManager(ctx); err == nil {
+		if expires, err := credentialsManager.GetTokenExpiration(); err == nil {
+			status.SSOTokenExpiration = &expires
+		}
+	}
+
+	identity, err := s.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+
+	status.AccountID = aws.ToString(identity.Account)
+	status.AccountName = awscconfig.GetAccountName(status.AccountID)
+	status.Arn = aws.ToString(identity.Arn)
+	if status.RoleName == "" {
+		status.RoleName = roleNameFromArn(status.Arn)
+	}
+
+	return status, nil
+}
+
+// roleNameFromArn extracts the permission set name from an SSO assumed-role ARN such as
+// arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Admin_0123456789abcdef/user
+func roleNameFromArn(arn string) string {
+	parts := strings.Split(arn, "/")
+	if len(parts) < 2 || !strings.HasSuffix(parts[0], ":assumed-role") {
+		return ""
+	}
+
+	role := parts[1]
+	if strings.HasPrefix(role, "AWSReservedSSO_") {
+		role = strings.TrimPrefix(role, "AWSReservedSSO_")
+		if i := strings.LastIndex(role, "_"); i > 0 {
+			role = role[:i]
+		}
+	}
+	return role
+}
@@ -0,0 +1,119 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoleNameFromArn(t *testing.T) {
+	tests := []struct {
+		arn      string
+		expected string
+	}{
+		{"arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_AdministratorAccess_0123456789abcdef/user@example.com", "AdministratorAccess"},
+		{"arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Read_Only_0123456789abcdef/user", "Read_Only"},
+		{"arn:aws:sts::123456789012:assumed-role/deploy-role/session", "deploy-role"},
+		{"arn:aws:iam::123456789012:user/alice", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			if result := roleNameFromArn(tt.arn); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestStatusManager_GetStatus(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_PROFILE", "awsc-prod")
+
+	if err := awscconfig.SaveAccountCache(nil); err != nil {
+		t.Fatalf("SaveAccountCache failed: %v", err)
+	}
+
+	expires := time.Now().Add(30 * time.Minute)
+
+	tests := []struct {
+		name        string
+		identityErr error
+		expectError bool
+		expectRole  string
+	}{
+		{"identity resolved", nil, false, "Admin"},
+		{"expired credentials reported", fmt.Errorf("ExpiredToken"), true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSTS := mocks.NewMockSTSClient(ctrl)
+			if tt.identityErr != nil {
+				mockSTS.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any()).Return(nil, tt.identityErr)
+			} else {
+				mockSTS.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+					Account: aws.String("123456789012"),
+					Arn:     aws.String("arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Admin_abc123/user"),
+				}, nil)
+			}
+
+			manager, err := NewStatusManager(context.Background(), StatusManagerOptions{
+				STSClient: mockSTS,
+				Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+					return aws.Credentials{AccessKeyID: "AKIATEST", CanExpire: true, Expires: expires}, nil
+				}),
+				Region: "us-east-1",
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			status, err := manager.GetStatus(context.Background())
+			if err != nil {
+				t.Fatalf("GetStatus failed: %v", err)
+			}
+
+			if status.Profile != "awsc-prod" || status.Source != awscconfig.SessionSourceEnv {
+				t.Errorf("Expected awsc-prod from AWSC_PROFILE, got %s from %s", status.Profile, status.Source)
+			}
+			if status.Region != "us-east-1" {
+				t.Errorf("Expected region us-east-1, got %s", status.Region)
+			}
+			if status.CredentialsExpiration == nil || !status.CredentialsExpiration.Equal(expires) {
+				t.Errorf("Expected credentials expiration %v, got %v", expires, status.CredentialsExpiration)
+			}
+			if (status.Error != "") != tt.expectError {
+				t.Errorf("Expected error: %v, got: %q", tt.expectError, status.Error)
+			}
+			if status.RoleName != tt.expectRole {
+				t.Errorf("Expected role %q, got %q", tt.expectRole, status.RoleName)
+			}
+		})
+	}
+}
+
+func TestStatusManager_GetStatus_NoSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWSC_PROFILE", "")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, _ := NewStatusManager(context.Background(), StatusManagerOptions{STSClient: mocks.NewMockSTSClient(ctrl)})
+	if _, err := manager.GetStatus(context.Background()); err == nil {
+		t.Error("Expected 'no active session' error")
+	}
+}
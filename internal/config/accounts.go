@@ -2,32 +2,137 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/spf13/viper"
+)
+
+const (
+	// accountCacheVersion is the current accounts.json format. Version 1 (unversioned)
+	// only held account names; version 2 adds roles and when they were fetched.
+	accountCacheVersion = 2
+
+	// defaultAccountCacheTTLMinutes is how long the account cache is used to show the
+	// account selector before refreshing it from SSO first
+	defaultAccountCacheTTLMinutes = 24 * 60
 )
 
 type AccountCache struct {
-	Accounts map[string]string `json:"accounts"` // accountId -> accountName
+	Version   int                 `json:"version"`
+	FetchedAt time.Time           `json:"fetched_at,omitempty"`
+	Accounts  map[string]string   `json:"accounts"`        // accountId -> accountName
+	Roles     map[string][]string `json:"roles,omitempty"` // accountId -> role names
 }
 
+// accountCacheMu serializes read-modify-write updates of accounts.json within awsc,
+// which refreshes accounts and roles concurrently in the background
+var accountCacheMu sync.Mutex
+
 func GetAccountCachePath() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".awsc", "accounts.json")
 }
 
-// SaveAccountCache saves account ID to name mappings
-func SaveAccountCache(accounts []types.AccountInfo) error {
-	cache := AccountCache{
-		Accounts: make(map[string]string),
+// GetAccountCacheTTL returns how long the account cache can be shown without refreshing it
+// first. Zero disables the cache.
+func GetAccountCacheTTL() time.Duration {
+	minutes := defaultAccountCacheTTLMinutes
+	if viper.IsSet("account_cache_ttl_minutes") {
+		minutes = viper.GetInt("account_cache_ttl_minutes")
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// LoadAccountCache reads accounts.json, upgrading older formats
+func LoadAccountCache() (*AccountCache, error) {
+	data, err := os.ReadFile(GetAccountCachePath())
+	if err != nil {
+		return nil, err
 	}
 
-	for _, account := range accounts {
-		if account.AccountId != nil && account.AccountName != nil {
-			cache.Accounts[*account.AccountId] = *account.AccountName
+	var cache AccountCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	if cache.Version > accountCacheVersion {
+		return nil, fmt.Errorf("account cache version %d is newer than this awsc supports", cache.Version)
+	}
+
+	// Version 1 caches have no roles or fetch time, so they are never fresh
+	cache.Version = accountCacheVersion
+	if cache.Accounts == nil {
+		cache.Accounts = make(map[string]string)
+	}
+	if cache.Roles == nil {
+		cache.Roles = make(map[string][]string)
+	}
+	return &cache, nil
+}
+
+// IsFresh reports whether the cache was fetched within ttl
+func (c *AccountCache) IsFresh(ttl time.Duration) bool {
+	return !c.FetchedAt.IsZero() && time.Since(c.FetchedAt) < ttl
+}
+
+// AccountInfos returns the cached accounts in the form returned by SSO ListAccounts
+func (c *AccountCache) AccountInfos() []types.AccountInfo {
+	accounts := make([]types.AccountInfo, 0, len(c.Accounts))
+	for id, name := range c.Accounts {
+		accounts = append(accounts, types.AccountInfo{AccountId: &id, AccountName: &name})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return *accounts[i].AccountName < *accounts[j].AccountName
+	})
+	return accounts
+}
+
+// SaveAccountCache saves account ID to name mappings, keeping the cached roles of accounts
+// that still exist
+func SaveAccountCache(accounts []types.AccountInfo) error {
+	return updateAccountCache(func(cache *AccountCache) {
+		previous := cache.Roles
+		cache.Accounts = make(map[string]string)
+		cache.Roles = make(map[string][]string)
+
+		for _, account := range accounts {
+			if account.AccountId != nil && account.AccountName != nil {
+				cache.Accounts[*account.AccountId] = *account.AccountName
+				if roles, ok := previous[*account.AccountId]; ok {
+					cache.Roles[*account.AccountId] = roles
+				}
+			}
 		}
+		cache.FetchedAt = time.Now().UTC()
+	})
+}
+
+// SaveAccountRoles caches the role names of an account
+func SaveAccountRoles(accountID string, roleNames []string) error {
+	return updateAccountCache(func(cache *AccountCache) {
+		sorted := append([]string(nil), roleNames...)
+		sort.Strings(sorted)
+		cache.Roles[accountID] = sorted
+	})
+}
+
+func updateAccountCache(update func(cache *AccountCache)) error {
+	accountCacheMu.Lock()
+	defer accountCacheMu.Unlock()
+
+	cache, err := LoadAccountCache()
+	if err != nil {
+		// Start over from a missing, corrupt or unsupported cache
+		cache = &AccountCache{Accounts: make(map[string]string), Roles: make(map[string][]string)}
 	}
+	cache.Version = accountCacheVersion
+	update(cache)
 
 	// Create .awsc directory if it doesn't exist with secure permissions
 	cacheDir := filepath.Dir(GetAccountCachePath())
@@ -35,30 +140,20 @@ func SaveAccountCache(accounts []types.AccountInfo) error {
 		return err
 	}
 
-	// Write cache file
 	data, err := json.Marshal(cache)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(GetAccountCachePath(), data, 0600)
+	// Atomic so concurrent awsc processes never read a partial cache
+	return writeFileAtomic(GetAccountCachePath(), string(data))
 }
 
 // GetAccountName returns account name for given account ID, or the ID if not found
 func GetAccountName(accountId string) string {
-	cachePath := GetAccountCachePath()
-	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-		return accountId // Cache doesn't exist, return ID
-	}
-
-	data, err := os.ReadFile(cachePath)
+	cache, err := LoadAccountCache()
 	if err != nil {
-		return accountId // Error reading cache, return ID
-	}
-
-	var cache AccountCache
-	if err := json.Unmarshal(data, &cache); err != nil {
-		return accountId // Error parsing cache, return ID
+		return accountId // Cache missing or unreadable, return ID
 	}
 
 	if name, exists := cache.Accounts[accountId]; exists {
@@ -67,3 +162,20 @@ func GetAccountName(accountId string) string {
 
 	return accountId // Account not found in cache, return ID
 }
+
+// GetAccountID returns the account ID for the given account name (case-insensitive),
+// or the input unchanged if not found in the cache
+func GetAccountID(accountName string) string {
+	cache, err := LoadAccountCache()
+	if err != nil {
+		return accountName // Cache missing or unreadable, return input
+	}
+
+	for id, name := range cache.Accounts {
+		if strings.EqualFold(name, accountName) {
+			return id
+		}
+	}
+
+	return accountName // Account not found in cache, return input
+}
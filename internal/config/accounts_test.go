@@ -3,9 +3,13 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/spf13/viper"
 )
 
 func TestSaveAndGetAccountCache(t *testing.T) {
@@ -96,3 +100,132 @@ func TestGetAccountCachePath(t *testing.T) {
 		t.Errorf("Expected path to end with accounts.json, got %s", path)
 	}
 }
+
+func TestGetAccountID(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tempDir)
+
+	accountId := "123456789012"
+	accountName := "Production"
+	if err := SaveAccountCache([]types.AccountInfo{{AccountId: &accountId, AccountName: &accountName}}); err != nil {
+		t.Fatalf("SaveAccountCache failed: %v", err)
+	}
+
+	if id := GetAccountID("production"); id != accountId {
+		t.Errorf("Expected %s, got %s", accountId, id)
+	}
+	if id := GetAccountID("210987654321"); id != "210987654321" {
+		t.Errorf("Expected unknown input to be returned unchanged, got %s", id)
+	}
+}
+
+func TestLoadAccountCache_Version1(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Caches written before roles were cached have no version
+	if err := os.MkdirAll(filepath.Dir(GetAccountCachePath()), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetAccountCachePath(), []byte(`{"accounts":{"123456789012":"Production"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err := LoadAccountCache()
+	if err != nil {
+		t.Fatalf("LoadAccountCache failed: %v", err)
+	}
+	if cache.Version != accountCacheVersion {
+		t.Errorf("Expected version %d, got %d", accountCacheVersion, cache.Version)
+	}
+	if cache.Accounts["123456789012"] != "Production" {
+		t.Errorf("Expected account names to be kept, got %v", cache.Accounts)
+	}
+	if cache.IsFresh(time.Hour) {
+		t.Error("Version 1 caches have no fetch time and should never be fresh")
+	}
+	if GetAccountName("123456789012") != "Production" {
+		t.Error("GetAccountName should read version 1 caches")
+	}
+}
+
+func TestLoadAccountCache_NewerVersion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := os.MkdirAll(filepath.Dir(GetAccountCachePath()), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetAccountCachePath(), []byte(`{"version":99,"accounts":{}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadAccountCache(); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected error for a newer cache version, got %v", err)
+	}
+
+	// Saving replaces the unsupported cache
+	if err := SaveAccountCache([]types.AccountInfo{{AccountId: aws.String("123456789012"), AccountName: aws.String("Production")}}); err != nil {
+		t.Fatalf("SaveAccountCache failed: %v", err)
+	}
+	if _, err := LoadAccountCache(); err != nil {
+		t.Errorf("LoadAccountCache after save failed: %v", err)
+	}
+}
+
+func TestSaveAccountRoles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	accounts := []types.AccountInfo{
+		{AccountId: aws.String("111111111111"), AccountName: aws.String("Production")},
+		{AccountId: aws.String("222222222222"), AccountName: aws.String("Development")},
+	}
+	if err := SaveAccountCache(accounts); err != nil {
+		t.Fatalf("SaveAccountCache failed: %v", err)
+	}
+	if err := SaveAccountRoles("111111111111", []string{"ReadOnly", "Admin"}); err != nil {
+		t.Fatalf("SaveAccountRoles failed: %v", err)
+	}
+	if err := SaveAccountRoles("222222222222", []string{"Developer"}); err != nil {
+		t.Fatalf("SaveAccountRoles failed: %v", err)
+	}
+
+	// Refreshing accounts keeps the roles of accounts that still exist
+	if err := SaveAccountCache(accounts[:1]); err != nil {
+		t.Fatalf("SaveAccountCache failed: %v", err)
+	}
+
+	cache, err := LoadAccountCache()
+	if err != nil {
+		t.Fatalf("LoadAccountCache failed: %v", err)
+	}
+	if got := strings.Join(cache.Roles["111111111111"], ","); got != "Admin,ReadOnly" {
+		t.Errorf("Expected sorted roles Admin,ReadOnly, got %s", got)
+	}
+	if _, ok := cache.Roles["222222222222"]; ok {
+		t.Error("Roles of removed accounts should be dropped")
+	}
+	if !cache.IsFresh(time.Minute) {
+		t.Errorf("Expected cache fetched at %v to be fresh", cache.FetchedAt)
+	}
+
+	infos := cache.AccountInfos()
+	if len(infos) != 1 || *infos[0].AccountId != "111111111111" || *infos[0].AccountName != "Production" {
+		t.Errorf("Unexpected account infos: %+v", infos)
+	}
+}
+
+func TestGetAccountCacheTTL(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	if ttl := GetAccountCacheTTL(); ttl != 24*time.Hour {
+		t.Errorf("Expected default TTL 24h, got %v", ttl)
+	}
+
+	viper.Set("account_cache_ttl_minutes", 0)
+	if ttl := GetAccountCacheTTL(); ttl != 0 {
+		t.Errorf("Expected TTL 0 to disable the cache, got %v", ttl)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultChainSessionName is the AssumeRole session name used when a chain doesn't set one
+const defaultChainSessionName = "awsc"
+
+// ChainConfig describes a role assumed from an SSO role, configured under `chains.<name>`
+type ChainConfig struct {
+	From        string `mapstructure:"from"` // "account/role" of the SSO role to start from
+	RoleARN     string `mapstructure:"role_arn"`
+	ExternalID  string `mapstructure:"external_id"`
+	SessionName string `mapstructure:"session_name"`
+}
+
+// GetChain returns the named role chain from the config
+func GetChain(name string) (*ChainConfig, error) {
+	key := "chains." + name
+	if !viper.IsSet(key) {
+		available := ListChains()
+		if len(available) == 0 {
+			return nil, fmt.Errorf("chain '%s' not found: no chains configured", name)
+		}
+		return nil, fmt.Errorf("chain '%s' not found. Available chains: %s", name, strings.Join(available, ", "))
+	}
+
+	var chain ChainConfig
+	if err := viper.UnmarshalKey(key, &chain); err != nil {
+		return nil, fmt.Errorf("invalid chain '%s': %w", name, err)
+	}
+
+	if _, _, err := chain.Source(); err != nil {
+		return nil, fmt.Errorf("invalid chain '%s': %w", name, err)
+	}
+	if chain.RoleARN == "" {
+		return nil, fmt.Errorf("invalid chain '%s': role_arn is required", name)
+	}
+	if chain.SessionName == "" {
+		chain.SessionName = defaultChainSessionName
+	}
+
+	return &chain, nil
+}
+
+// ListChains returns the configured chain names in sorted order
+func ListChains() []string {
+	chains := viper.GetStringMap("chains")
+	names := make([]string, 0, len(chains))
+	for name := range chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Source returns the account and role of the SSO role the chain starts from
+func (c *ChainConfig) Source() (string, string, error) {
+	i := strings.LastIndex(c.From, "/")
+	if i <= 0 || i == len(c.From)-1 {
+		return "", "", fmt.Errorf("from must be in the form account/role, got '%s'", c.From)
+	}
+	return c.From[:i], c.From[i+1:], nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGetChain(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("chains", map[string]interface{}{
+		"prod-db-admin": map[string]interface{}{
+			"from":        "production/DataAccess",
+			"role_arn":    "arn:aws:iam::999999999999:role/db-admin",
+			"external_id": "secret-id",
+		},
+		"bad-from": map[string]interface{}{
+			"from":     "production",
+			"role_arn": "arn:aws:iam::999999999999:role/db-admin",
+		},
+		"no-arn": map[string]interface{}{
+			"from": "production/DataAccess",
+		},
+	})
+
+	tests := []struct {
+		name        string
+		chain       string
+		expectError bool
+	}{
+		{"valid chain", "prod-db-admin", false},
+		{"missing chain", "unknown", true},
+		{"invalid from", "bad-from", true},
+		{"missing role_arn", "no-arn", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := GetChain(tt.chain)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			account, role, _ := chain.Source()
+			if account != "production" || role != "DataAccess" {
+				t.Errorf("Expected production/DataAccess, got %s/%s", account, role)
+			}
+			if chain.ExternalID != "secret-id" {
+				t.Errorf("Expected external ID secret-id, got %s", chain.ExternalID)
+			}
+			if chain.SessionName != defaultChainSessionName {
+				t.Errorf("Expected default session name, got %s", chain.SessionName)
+			}
+		})
+	}
+
+	if chains := ListChains(); len(chains) != 3 || chains[0] != "bad-from" {
+		t.Errorf("Expected 3 sorted chains, got %v", chains)
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultFederationEndpoint is the AWS sign-in federation endpoint for the aws partition
+	DefaultFederationEndpoint = "https://signin.aws.amazon.com/federation"
+	// DefaultConsoleURL is the AWS Management Console for the aws partition
+	DefaultConsoleURL = "https://console.aws.amazon.com"
+)
+
+// GetFederationEndpoint returns the sign-in federation endpoint used by awsc console for the
+// partition of region
+func GetFederationEndpoint(region string) (string, error) {
+	if endpoint := viper.GetString("console.federation_endpoint"); endpoint != "" {
+		return endpoint, nil
+	}
+	partition, err := consolePartition(region)
+	if err != nil {
+		return "", err
+	}
+	return partition.FederationEndpoint, nil
+}
+
+// GetConsoleURL returns the console base URL that sign-in redirects to for the partition of
+// region
+func GetConsoleURL(region string) (string, error) {
+	if consoleURL := viper.GetString("console.url"); consoleURL != "" {
+		return consoleURL, nil
+	}
+	partition, err := consolePartition(region)
+	if err != nil {
+		return "", err
+	}
+	return partition.ConsoleURL, nil
+}
+
+// consolePartition returns the partition of region, which must have a known console. An
+// empty region is the aws partition.
+func consolePartition(region string) (Partition, error) {
+	if region == "" {
+		return partitions[0], nil
+	}
+	partition, err := PartitionForRegion(region)
+	if err != nil {
+		return Partition{}, err
+	}
+	if partition.ConsoleURL == "" || partition.FederationEndpoint == "" {
+		return Partition{}, fmt.Errorf("console sign-in isn't supported in the %s partition - set console.url and console.federation_endpoint", partition.ID)
+	}
+	return partition, nil
+}
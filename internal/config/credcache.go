@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// credentialExpiryBuffer is how long before expiration cached credentials are considered stale
+const credentialExpiryBuffer = 5 * time.Minute
+
+// CachedCredentials holds role credentials served to credential_process profiles
+type CachedCredentials struct {
+	AccessKeyID     string    `json:"access_key_id"`
+	SecretAccessKey string    `json:"secret_access_key"`
+	SessionToken    string    `json:"session_token"`
+	Expiration      time.Time `json:"expiration"`
+}
+
+func getCredentialCachePath(accountID, roleName string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".awsc", "cache", "credentials", fmt.Sprintf("%s-%s.json", accountID, roleName))
+}
+
+func getChainCredentialCachePath(chainName string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".awsc", "cache", "credentials", fmt.Sprintf("chain-%s.json", chainName))
+}
+
+// LoadCachedCredentials returns cached credentials for the account and role if they are
+// not about to expire
+func LoadCachedCredentials(accountID, roleName string) (*CachedCredentials, error) {
+	return loadCachedCredentials(getCredentialCachePath(accountID, roleName))
+}
+
+// LoadCachedChainCredentials returns the cached credentials of a role chain if they are
+// not about to expire
+func LoadCachedChainCredentials(chainName string) (*CachedCredentials, error) {
+	return loadCachedCredentials(getChainCredentialCachePath(chainName))
+}
+
+func loadCachedCredentials(cachePath string) (*CachedCredentials, error) {
+	store, err := GetCredentialStore()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := store.Load(cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds CachedCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+
+	if time.Now().Add(credentialExpiryBuffer).After(creds.Expiration) {
+		return nil, fmt.Errorf("cached credentials expired")
+	}
+
+	return &creds, nil
+}
+
+// SaveCachedCredentials caches credentials for the account and role in the credential store
+func SaveCachedCredentials(accountID, roleName string, creds *CachedCredentials) error {
+	return saveCachedCredentials(getCredentialCachePath(accountID, roleName), creds)
+}
+
+// SaveCachedChainCredentials caches the credentials of a role chain in the credential store
+func SaveCachedChainCredentials(chainName string, creds *CachedCredentials) error {
+	return saveCachedCredentials(getChainCredentialCachePath(chainName), creds)
+}
+
+func saveCachedCredentials(cachePath string, creds *CachedCredentials) error {
+	store, err := GetCredentialStore()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+
+	return store.Save(cachePath, data)
+}
+
+// DeleteCachedCredentials removes cached credentials for the account and role
+func DeleteCachedCredentials(accountID, roleName string) error {
+	return RemoveStoredFile(getCredentialCachePath(accountID, roleName))
+}
+
+// DeleteCachedChainCredentials removes the cached credentials of a role chain
+func DeleteCachedChainCredentials(chainName string) error {
+	return RemoveStoredFile(getChainCredentialCachePath(chainName))
+}
+
+// ClearCredentialCache removes all cached credentials
+func ClearCredentialCache() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(home, ".awsc", "cache", "credentials"))
+}
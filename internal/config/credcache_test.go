@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoadCachedCredentials(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tempDir)
+
+	// Nothing cached yet
+	if _, err := LoadCachedCredentials("123456789012", "TestRole"); err == nil {
+		t.Error("Expected error when no credentials are cached")
+	}
+
+	creds := &CachedCredentials{
+		AccessKeyID:     "AKIATEST",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(1 * time.Hour),
+	}
+	if err := SaveCachedCredentials("123456789012", "TestRole", creds); err != nil {
+		t.Fatalf("SaveCachedCredentials failed: %v", err)
+	}
+
+	cachePath := filepath.Join(tempDir, ".awsc", "cache", "credentials", "123456789012-TestRole.json")
+	info, err := os.Stat(cachePath)
+	if err != nil {
+		t.Fatalf("Cache file was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected file permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	loaded, err := LoadCachedCredentials("123456789012", "TestRole")
+	if err != nil {
+		t.Fatalf("LoadCachedCredentials failed: %v", err)
+	}
+	if loaded.AccessKeyID != "AKIATEST" {
+		t.Errorf("Expected AKIATEST, got %s", loaded.AccessKeyID)
+	}
+
+	// Credentials close to expiry are not served
+	creds.Expiration = time.Now().Add(1 * time.Minute)
+	if err := SaveCachedCredentials("123456789012", "TestRole", creds); err != nil {
+		t.Fatalf("SaveCachedCredentials failed: %v", err)
+	}
+	if _, err := LoadCachedCredentials("123456789012", "TestRole"); err == nil {
+		t.Error("Expected error for credentials about to expire")
+	}
+}
+
+func TestWriteCredentialProcessProfile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tempDir)
+
+	profileName, err := WriteCredentialProcessProfile("test-account", "123456789012", "TestRole")
+	if err != nil {
+		t.Fatalf("WriteCredentialProcessProfile failed: %v", err)
+	}
+	if profileName != "awsc-test-account" {
+		t.Errorf("Expected profile name awsc-test-account, got %s", profileName)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, ".aws", "config"))
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if !contains(string(content), "credential-process --account 123456789012 --role TestRole") {
+		t.Errorf("Config file does not contain credential_process command:\n%s", content)
+	}
+	if contains(string(content), "aws_access_key_id") {
+		t.Error("credential_process profile should not contain static credentials")
+	}
+}
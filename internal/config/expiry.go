@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultExpiryWarningMinutes is how close to expiry credentials must be before awsc
+// warns ahead of starting an SSM session
+const defaultExpiryWarningMinutes = 10
+
+// ProfileMetadata is the information awsc records in the comment header of its profiles
+type ProfileMetadata struct {
+	AccountName string
+	AccountID   string
+	RoleName    string
+	Chain       string    // Set for profiles written by `awsc login --chain`
+	Expiration  time.Time // Zero for profiles that refresh their own credentials
+}
+
+// Expired reports whether the profile's recorded credentials have passed their expiry
+func (m *ProfileMetadata) Expired() bool {
+	return !m.Expiration.IsZero() && time.Now().After(m.Expiration)
+}
+
+// GetExpiryWarningMargin returns the configured expiry warning margin
+func GetExpiryWarningMargin() time.Duration {
+	minutes := defaultExpiryWarningMinutes
+	if viper.IsSet("expiry_warning_minutes") {
+		minutes = viper.GetInt("expiry_warning_minutes")
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetProfileMetadata reads the awsc comment header of a profile in ~/.aws/config
+func GetProfileMetadata(profileName string) (*ProfileMetadata, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".aws", "config"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return parseProfileMetadata(string(data), profileName)
+}
+
+func parseProfileMetadata(content, profileName string) (*ProfileMetadata, error) {
+	section := parseINI(content).section("profile " + profileName)
+	if section == nil {
+		return nil, fmt.Errorf("profile %s not found", profileName)
+	}
+
+	metadata := &ProfileMetadata{}
+	for _, comment := range section.comments() {
+		switch {
+		case strings.HasPrefix(comment, "# Account: "):
+			// Format: "# Account: name (id)"
+			value := strings.TrimPrefix(comment, "# Account: ")
+			if i := strings.LastIndex(value, " ("); i > 0 && strings.HasSuffix(value, ")") {
+				metadata.AccountName = value[:i]
+				metadata.AccountID = value[i+2 : len(value)-1]
+			} else {
+				metadata.AccountName = value
+			}
+		case strings.HasPrefix(comment, "# Chain: "):
+			metadata.Chain = strings.TrimPrefix(comment, "# Chain: ")
+		case strings.HasPrefix(comment, "# Role: "):
+			metadata.RoleName = strings.TrimPrefix(comment, "# Role: ")
+		case strings.HasPrefix(comment, "# Expires: "):
+			if expiration, err := time.Parse(time.RFC3339, strings.TrimPrefix(comment, "# Expires: ")); err == nil {
+				metadata.Expiration = expiration
+			}
+		}
+	}
+
+	return metadata, nil
+}
+
+// GetCredentialExpiration returns when the active profile's credentials expire, or zero
+// if unknown or the profile refreshes its own credentials
+func GetCredentialExpiration() time.Time {
+	profileName, source, err := ResolveProfile()
+	if err != nil {
+		return time.Time{}
+	}
+
+	if source == SessionSourcePPID {
+		if session, err := GetCurrentSession(); err == nil && !session.Expiration.IsZero() {
+			return session.Expiration
+		}
+	}
+
+	metadata, err := GetProfileMetadata(profileName)
+	if err != nil {
+		return time.Time{}
+	}
+	return metadata.Expiration
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/spf13/viper"
+)
+
+func TestParseProfileMetadata(t *testing.T) {
+	content := `[profile awsc-prod]
+# Account: prod (account) (123456789012)
+# Role: Admin
+# Expires: 2030-01-02T03:04:05Z
+aws_access_key_id = AKIA
+
+[profile awsc-dev]
+# Account: dev (222222222222)
+# Role: ReadOnly
+sso_session = awsc
+`
+
+	tests := []struct {
+		name        string
+		profile     string
+		expected    ProfileMetadata
+		expectError bool
+	}{
+		{
+			name:    "static profile with expiration",
+			profile: "awsc-prod",
+			expected: ProfileMetadata{
+				AccountName: "prod (account)",
+				AccountID:   "123456789012",
+				RoleName:    "Admin",
+				Expiration:  time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+		{
+			name:     "profile without expiration",
+			profile:  "awsc-dev",
+			expected: ProfileMetadata{AccountName: "dev", AccountID: "222222222222", RoleName: "ReadOnly"},
+		},
+		{
+			name:        "missing profile",
+			profile:     "awsc-missing",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := parseProfileMetadata(content, tt.profile)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if metadata.AccountName != tt.expected.AccountName || metadata.AccountID != tt.expected.AccountID ||
+				metadata.RoleName != tt.expected.RoleName || !metadata.Expiration.Equal(tt.expected.Expiration) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, *metadata)
+			}
+		})
+	}
+}
+
+func TestGetCredentialExpiration(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_PROFILE", "")
+
+	expiration := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+
+	// No session
+	if !GetCredentialExpiration().IsZero() {
+		t.Error("Expected zero expiration without a session")
+	}
+
+	// Static profile records its expiration in the profile metadata
+	if _, err := WriteProfile("prod", "123456789012", "Admin", &types.RoleCredentials{
+		AccessKeyId:     aws.String("AKIA"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+		Expiration:      expiration.UnixMilli(),
+	}); err != nil {
+		t.Fatalf("WriteProfile failed: %v", err)
+	}
+
+	t.Setenv("AWSC_PROFILE", "awsc-prod")
+	if got := GetCredentialExpiration(); !got.Equal(expiration) {
+		t.Errorf("Expected profile expiration %v, got %v", expiration, got)
+	}
+
+	// PPID session expiration takes priority
+	t.Setenv("AWSC_PROFILE", "")
+	sessionExpiration := expiration.Add(time.Hour)
+	if err := SaveSession(currentSessionKey(t), "awsc-prod", "123456789012", "prod", "Admin", sessionExpiration); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if got := GetCredentialExpiration(); !got.Equal(sessionExpiration) {
+		t.Errorf("Expected session expiration %v, got %v", sessionExpiration, got)
+	}
+
+	// Session file stores the expiration
+	data, _ := os.ReadFile(filepath.Join(tempDir, ".awsc", "sessions", sessionFileName(currentSessionKey(t))))
+	if !contains(string(data), `"expiration"`) {
+		t.Errorf("Session file should contain expiration: %s", string(data))
+	}
+}
+
+func TestGetExpiryWarningMargin(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	if margin := GetExpiryWarningMargin(); margin != 10*time.Minute {
+		t.Errorf("Expected default margin 10m, got %v", margin)
+	}
+
+	viper.Set("expiry_warning_minutes", 30)
+	if margin := GetExpiryWarningMargin(); margin != 30*time.Minute {
+		t.Errorf("Expected margin 30m, got %v", margin)
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"strings"
+)
+
+// iniFile is an AWS shared config file split into sections. It keeps every line as written,
+// including comments and blank lines, so sections awsc doesn't touch are written back
+// unchanged and in their original order.
+type iniFile struct {
+	preamble []string // Lines before the first section
+	sections []*iniSection
+}
+
+// iniSection is one section of an iniFile: the comment lines directly above its header,
+// the header line, and every line up to the next section
+type iniSection struct {
+	name    string // Normalized header name, e.g. "default", "profile x", "sso-session y"
+	leading []string
+	lines   []string // Header line first
+}
+
+// parseINI splits config file content into sections. It never fails: lines it doesn't
+// understand stay in the section they appear in.
+func parseINI(content string) *iniFile {
+	file := &iniFile{}
+	if content == "" {
+		return file
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	current := &file.preamble
+
+	for _, line := range lines {
+		name, ok := parseSectionHeader(line)
+		if !ok {
+			*current = append(*current, line)
+			continue
+		}
+
+		// Comments directly above a header describe the section that follows
+		leadingStart := len(*current)
+		for leadingStart > 0 && isINIComment((*current)[leadingStart-1]) {
+			leadingStart--
+		}
+		leading := append([]string(nil), (*current)[leadingStart:]...)
+		*current = (*current)[:leadingStart]
+
+		section := &iniSection{name: name, leading: leading, lines: []string{line}}
+		file.sections = append(file.sections, section)
+		current = &section.lines
+	}
+
+	return file
+}
+
+// parseSectionHeader returns the normalized name of a "[...]" header line
+func parseSectionHeader(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "[") || !strings.HasSuffix(trimmed, "]") {
+		return "", false
+	}
+	// The AWS CLI accepts any whitespace between the section type and name
+	return strings.Join(strings.Fields(trimmed[1:len(trimmed)-1]), " "), true
+}
+
+func isINIComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";")
+}
+
+// String renders the file, ending with a newline unless it is empty
+func (f *iniFile) String() string {
+	lines := append([]string(nil), f.preamble...)
+	for _, section := range f.sections {
+		lines = append(lines, section.leading...)
+		lines = append(lines, section.lines...)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// section returns the section with the given header name, or nil
+func (f *iniFile) section(name string) *iniSection {
+	for _, section := range f.sections {
+		if section.name == name {
+			return section
+		}
+	}
+	return nil
+}
+
+// setSection replaces the section's header and body with text (a full section starting
+// with its header line) in place, keeping the comments above it, or appends it if missing
+func (f *iniFile) setSection(name, text string) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	if existing := f.section(name); existing != nil {
+		existing.lines = lines
+		return
+	}
+
+	// Keep a blank line between the previous section and the new one
+	if last := f.lastLines(); len(last) > 0 && strings.TrimSpace(last[len(last)-1]) != "" {
+		f.appendToLast("")
+	}
+	f.sections = append(f.sections, &iniSection{name: name, lines: lines})
+}
+
+// removeSection removes the section with the given header name, including the comments
+// directly above it
+func (f *iniFile) removeSection(name string) {
+	sections := f.sections[:0]
+	for _, section := range f.sections {
+		if section.name != name {
+			sections = append(sections, section)
+		}
+	}
+	f.sections = sections
+}
+
+// hasValue reports whether any section sets key to value
+func (f *iniFile) hasValue(key, value string) bool {
+	for _, section := range f.sections {
+		for _, line := range section.lines[1:] {
+			k, v, ok := strings.Cut(line, "=")
+			if ok && strings.TrimSpace(k) == key && strings.TrimSpace(v) == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sectionNames returns the header names of all sections with the given prefix, in file order
+func (f *iniFile) sectionNames(prefix string) []string {
+	var names []string
+	for _, section := range f.sections {
+		if strings.HasPrefix(section.name, prefix) {
+			names = append(names, section.name)
+		}
+	}
+	return names
+}
+
+func (f *iniFile) lastLines() []string {
+	if len(f.sections) == 0 {
+		return f.preamble
+	}
+	return f.sections[len(f.sections)-1].lines
+}
+
+func (f *iniFile) appendToLast(line string) {
+	if len(f.sections) == 0 {
+		f.preamble = append(f.preamble, line)
+		return
+	}
+	last := f.sections[len(f.sections)-1]
+	last.lines = append(last.lines, line)
+}
+
+// comments returns the trimmed comment lines inside the section body
+func (s *iniSection) comments() []string {
+	var comments []string
+	for _, line := range s.lines[1:] {
+		if isINIComment(line) {
+			comments = append(comments, strings.TrimSpace(line))
+		}
+	}
+	return comments
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleConfig = `# Managed partly by hand
+
+[default]
+region = eu-west-1
+
+# Work account
+[profile  work ]
+region = us-east-1
+; keep this
+
+[profile awsc-dev]
+# Account: dev (111111111111)
+aws_access_key_id = KEY1
+
+[sso-session awsc]
+sso_start_url = https://test.awsapps.com/start
+
+[services local]
+s3 =
+  endpoint_url = http://localhost:4566
+`
+
+func TestParseINI_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"empty", ""},
+		{"sample", sampleConfig},
+		{"only comments", "# nothing here\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := parseINI(tt.content).String(); result != tt.content {
+				t.Errorf("Round trip changed content:\n%q\nwant:\n%q", result, tt.content)
+			}
+		})
+	}
+}
+
+func TestParseINI_Sections(t *testing.T) {
+	file := parseINI(sampleConfig)
+
+	expected := []string{"default", "profile work", "profile awsc-dev", "sso-session awsc", "services local"}
+	if len(file.sections) != len(expected) {
+		t.Fatalf("Expected %d sections, got %d", len(expected), len(file.sections))
+	}
+	for i, name := range expected {
+		if file.sections[i].name != name {
+			t.Errorf("Section %d: expected %q, got %q", i, name, file.sections[i].name)
+		}
+	}
+
+	if work := file.section("profile work"); len(work.leading) != 1 || work.leading[0] != "# Work account" {
+		t.Errorf("Expected comment above [profile work] to belong to it, got %v", work.leading)
+	}
+	if comments := file.section("profile awsc-dev").comments(); len(comments) != 1 || comments[0] != "# Account: dev (111111111111)" {
+		t.Errorf("Unexpected comments: %v", comments)
+	}
+}
+
+func TestINIFile_SetSection(t *testing.T) {
+	file := parseINI(sampleConfig)
+
+	file.setSection("profile work", "[profile work]\nregion = ap-southeast-2\n\n")
+	file.setSection("profile awsc-new", "[profile awsc-new]\naws_access_key_id = KEY2\n\n")
+	result := file.String()
+
+	if !strings.Contains(result, "# Work account\n[profile work]\nregion = ap-southeast-2\n") {
+		t.Errorf("Expected [profile work] replaced in place with its comment kept:\n%s", result)
+	}
+	if strings.Contains(result, "; keep this") {
+		t.Error("Old body of replaced section still present")
+	}
+	if strings.Index(result, "[profile work]") > strings.Index(result, "[profile awsc-dev]") {
+		t.Error("Replaced section moved")
+	}
+	if !strings.HasSuffix(result, "endpoint_url = http://localhost:4566\n\n[profile awsc-new]\naws_access_key_id = KEY2\n\n") {
+		t.Errorf("Expected new section appended after a blank line:\n%s", result)
+	}
+}
+
+func TestINIFile_RemoveSection(t *testing.T) {
+	tests := []struct {
+		name      string
+		remove    string
+		gone      []string
+		remaining []string
+	}{
+		{
+			name:      "profile before sso-session",
+			remove:    "profile awsc-dev",
+			gone:      []string{"KEY1", "# Account: dev"},
+			remaining: []string{"[sso-session awsc]", "sso_start_url", "[services local]", "[default]"},
+		},
+		{
+			name:      "sso-session in the middle",
+			remove:    "sso-session awsc",
+			gone:      []string{"sso_start_url"},
+			remaining: []string{"KEY1", "[services local]", "endpoint_url"},
+		},
+		{
+			name:      "default section",
+			remove:    "default",
+			gone:      []string{"region = eu-west-1"},
+			remaining: []string{"# Managed partly by hand", "[profile  work ]"},
+		},
+		{
+			name:      "header with extra whitespace",
+			remove:    "profile work",
+			gone:      []string{"# Work account", "; keep this"},
+			remaining: []string{"[default]", "KEY1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := parseINI(sampleConfig)
+			file.removeSection(tt.remove)
+			result := file.String()
+
+			for _, s := range tt.gone {
+				if strings.Contains(result, s) {
+					t.Errorf("Expected %q to be removed", s)
+				}
+			}
+			for _, s := range tt.remaining {
+				if !strings.Contains(result, s) {
+					t.Errorf("Expected %q to remain", s)
+				}
+			}
+		})
+	}
+}
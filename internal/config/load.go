@@ -2,7 +2,7 @@ package config
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"os"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -31,28 +31,64 @@ func LoadAWSConfig(ctx context.Context) (aws.Config, error) {
 	return config.LoadDefaultConfig(ctx, options...)
 }
 
-// LoadAWSConfigWithProfile loads AWS config using hybrid approach:
+// LoadSSOConfig loads AWS config for the IAM Identity Center (SSO and SSO OIDC) clients.
+// They must use the Identity Center region, whose partition decides the endpoints, whatever
+// region the resource commands use.
+func LoadSSOConfig(ctx context.Context) (aws.Config, error) {
+	region := viper.GetString("sso.region")
+	if region == "" {
+		region = viper.GetString("default_region")
+	}
+
+	// Explicitly use empty profile to ignore AWS_PROFILE environment variable
+	options := []func(*config.LoadOptions) error{
+		config.WithSharedConfigProfile(""),
+	}
+
+	if region != "" {
+		options = append(options, config.WithRegion(region))
+	}
+
+	return config.LoadDefaultConfig(ctx, options...)
+}
+
+// ErrNoActiveSession is returned when this terminal has no awsc profile selected
+var ErrNoActiveSession = errors.New("no active session")
+
+const (
+	// SessionSourceEnv means the profile came from the AWSC_PROFILE environment variable
+	SessionSourceEnv = "AWSC_PROFILE"
+	// SessionSourcePPID means the profile came from the per-terminal session file
+	SessionSourcePPID = "ppid"
+)
+
+// ResolveProfile returns the awsc profile for this terminal and where it came from:
 // 1. AWSC_PROFILE environment variable (explicit override)
 // 2. PPID session tracking (automatic per-terminal)
 // 3. Error if neither exists
+func ResolveProfile() (string, string, error) {
+	// Priority 1: Check AWSC_PROFILE environment variable
+	if envProfile := os.Getenv("AWSC_PROFILE"); envProfile != "" {
+		return envProfile, SessionSourceEnv, nil
+	}
+
+	// Priority 2: Check PPID session
+	session, err := GetCurrentSession()
+	if err != nil {
+		// No session found
+		return "", "", ErrNoActiveSession
+	}
+	return session.ProfileName, SessionSourcePPID, nil
+}
+
+// LoadAWSConfigWithProfile loads AWS config using the profile from ResolveProfile
 func LoadAWSConfigWithProfile(ctx context.Context) (aws.Config, error) {
 	// Use region override if provided, otherwise use default region from config
 	region := viper.GetString("default_region")
 
-	var profileName string
-
-	// Priority 1: Check AWSC_PROFILE environment variable
-	envProfile := os.Getenv("AWSC_PROFILE")
-	if envProfile != "" {
-		profileName = envProfile
-	} else {
-		// Priority 2: Check PPID session
-		session, err := GetCurrentSession()
-		if err != nil {
-			// No session found
-			return aws.Config{}, fmt.Errorf("no active session")
-		}
-		profileName = session.ProfileName
+	profileName, _, err := ResolveProfile()
+	if err != nil {
+		return aws.Config{}, err
 	}
 
 	// Load config with the determined profile
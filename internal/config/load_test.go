@@ -2,10 +2,10 @@ package config
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -49,6 +49,32 @@ func TestLoadAWSConfigFallback(t *testing.T) {
 	viper.Reset()
 }
 
+func TestLoadSSOConfig(t *testing.T) {
+	defer viper.Reset()
+
+	// The Identity Center region wins over the resource region
+	viper.Set("default_region", "us-west-2")
+	viper.Set("sso.region", "us-gov-west-1")
+
+	cfg, err := LoadSSOConfig(context.Background())
+	if err != nil {
+		t.Fatalf("LoadSSOConfig failed: %v", err)
+	}
+	if cfg.Region != "us-gov-west-1" {
+		t.Errorf("Expected region us-gov-west-1, got %s", cfg.Region)
+	}
+
+	// Without sso.region, fall back to default_region
+	viper.Set("sso.region", "")
+	cfg, err = LoadSSOConfig(context.Background())
+	if err != nil {
+		t.Fatalf("LoadSSOConfig failed: %v", err)
+	}
+	if cfg.Region != "us-west-2" {
+		t.Errorf("Expected region us-west-2, got %s", cfg.Region)
+	}
+}
+
 func TestLoadAWSConfigWithProfile_NoActiveSession(t *testing.T) {
 	// Create temporary directory for test
 	tempDir := t.TempDir()
@@ -95,7 +121,7 @@ func TestLoadAWSConfigWithProfile_EnvVarOverride(t *testing.T) {
 	defer os.Setenv("HOME", originalHome)
 
 	// Create a session file for current PPID
-	ppid := os.Getppid()
+	key := currentSessionKey(t)
 	sessionsDir := filepath.Join(tempDir, ".awsc", "sessions")
 	if err := os.MkdirAll(sessionsDir, 0700); err != nil {
 		t.Fatalf("Failed to create sessions directory: %v", err)
@@ -107,7 +133,7 @@ func TestLoadAWSConfigWithProfile_EnvVarOverride(t *testing.T) {
   "account_name": "ppid-account",
   "role_name": "PPIDRole"
 }`
-	sessionPath := filepath.Join(sessionsDir, fmt.Sprintf("session-%d.json", ppid))
+	sessionPath := filepath.Join(sessionsDir, sessionFileName(key))
 	if err := os.WriteFile(sessionPath, []byte(sessionContent), 0600); err != nil {
 		t.Fatalf("Failed to write session file: %v", err)
 	}
@@ -158,7 +184,7 @@ func TestLoadAWSConfigWithProfile_PPIDFallback(t *testing.T) {
 	}()
 
 	// Create a session file for current PPID
-	ppid := os.Getppid()
+	key := currentSessionKey(t)
 	sessionsDir := filepath.Join(tempDir, ".awsc", "sessions")
 	if err := os.MkdirAll(sessionsDir, 0700); err != nil {
 		t.Fatalf("Failed to create sessions directory: %v", err)
@@ -170,7 +196,7 @@ func TestLoadAWSConfigWithProfile_PPIDFallback(t *testing.T) {
   "account_name": "test-account",
   "role_name": "TestRole"
 }`
-	sessionPath := filepath.Join(sessionsDir, fmt.Sprintf("session-%d.json", ppid))
+	sessionPath := filepath.Join(sessionsDir, sessionFileName(key))
 	if err := os.WriteFile(sessionPath, []byte(sessionContent), 0600); err != nil {
 		t.Fatalf("Failed to write session file: %v", err)
 	}
@@ -192,3 +218,36 @@ func TestLoadAWSConfigWithProfile_PPIDFallback(t *testing.T) {
 		t.Log("Note: PPID session fallback is working (expected behavior)")
 	}
 }
+
+func TestResolveProfile(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_PROFILE", "")
+
+	// No session and no override
+	if _, _, err := ResolveProfile(); err == nil || err.Error() != "no active session" {
+		t.Errorf("Expected 'no active session' error, got: %v", err)
+	}
+
+	// PPID session
+	if err := SaveSession(currentSessionKey(t), "awsc-ppid-profile", "123456789012", "ppid-account", "PPIDRole", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	profile, source, err := ResolveProfile()
+	if err != nil {
+		t.Fatalf("ResolveProfile failed: %v", err)
+	}
+	if profile != "awsc-ppid-profile" || source != SessionSourcePPID {
+		t.Errorf("Expected awsc-ppid-profile from ppid, got %s from %s", profile, source)
+	}
+
+	// AWSC_PROFILE takes priority
+	t.Setenv("AWSC_PROFILE", "awsc-env-profile")
+	profile, source, err = ResolveProfile()
+	if err != nil {
+		t.Fatalf("ResolveProfile failed: %v", err)
+	}
+	if profile != "awsc-env-profile" || source != SessionSourceEnv {
+		t.Errorf("Expected awsc-env-profile from AWSC_PROFILE, got %s from %s", profile, source)
+	}
+}
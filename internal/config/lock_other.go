@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package config
+
+import "fmt"
+
+// lockFile fails where no file lock is available, rather than letting concurrent writers
+// lose each other's changes
+func lockFile(path string) (func(), error) {
+	return nil, fmt.Errorf("file locking isn't supported on this platform")
+}
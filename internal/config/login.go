@@ -0,0 +1,23 @@
+package config
+
+import "github.com/spf13/viper"
+
+const (
+	// LoginMethodDevice uses the OAuth device authorization flow (code entered in the browser)
+	LoginMethodDevice = "device"
+	// LoginMethodPKCE uses the authorization code flow with PKCE and a 127.0.0.1 redirect
+	LoginMethodPKCE = "pkce"
+)
+
+// GetLoginMethod returns the configured SSO login method, defaulting to the device flow
+func GetLoginMethod() string {
+	if method := viper.GetString("sso.login_method"); method != "" {
+		return method
+	}
+	return LoginMethodDevice
+}
+
+// IsNoBrowser reports whether login should print the sign-in URL instead of opening a browser
+func IsNoBrowser() bool {
+	return viper.GetBool("sso.no_browser")
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGetLoginMethod(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	if method := GetLoginMethod(); method != LoginMethodDevice {
+		t.Errorf("Expected default login method %s, got %s", LoginMethodDevice, method)
+	}
+
+	viper.Set("sso.login_method", LoginMethodPKCE)
+	if method := GetLoginMethod(); method != LoginMethodPKCE {
+		t.Errorf("Expected login method %s, got %s", LoginMethodPKCE, method)
+	}
+
+	if IsNoBrowser() {
+		t.Error("Expected no-browser to be off by default")
+	}
+	viper.Set("sso.no_browser", true)
+	if !IsNoBrowser() {
+		t.Error("Expected no-browser to be on")
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Partition describes an AWS partition: the regions it contains and the hosts awsc needs
+// outside the service endpoints, which the SDK resolves itself
+type Partition struct {
+	ID                 string
+	ConsoleURL         string   // Empty where awsc doesn't know the console
+	FederationEndpoint string   // Empty where awsc doesn't know the sign-in endpoint
+	StartURLDomains    []string // IAM Identity Center access portal domains
+	regionPattern      *regexp.Regexp
+}
+
+// partitions mirrors the partition IDs and region patterns of the SDK's partition metadata
+// (aws-sdk-go-v2/internal/endpoints/awsrulesfn/partitions.json), which is internal to the
+// SDK. Matching the pattern rather than a region list keeps new regions valid without a
+// release, as the SDK does.
+var partitions = []Partition{
+	{
+		ID:                 "aws",
+		ConsoleURL:         "https://console.aws.amazon.com",
+		FederationEndpoint: "https://signin.aws.amazon.com/federation",
+		StartURLDomains:    []string{"awsapps.com", "app.aws"},
+		regionPattern:      regexp.MustCompile(`^(us|eu|ap|sa|ca|me|af|il|mx)\-\w+\-\d+$`),
+	},
+	{
+		ID:                 "aws-cn",
+		ConsoleURL:         "https://console.amazonaws.cn",
+		FederationEndpoint: "https://signin.amazonaws.cn/federation",
+		StartURLDomains:    []string{"awsapps.cn"},
+		regionPattern:      regexp.MustCompile(`^cn\-\w+\-\d+$`),
+	},
+	{
+		ID:                 "aws-us-gov",
+		ConsoleURL:         "https://console.amazonaws-us-gov.com",
+		FederationEndpoint: "https://signin.amazonaws-us-gov.com/federation",
+		StartURLDomains:    []string{"awsapps-us-gov.com"},
+		regionPattern:      regexp.MustCompile(`^us\-gov\-\w+\-\d+$`),
+	},
+	{ID: "aws-eusc", regionPattern: regexp.MustCompile(`^eusc\-(de)\-\w+\-\d+$`)},
+	{ID: "aws-iso", regionPattern: regexp.MustCompile(`^us\-iso\-\w+\-\d+$`)},
+	{ID: "aws-iso-b", regionPattern: regexp.MustCompile(`^us\-isob\-\w+\-\d+$`)},
+	{ID: "aws-iso-e", regionPattern: regexp.MustCompile(`^eu\-isoe\-\w+\-\d+$`)},
+	{ID: "aws-iso-f", regionPattern: regexp.MustCompile(`^us\-isof\-\w+\-\d+$`)},
+}
+
+// startURLHostPattern matches the host name labels in front of a start URL domain
+var startURLHostPattern = regexp.MustCompile(`^([a-zA-Z0-9-]+\.)+$`)
+
+// PartitionForRegion returns the partition containing region
+func PartitionForRegion(region string) (Partition, error) {
+	for _, partition := range partitions {
+		if partition.regionPattern.MatchString(region) {
+			return partition, nil
+		}
+	}
+	return Partition{}, fmt.Errorf("invalid AWS region '%s'", region)
+}
+
+// ValidateRegion checks that region belongs to an AWS partition
+func ValidateRegion(region string) error {
+	_, err := PartitionForRegion(region)
+	return err
+}
+
+// ValidateStartURL checks that startURL is an IAM Identity Center access portal URL in any
+// partition, such as https://my-org.awsapps.com/start, https://start.us-gov-home.awsapps.com/start,
+// https://my-org.awsapps-us-gov.com/start or https://ssoins-1234.portal.us-east-1.app.aws
+func ValidateStartURL(startURL string) error {
+	parsed, err := url.Parse(startURL)
+	if err != nil || parsed.Scheme != "https" || parsed.RawQuery != "" || parsed.User != nil || parsed.Port() != "" {
+		return fmt.Errorf("invalid start URL '%s'", startURL)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, partition := range partitions {
+		for _, domain := range partition.StartURLDomains {
+			prefix, ok := strings.CutSuffix(host, domain)
+			if !ok || !startURLHostPattern.MatchString(prefix) {
+				continue
+			}
+			// awsapps.com style portals live under /start, app.aws portals at the root
+			if domain == "app.aws" && (parsed.Path == "" || parsed.Path == "/") {
+				return nil
+			}
+			if parsed.Path == "/start" || parsed.Path == "/start/" {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("invalid start URL '%s'", startURL)
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/spf13/viper"
+)
+
+func TestPartitionForRegion(t *testing.T) {
+	tests := []struct {
+		region    string
+		partition string
+		wantErr   bool
+	}{
+		{region: "us-east-1", partition: "aws"},
+		{region: "il-central-1", partition: "aws"},
+		{region: "us-gov-west-1", partition: "aws-us-gov"},
+		{region: "cn-northwest-1", partition: "aws-cn"},
+		{region: "us-iso-east-1", partition: "aws-iso"},
+		{region: "us-isob-east-1", partition: "aws-iso-b"},
+		{region: "eusc-de-east-1", partition: "aws-eusc"},
+		{region: "moon-base-1", wantErr: true},
+		{region: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			partition, err := PartitionForRegion(tt.region)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got partition %s", partition.ID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if partition.ID != tt.partition {
+				t.Errorf("Expected partition %s, got %s", tt.partition, partition.ID)
+			}
+		})
+	}
+}
+
+func TestPartitionsMatchSDK(t *testing.T) {
+	// The SDK resolves endpoints from its own partition metadata; a region it places in a
+	// different DNS domain than our table expects means the table is out of date
+	domains := map[string]string{
+		"aws":        "amazonaws.com",
+		"aws-us-gov": "amazonaws.com",
+		"aws-cn":     "amazonaws.com.cn",
+	}
+	for _, region := range []string{"us-east-1", "eu-central-2", "us-gov-east-1", "cn-north-1"} {
+		partition, err := PartitionForRegion(region)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", region, err)
+		}
+		endpoint, err := ssooidc.NewDefaultEndpointResolverV2().ResolveEndpoint(context.Background(), ssooidc.EndpointParameters{
+			Region: aws.String(region),
+		})
+		if err != nil {
+			t.Fatalf("SDK failed to resolve %s: %v", region, err)
+		}
+		if !strings.HasSuffix(endpoint.URI.Host, "."+region+"."+domains[partition.ID]) {
+			t.Errorf("Partition %s of %s doesn't match the SDK endpoint %s", partition.ID, region, endpoint.URI.Host)
+		}
+	}
+}
+
+func TestConsoleHostsFollowPartition(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		region     string
+		consoleURL string
+		federation string
+		wantErr    bool
+	}{
+		{region: "", consoleURL: DefaultConsoleURL, federation: DefaultFederationEndpoint},
+		{region: "eu-west-1", consoleURL: DefaultConsoleURL, federation: DefaultFederationEndpoint},
+		{region: "us-gov-west-1", consoleURL: "https://console.amazonaws-us-gov.com", federation: "https://signin.amazonaws-us-gov.com/federation"},
+		{region: "cn-north-1", consoleURL: "https://console.amazonaws.cn", federation: "https://signin.amazonaws.cn/federation"},
+		{region: "us-iso-east-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			consoleURL, err := GetConsoleURL(tt.region)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %s", consoleURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			federation, err := GetFederationEndpoint(tt.region)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if consoleURL != tt.consoleURL || federation != tt.federation {
+				t.Errorf("Expected %s and %s, got %s and %s", tt.consoleURL, tt.federation, consoleURL, federation)
+			}
+			for _, host := range []string{consoleURL, federation} {
+				if _, err := url.Parse(host); err != nil {
+					t.Errorf("Invalid URL %s: %v", host, err)
+				}
+			}
+		})
+	}
+
+	// Configured hosts apply in any partition
+	viper.Set("console.url", "https://console.example")
+	viper.Set("console.federation_endpoint", "https://signin.example/federation")
+	if consoleURL, err := GetConsoleURL("us-iso-east-1"); err != nil || consoleURL != "https://console.example" {
+		t.Errorf("Expected the configured console URL, got %s (%v)", consoleURL, err)
+	}
+	if federation, err := GetFederationEndpoint("us-iso-east-1"); err != nil || federation != "https://signin.example/federation" {
+		t.Errorf("Expected the configured federation endpoint, got %s (%v)", federation, err)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build darwin
+
+package config
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// readProcessInfo reads a process's parent and command line with sysctl
+func readProcessInfo(pid int) (processInfo, error) {
+	kinfo, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return processInfo{}, err
+	}
+	if int(kinfo.Proc.P_pid) != pid {
+		return processInfo{}, fmt.Errorf("process %d not found", pid)
+	}
+
+	info := processInfo{ppid: int(kinfo.Eproc.Ppid), args: []string{unix.ByteSliceToString(kinfo.Proc.P_comm[:])}}
+	if args, err := readProcessArgs(pid); err == nil && len(args) > 0 {
+		info.args = args
+	}
+	return info, nil
+}
+
+// readProcessArgs parses kern.procargs2: argc, the executable path, padding, then argv.
+// Processes of other users can't be read, which leaves just the command name.
+func readProcessArgs(pid int) ([]string, error) {
+	data, err := unix.SysctlRaw("kern.procargs2", pid)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid procargs for process %d", pid)
+	}
+
+	argc := int(binary.LittleEndian.Uint32(data[:4]))
+	data = data[4:]
+
+	// Skip the executable path and the NUL padding after it
+	if end := bytes.IndexByte(data, 0); end >= 0 {
+		data = data[end:]
+	}
+	data = bytes.TrimLeft(data, "\x00")
+
+	args := make([]string, 0, argc)
+	for len(args) < argc && len(data) > 0 {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			end = len(data)
+		}
+		args = append(args, string(data[:end]))
+		data = data[min(end+1, len(data)):]
+	}
+	return args, nil
+}
+
+// controllingTTY returns the device number of this process's controlling terminal and
+// the PID of its session leader
+func controllingTTY() (int, int, error) {
+	kinfo, err := unix.SysctlKinfoProc("kern.proc.pid", unix.Getpid())
+	if err != nil {
+		return 0, 0, err
+	}
+	// NODEV (-1) means no controlling terminal
+	if kinfo.Eproc.Tdev == -1 {
+		return 0, 0, fmt.Errorf("no controlling terminal")
+	}
+
+	session, err := unix.Getsid(0)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(kinfo.Eproc.Tdev), session, nil
+}
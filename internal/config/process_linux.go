@@ -0,0 +1,72 @@
+//go:build linux
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readProcessInfo reads a process's parent and command line from /proc
+func readProcessInfo(pid int) (processInfo, error) {
+	fields, comm, err := readProcStat(strconv.Itoa(pid))
+	if err != nil {
+		return processInfo{}, err
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return processInfo{}, fmt.Errorf("invalid stat for process %d", pid)
+	}
+
+	info := processInfo{ppid: ppid, args: []string{comm}}
+	if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil && len(cmdline) > 0 {
+		info.args = strings.Split(strings.TrimSuffix(string(cmdline), "\x00"), "\x00")
+	}
+	return info, nil
+}
+
+// controllingTTY returns the device number of this process's controlling terminal and
+// the PID of its session leader
+func controllingTTY() (int, int, error) {
+	fields, _, err := readProcStat("self")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	session, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return 0, 0, err
+	}
+	device, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return 0, 0, err
+	}
+	if device == 0 {
+		return 0, 0, fmt.Errorf("no controlling terminal")
+	}
+	return device, session, nil
+}
+
+// readProcStat returns the fields of /proc/<pid>/stat after the command name (state first)
+// and the command name, which may itself contain spaces and parentheses
+func readProcStat(pid string) ([]string, string, error) {
+	data, err := os.ReadFile("/proc/" + pid + "/stat")
+	if err != nil {
+		return nil, "", err
+	}
+
+	stat := string(data)
+	open, end := strings.Index(stat, "("), strings.LastIndex(stat, ")")
+	if open < 0 || end < open {
+		return nil, "", fmt.Errorf("invalid stat for process %s", pid)
+	}
+
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 5 {
+		return nil, "", fmt.Errorf("invalid stat for process %s", pid)
+	}
+	return fields, stat[open+1 : end], nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package config
+
+import "errors"
+
+var errProcessInfoUnsupported = errors.New("process information is not supported on this platform")
+
+// readProcessInfo is unsupported here, so the owning shell is always the parent process
+func readProcessInfo(pid int) (processInfo, error) {
+	return processInfo{}, errProcessInfoUnsupported
+}
+
+// controllingTTY is unsupported here, so the tty strategy falls back to ppid
+func controllingTTY() (int, int, error) {
+	return 0, 0, errProcessInfoUnsupported
+}
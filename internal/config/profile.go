@@ -4,95 +4,511 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/spf13/viper"
 )
 
+const (
+	// ProfileTypeStatic writes session credentials directly into the profile
+	ProfileTypeStatic = "static"
+	// ProfileTypeSSO writes sso-session based profiles resolved by the AWS CLI and SDKs
+	ProfileTypeSSO = "sso"
+	// ProfileTypeCredentialProcess writes profiles that call `awsc credential-process`
+	ProfileTypeCredentialProcess = "credential_process"
+
+	// defaultSSOSessionName is the sso-session name used when none is configured
+	defaultSSOSessionName = "awsc"
+)
+
+// GetProfileType returns the configured profile type, defaulting to static credentials.
+// Static profiles become credential_process profiles with the encrypted credential store,
+// so keys are never written to ~/.aws/config in plaintext.
+func GetProfileType() string {
+	profileType := viper.GetString("profile_type")
+	if profileType == "" {
+		profileType = ProfileTypeStatic
+	}
+	if profileType == ProfileTypeStatic && IsEncryptedCredentialStore() {
+		return ProfileTypeCredentialProcess
+	}
+	return profileType
+}
+
+// GetSSOSessionName returns the sso-session name shared with the AWS CLI
+func GetSSOSessionName() string {
+	if name := viper.GetString("sso.session_name"); name != "" {
+		return name
+	}
+	return defaultSSOSessionName
+}
+
+// ProfileEntry describes one awsc profile to write to ~/.aws/config
+type ProfileEntry struct {
+	ProfileName string
+	AccountName string
+	AccountID   string
+	RoleName    string
+	Creds       *types.RoleCredentials // Required for static profiles only
+}
+
 // WriteProfile writes AWS credentials to ~/.aws/config with the profile name awsc-{accountName}
 func WriteProfile(accountName, accountID, roleName string, creds *types.RoleCredentials) (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+	profileName := fmt.Sprintf("awsc-%s", accountName)
+
+	if err := writeConfigSections(map[string]string{
+		"profile " + profileName: staticProfileSection(profileName, accountName, accountID, roleName, creds),
+	}); err != nil {
+		return "", err
 	}
 
-	awsDir := filepath.Join(homeDir, ".aws")
-	if err := os.MkdirAll(awsDir, 0700); err != nil {
-		return "", fmt.Errorf("failed to create .aws directory: %w", err)
+	return profileName, nil
+}
+
+// WriteChainProfile writes the credentials of a chained role to ~/.aws/config with the
+// profile name awsc-{chainName}, recording the chain so re-login can repeat it. With the
+// encrypted credential store the profile reads them back through `awsc credential-process
+// --chain` instead; callers must cache them first.
+func WriteChainProfile(chainName, accountName, accountID, roleName string, creds *types.RoleCredentials) (string, error) {
+	profileName := fmt.Sprintf("awsc-%s", chainName)
+	header := fmt.Sprintf("[profile %s]\n", profileName)
+
+	body := strings.TrimPrefix(staticProfileSection(profileName, accountName, accountID, roleName, creds), header)
+	if IsEncryptedCredentialStore() {
+		var err error
+		body, err = chainCredentialProcessBody(chainName, accountName, accountID, roleName, creds)
+		if err != nil {
+			return "", err
+		}
 	}
+	section := header + fmt.Sprintf("# Chain: %s\n", chainName) + body
 
-	configPath := filepath.Join(awsDir, "config")
+	if err := writeConfigSections(map[string]string{"profile " + profileName: section}); err != nil {
+		return "", err
+	}
+
+	return profileName, nil
+}
+
+// WriteSSOProfile writes an [sso-session] section and an awsc-{accountName} profile that
+// references it, so the AWS CLI and SDKs resolve credentials from the shared SSO token cache
+func WriteSSOProfile(accountName, accountID, roleName string) (string, error) {
 	profileName := fmt.Sprintf("awsc-%s", accountName)
+	sessionName := GetSSOSessionName()
 
-	// Read existing config
-	var existingContent string
-	data, err := os.ReadFile(configPath)
-	if err != nil && !os.IsNotExist(err) {
-		return "", fmt.Errorf("failed to read config file: %w", err)
+	if err := writeConfigSections(map[string]string{
+		"sso-session " + sessionName: ssoSessionSection(sessionName),
+		"profile " + profileName:     ssoProfileSection(profileName, accountName, accountID, roleName, sessionName),
+	}); err != nil {
+		return "", err
 	}
-	if err == nil {
-		existingContent = string(data)
+
+	return profileName, nil
+}
+
+// WriteCredentialProcessProfile writes an awsc-{accountName} profile that fetches fresh
+// credentials on demand through `awsc credential-process`
+func WriteCredentialProcessProfile(accountName, accountID, roleName string) (string, error) {
+	profileName := fmt.Sprintf("awsc-%s", accountName)
+
+	section, err := credentialProcessProfileSection(profileName, accountName, accountID, roleName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeConfigSections(map[string]string{"profile " + profileName: section}); err != nil {
+		return "", err
 	}
 
-	// Remove old profile section if exists
-	existingContent = removeProfileSection(existingContent, profileName)
+	return profileName, nil
+}
+
+// WriteProfiles writes many profiles of the configured profile type in a single update
+func WriteProfiles(entries []ProfileEntry) error {
+	profileType := GetProfileType()
+	sessionName := GetSSOSessionName()
+	sections := make(map[string]string)
 
-	// Ensure existing content ends with newline if not empty
-	if existingContent != "" && !strings.HasSuffix(existingContent, "\n") {
-		existingContent += "\n"
+	if profileType == ProfileTypeSSO {
+		sections["sso-session "+sessionName] = ssoSessionSection(sessionName)
+	}
+
+	for _, entry := range entries {
+		var section string
+		switch profileType {
+		case ProfileTypeSSO:
+			section = ssoProfileSection(entry.ProfileName, entry.AccountName, entry.AccountID, entry.RoleName, sessionName)
+		case ProfileTypeCredentialProcess:
+			var err error
+			section, err = credentialProcessProfileSection(entry.ProfileName, entry.AccountName, entry.AccountID, entry.RoleName)
+			if err != nil {
+				return err
+			}
+		default:
+			if entry.Creds == nil {
+				return fmt.Errorf("missing credentials for profile %s", entry.ProfileName)
+			}
+			section = staticProfileSection(entry.ProfileName, entry.AccountName, entry.AccountID, entry.RoleName, entry.Creds)
+		}
+		sections["profile "+entry.ProfileName] = section
 	}
 
-	// Build new profile section
-	profileSection := fmt.Sprintf(`[profile %s]
+	return writeConfigSections(sections)
+}
+
+func staticProfileSection(profileName, accountName, accountID, roleName string, creds *types.RoleCredentials) string {
+	// Record when the static credentials expire so status and expiry warnings can find it
+	var expires string
+	if creds.Expiration > 0 {
+		expires = fmt.Sprintf("# Expires: %s\n", time.UnixMilli(creds.Expiration).UTC().Format(time.RFC3339))
+	}
+
+	return fmt.Sprintf(`[profile %s]
 # Account: %s (%s)
 # Role: %s
-aws_access_key_id = %s
+%saws_access_key_id = %s
 aws_secret_access_key = %s
 aws_session_token = %s
 
-`, profileName, accountName, accountID, roleName,
+`, profileName, accountName, accountID, roleName, expires,
 		*creds.AccessKeyId,
 		*creds.SecretAccessKey,
 		*creds.SessionToken)
+}
 
-	// Append new profile
-	newContent := existingContent + profileSection
+func ssoSessionSection(sessionName string) string {
+	return fmt.Sprintf(`[sso-session %s]
+sso_start_url = %s
+sso_region = %s
+sso_registration_scopes = sso:account:access
 
-	// Write back to file
-	if err := os.WriteFile(configPath, []byte(newContent), 0600); err != nil {
-		return "", fmt.Errorf("failed to write config file: %w", err)
+`, sessionName, viper.GetString("sso.start_url"), viper.GetString("sso.region"))
+}
+
+func ssoProfileSection(profileName, accountName, accountID, roleName, sessionName string) string {
+	return fmt.Sprintf(`[profile %s]
+# Account: %s (%s)
+# Role: %s
+sso_session = %s
+sso_account_id = %s
+sso_role_name = %s
+
+`, profileName, accountName, accountID, roleName, sessionName, accountID, roleName)
+}
+
+func credentialProcessProfileSection(profileName, accountName, accountID, roleName string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate awsc executable: %w", err)
 	}
 
-	return profileName, nil
+	command, err := quoteCommandArg(executable)
+	if err != nil {
+		return "", err
+	}
+	role, err := quoteCommandArg(roleName)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`[profile %s]
+# Account: %s (%s)
+# Role: %s
+credential_process = %s credential-process --account %s --role %s
+
+`, profileName, accountName, accountID, roleName, command, accountID, role), nil
 }
 
-// removeProfileSection removes a profile section from the config content
-func removeProfileSection(content, profileName string) string {
-	lines := strings.Split(content, "\n")
-	var result []string
-	inTargetProfile := false
+// chainCredentialProcessBody returns the body of a chain profile that serves the chain's
+// cached credentials. Chains can't be refreshed without prompting, so it keeps the expiry.
+func chainCredentialProcessBody(chainName, accountName, accountID, roleName string, creds *types.RoleCredentials) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate awsc executable: %w", err)
+	}
+
+	var expires string
+	if creds.Expiration > 0 {
+		expires = fmt.Sprintf("# Expires: %s\n", time.UnixMilli(creds.Expiration).UTC().Format(time.RFC3339))
+	}
+
+	command, err := quoteCommandArg(executable)
+	if err != nil {
+		return "", err
+	}
+	chain, err := quoteCommandArg(chainName)
+	if err != nil {
+		return "", err
+	}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	return fmt.Sprintf(`# Account: %s (%s)
+# Role: %s
+%scredential_process = %s credential-process --chain %s
 
-		// Check if this is the start of our target profile
-		if trimmed == fmt.Sprintf("[profile %s]", profileName) {
-			inTargetProfile = true
-			continue
+`, accountName, accountID, roleName, expires, command, chain), nil
+}
+
+// quoteCommandArg quotes a credential_process argument for the shell that runs it. The SDKs
+// run the command with sh -c and the AWS CLI splits it like a POSIX shell, so single quotes
+// keep $, backticks and backslashes literal. cmd.exe has no such quoting, so on Windows
+// arguments it would reinterpret are rejected instead.
+func quoteCommandArg(arg string) (string, error) {
+	return quoteCommandArgFor(runtime.GOOS, arg)
+}
+
+func quoteCommandArgFor(goos, arg string) (string, error) {
+	if goos == "windows" {
+		if strings.ContainsAny(arg, "\"%^&|<>!") {
+			return "", fmt.Errorf("can't use %s in credential_process: it contains characters cmd.exe would interpret", arg)
 		}
+		if strings.ContainsAny(arg, " \t") {
+			return `"` + arg + `"`, nil
+		}
+		return arg, nil
+	}
 
-		// Check if this is the start of a different profile
-		if strings.HasPrefix(trimmed, "[profile ") && trimmed != fmt.Sprintf("[profile %s]", profileName) {
-			inTargetProfile = false
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return arg, nil
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'", nil
+}
+
+// writeConfigSections replaces the given sections (keyed by header name, e.g. "profile x")
+// in ~/.aws/config where they are, appending them if they don't exist yet
+func writeConfigSections(sections map[string]string) error {
+	return updateConfigFile(func(file *iniFile) {
+		headers := make([]string, 0, len(sections))
+		for header := range sections {
+			headers = append(headers, header)
+		}
+		// Stable order, sso-session sections first so new profiles follow their session
+		sort.Slice(headers, func(i, j int) bool {
+			iSession := strings.HasPrefix(headers[i], "sso-session ")
+			jSession := strings.HasPrefix(headers[j], "sso-session ")
+			if iSession != jSession {
+				return iSession
+			}
+			return headers[i] < headers[j]
+		})
+
+		for _, header := range headers {
+			file.setSection(header, sections[header])
+		}
+	})
+}
+
+// RemoveProfiles removes the named profiles from ~/.aws/config
+func RemoveProfiles(profileNames []string) error {
+	return updateConfigFile(func(file *iniFile) {
+		for _, profileName := range profileNames {
+			file.removeSection("profile " + profileName)
+		}
+	})
+}
+
+// RemoveAllProfiles removes every awsc-* profile from ~/.aws/config, returning the names of
+// the removed profiles. The sso-session section is removed too unless another profile still
+// uses it, since it can be shared with the AWS CLI.
+func RemoveAllProfiles() ([]string, error) {
+	var removed []string
+	err := updateConfigFile(func(file *iniFile) {
+		removed = listAWSCProfiles(file)
+		for _, profileName := range removed {
+			file.removeSection("profile " + profileName)
+		}
+		sessionName := GetSSOSessionName()
+		if !file.hasValue("sso_session", sessionName) {
+			file.removeSection("sso-session " + sessionName)
+		}
+	})
+	return removed, err
+}
+
+// ListAWSCProfiles returns the names of all awsc-* profiles in ~/.aws/config
+func ListAWSCProfiles() ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".aws", "config"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return listAWSCProfiles(parseINI(string(data))), nil
+}
+
+// AWSCProfile is an awsc profile with the metadata from its comment header
+type AWSCProfile struct {
+	Name     string
+	Metadata *ProfileMetadata
+}
+
+// LoadAWSCProfiles returns every awsc profile in ~/.aws/config with its metadata
+func LoadAWSCProfiles() ([]AWSCProfile, error) {
+	names, err := ListAWSCProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]AWSCProfile, 0, len(names))
+	for _, name := range names {
+		metadata, err := GetProfileMetadata(name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, AWSCProfile{Name: name, Metadata: metadata})
+	}
+	return profiles, nil
+}
+
+// MatchAWSCProfiles returns the profiles matching target exactly by profile name (with or
+// without the awsc- prefix), or else by account name or ID
+func MatchAWSCProfiles(profiles []AWSCProfile, target string) []AWSCProfile {
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, target) || strings.EqualFold(profile.Name, "awsc-"+target) {
+			return []AWSCProfile{profile}
 		}
+	}
 
-		// Skip lines that are part of the target profile
-		if inTargetProfile {
-			continue
+	var matches []AWSCProfile
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Metadata.AccountName, target) || profile.Metadata.AccountID == target {
+			matches = append(matches, profile)
 		}
+	}
+	return matches
+}
 
-		result = append(result, line)
+// RemoveExpiredProfiles removes awsc profiles whose credentials have expired, with their
+// cached credentials, and returns their names. Sessions bound to them are kept: their next
+// command offers to log in again to the same account and role.
+func RemoveExpiredProfiles() ([]string, error) {
+	profiles, err := LoadAWSCProfiles()
+	if err != nil {
+		return nil, err
 	}
 
-	return strings.Join(result, "\n")
+	var expired []AWSCProfile
+	var names []string
+	for _, profile := range profiles {
+		if profile.Metadata.Expired() {
+			expired = append(expired, profile)
+			names = append(names, profile.Name)
+		}
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	if err := RemoveProfiles(names); err != nil {
+		return nil, fmt.Errorf("error removing profiles: %v", err)
+	}
+	for _, profile := range expired {
+		var err error
+		switch {
+		case profile.Metadata.Chain != "":
+			err = DeleteCachedChainCredentials(profile.Metadata.Chain)
+		case profile.Metadata.AccountID != "" && profile.Metadata.RoleName != "":
+			err = DeleteCachedCredentials(profile.Metadata.AccountID, profile.Metadata.RoleName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error removing cached credentials: %v", err)
+		}
+	}
+	return names, nil
+}
+
+// listAWSCProfiles returns the names of all awsc-* profiles in the config file
+func listAWSCProfiles(file *iniFile) []string {
+	var profiles []string
+	for _, name := range file.sectionNames("profile awsc-") {
+		profiles = append(profiles, strings.TrimPrefix(name, "profile "))
+	}
+	return profiles
+}
+
+// updateConfigFile applies update to ~/.aws/config while holding the awsc config lock, so
+// concurrent logins from other terminals can't lose each other's profiles. The result
+// replaces the file atomically and the previous version is kept in config.awsc-backup.
+func updateConfigFile(update func(file *iniFile)) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	awsDir := filepath.Join(homeDir, ".aws")
+	if err := os.MkdirAll(awsDir, 0700); err != nil {
+		return fmt.Errorf("failed to create .aws directory: %w", err)
+	}
+
+	unlock, err := lockFile(filepath.Join(awsDir, ".awsc-config.lock"))
+	if err != nil {
+		return fmt.Errorf("failed to lock config file: %w", err)
+	}
+	defer unlock()
+
+	configPath := filepath.Join(awsDir, "config")
+
+	// Read existing config
+	var existingContent string
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err == nil {
+		existingContent = string(data)
+	}
+
+	file := parseINI(existingContent)
+	update(file)
+	newContent := file.String()
+	if newContent == existingContent {
+		return nil
+	}
+
+	if existingContent != "" {
+		if err := writeFileAtomic(filepath.Join(awsDir, "config.awsc-backup"), existingContent); err != nil {
+			return fmt.Errorf("failed to back up config file: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(configPath, newContent); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes content to a temp file next to path and renames it into place, so
+// readers never see a partially written file
+func writeFileAtomic(path, content string) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // No-op after a successful rename
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
 }
@@ -1,13 +1,17 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/spf13/viper"
 )
 
 func TestWriteProfile(t *testing.T) {
@@ -168,7 +172,9 @@ aws_secret_access_key = SECRET3
 `
 
 	// Remove middle profile
-	result := removeProfileSection(content, "awsc-account2")
+	file := parseINI(content)
+	file.removeSection("profile awsc-account2")
+	result := file.String()
 
 	// Verify account2 is removed
 	if strings.Contains(result, "[profile awsc-account2]") {
@@ -192,3 +198,410 @@ aws_secret_access_key = SECRET3
 		t.Error("KEY3 was incorrectly removed")
 	}
 }
+
+func TestWriteSSOProfile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	viper.Set("sso.start_url", "https://test.awsapps.com/start")
+	viper.Set("sso.region", "us-east-1")
+	defer viper.Reset()
+
+	// Existing unrelated config must be preserved
+	configFile := filepath.Join(tempDir, ".aws", "config")
+	os.MkdirAll(filepath.Dir(configFile), 0700)
+	os.WriteFile(configFile, []byte("[default]\nregion = eu-west-1\n"), 0600)
+
+	for _, role := range []string{"OldRole", "NewRole"} {
+		profileName, err := WriteSSOProfile("test-account", "123456789012", role)
+		if err != nil {
+			t.Fatalf("WriteSSOProfile failed: %v", err)
+		}
+		if profileName != "awsc-test-account" {
+			t.Errorf("Expected profile name awsc-test-account, got %s", profileName)
+		}
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	configStr := string(content)
+
+	expected := []string{
+		"[default]\nregion = eu-west-1",
+		"[sso-session awsc]",
+		"sso_start_url = https://test.awsapps.com/start",
+		"sso_region = us-east-1",
+		"[profile awsc-test-account]",
+		"sso_session = awsc",
+		"sso_account_id = 123456789012",
+		"sso_role_name = NewRole",
+	}
+	for _, e := range expected {
+		if !strings.Contains(configStr, e) {
+			t.Errorf("Config file does not contain %q", e)
+		}
+	}
+
+	if strings.Contains(configStr, "OldRole") {
+		t.Error("Old role still present in config file")
+	}
+	if count := strings.Count(configStr, "[sso-session awsc]"); count != 1 {
+		t.Errorf("Expected sso-session to appear once, found %d times", count)
+	}
+	if strings.Index(configStr, "[sso-session awsc]") > strings.Index(configStr, "[profile awsc-test-account]") {
+		t.Error("Expected sso-session section before the profile referencing it")
+	}
+}
+
+func TestGetSSOSessionName(t *testing.T) {
+	defer viper.Reset()
+
+	if name := GetSSOSessionName(); name != "awsc" {
+		t.Errorf("Expected default session name awsc, got %s", name)
+	}
+
+	viper.Set("sso.session_name", "my-sso")
+	if name := GetSSOSessionName(); name != "my-sso" {
+		t.Errorf("Expected session name my-sso, got %s", name)
+	}
+}
+
+func TestRemoveSection_NonProfileHeaders(t *testing.T) {
+	content := `[profile awsc-account1]
+aws_access_key_id = KEY1
+
+[sso-session awsc]
+sso_start_url = https://test.awsapps.com/start
+
+[default]
+region = us-east-1
+`
+
+	// A profile followed by a non-profile section must not swallow it
+	file := parseINI(content)
+	file.removeSection("profile awsc-account1")
+	result := file.String()
+	if strings.Contains(result, "KEY1") {
+		t.Error("KEY1 was not removed")
+	}
+	if !strings.Contains(result, "[sso-session awsc]") || !strings.Contains(result, "[default]") {
+		t.Error("Following sections were incorrectly removed")
+	}
+
+	file = parseINI(content)
+	file.removeSection("sso-session awsc")
+	result = file.String()
+	if strings.Contains(result, "sso_start_url") {
+		t.Error("sso-session section was not removed")
+	}
+	if !strings.Contains(result, "region = us-east-1") || !strings.Contains(result, "KEY1") {
+		t.Error("Other sections were incorrectly removed")
+	}
+}
+
+func TestWriteProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	viper.Reset()
+	defer viper.Reset()
+
+	creds := &types.RoleCredentials{
+		AccessKeyId:     aws.String("AKIATEST123456789"),
+		SecretAccessKey: aws.String("test-secret-key"),
+		SessionToken:    aws.String("test-session-token"),
+	}
+
+	// Existing unrelated profile must survive the bulk write
+	awsDir := filepath.Join(tempDir, ".aws")
+	os.MkdirAll(awsDir, 0700)
+	os.WriteFile(filepath.Join(awsDir, "config"), []byte("[profile other]\nregion = eu-west-1\n"), 0600)
+
+	entries := []ProfileEntry{
+		{ProfileName: "awsc-prod-Admin", AccountName: "prod", AccountID: "111111111111", RoleName: "Admin", Creds: creds},
+		{ProfileName: "awsc-dev-Admin", AccountName: "dev", AccountID: "222222222222", RoleName: "Admin", Creds: creds},
+	}
+
+	if err := WriteProfiles(entries); err != nil {
+		t.Fatalf("WriteProfiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(awsDir, "config"))
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+
+	for _, expected := range []string{"[profile other]", "[profile awsc-prod-Admin]", "[profile awsc-dev-Admin]", "# Account: dev (222222222222)"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Config missing %q:\n%s", expected, string(content))
+		}
+	}
+
+	// No temp files should be left behind
+	files, _ := os.ReadDir(awsDir)
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), ".tmp") {
+			t.Errorf("Temp file left behind: %s", f.Name())
+		}
+	}
+
+	// Static profiles require credentials
+	if err := WriteProfiles([]ProfileEntry{{ProfileName: "awsc-x-Admin"}}); err == nil {
+		t.Error("Expected error for static profile without credentials")
+	}
+}
+
+func TestRemoveProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	viper.Reset()
+	defer viper.Reset()
+
+	awsDir := filepath.Join(tempDir, ".aws")
+	os.MkdirAll(awsDir, 0700)
+	configPath := filepath.Join(awsDir, "config")
+	initial := `[profile other]
+region = eu-west-1
+
+[sso-session awsc]
+sso_start_url = https://example.awsapps.com/start
+
+[profile awsc-prod]
+sso_session = awsc
+
+[profile awsc-dev-Admin]
+sso_session = awsc
+`
+
+	tests := []struct {
+		name       string
+		config     string // Defaults to initial
+		remove     func() error
+		expectGone []string
+		expectKept []string
+	}{
+		{
+			name:       "single profile",
+			remove:     func() error { return RemoveProfiles([]string{"awsc-prod"}) },
+			expectGone: []string{"[profile awsc-prod]"},
+			expectKept: []string{"[profile other]", "[sso-session awsc]", "[profile awsc-dev-Admin]"},
+		},
+		{
+			name: "all awsc profiles",
+			remove: func() error {
+				removed, err := RemoveAllProfiles()
+				if len(removed) != 2 {
+					t.Errorf("Expected 2 removed profiles, got %v", removed)
+				}
+				return err
+			},
+			expectGone: []string{"[profile awsc-prod]", "[profile awsc-dev-Admin]", "[sso-session awsc]"},
+			expectKept: []string{"[profile other]"},
+		},
+		{
+			name: "all awsc profiles keeps an sso-session other profiles use",
+			config: `[profile cli-admin]
+sso_session = awsc
+sso_account_id = 123456789012
+
+[sso-session awsc]
+sso_start_url = https://example.awsapps.com/start
+
+[profile awsc-prod]
+sso_session = awsc
+`,
+			remove: func() error {
+				_, err := RemoveAllProfiles()
+				return err
+			},
+			expectGone: []string{"[profile awsc-prod]"},
+			expectKept: []string{"[profile cli-admin]", "[sso-session awsc]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			if config == "" {
+				config = initial
+			}
+			os.WriteFile(configPath, []byte(config), 0600)
+
+			if err := tt.remove(); err != nil {
+				t.Fatalf("Remove failed: %v", err)
+			}
+
+			content, _ := os.ReadFile(configPath)
+			for _, section := range tt.expectGone {
+				if strings.Contains(string(content), section) {
+					t.Errorf("Expected %s to be removed:\n%s", section, string(content))
+				}
+			}
+			for _, section := range tt.expectKept {
+				if !strings.Contains(string(content), section) {
+					t.Errorf("Expected %s to be kept:\n%s", section, string(content))
+				}
+			}
+		})
+	}
+}
+
+func TestWriteProfile_ConcurrentWriters(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	creds := &types.RoleCredentials{
+		AccessKeyId:     aws.String("AKIATEST"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+	}
+
+	// Each login reads, updates and rewrites the whole file; without the lock some are lost
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := WriteProfile(fmt.Sprintf("account%d", i), "123456789012", "Admin", creds); err != nil {
+				t.Errorf("WriteProfile failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), ".aws", "config"))
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if !strings.Contains(string(content), fmt.Sprintf("[profile awsc-account%d]", i)) {
+			t.Errorf("Profile awsc-account%d was lost", i)
+		}
+	}
+}
+
+func TestUpdateConfigFile_PreservesAndBacksUp(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	awsDir := filepath.Join(tempDir, ".aws")
+	os.MkdirAll(awsDir, 0700)
+	initial := "# my settings\n[default]\nregion = eu-west-1\n\n[profile awsc-test]\naws_access_key_id = OLD\n\n[sso-session corp]\nsso_region = us-east-1\n"
+	os.WriteFile(filepath.Join(awsDir, "config"), []byte(initial), 0600)
+
+	creds := &types.RoleCredentials{
+		AccessKeyId:     aws.String("NEW"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+	}
+	if _, err := WriteProfile("test", "123456789012", "Admin", creds); err != nil {
+		t.Fatalf("WriteProfile failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(awsDir, "config"))
+	configStr := string(content)
+	if !strings.HasPrefix(configStr, "# my settings\n[default]\nregion = eu-west-1\n\n[profile awsc-test]\n") {
+		t.Errorf("Expected comments and order preserved with the profile updated in place:\n%s", configStr)
+	}
+	if !strings.Contains(configStr, "aws_access_key_id = NEW") || strings.Contains(configStr, "OLD") {
+		t.Errorf("Expected profile credentials replaced:\n%s", configStr)
+	}
+	if !strings.HasSuffix(configStr, "[sso-session corp]\nsso_region = us-east-1\n") {
+		t.Errorf("Expected trailing sso-session kept intact:\n%s", configStr)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(awsDir, "config.awsc-backup"))
+	if err != nil {
+		t.Fatalf("Expected backup file: %v", err)
+	}
+	if string(backup) != initial {
+		t.Errorf("Expected backup of the previous config, got:\n%s", string(backup))
+	}
+
+	info, _ := os.Stat(filepath.Join(awsDir, "config"))
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected config permissions 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestQuoteCommandArg(t *testing.T) {
+	tests := []struct {
+		name        string
+		goos        string
+		arg         string
+		expected    string
+		expectError bool
+	}{
+		{"plain path", "linux", "/usr/local/bin/awsc", "/usr/local/bin/awsc", false},
+		{"path with spaces", "darwin", "/Users/me/My Tools/awsc", "'/Users/me/My Tools/awsc'", false},
+		{"shell expansion stays literal", "linux", "/opt/$HOME/`id`/awsc", "'/opt/$HOME/`id`/awsc'", false},
+		{"backslash stays literal", "linux", `/opt/a\x41/awsc`, `'/opt/a\x41/awsc'`, false},
+		{"single quote", "linux", "/opt/it's/awsc", `'/opt/it'\''s/awsc'`, false},
+		{"empty argument", "linux", "", "''", false},
+		{"windows path", "windows", `C:\tools\awsc.exe`, `C:\tools\awsc.exe`, false},
+		{"windows path with spaces", "windows", `C:\Program Files\awsc\awsc.exe`, `"C:\Program Files\awsc\awsc.exe"`, false},
+		{"windows variable expansion", "windows", `C:\%TEMP%\awsc.exe`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quoteCommandArgFor(tt.goos, tt.arg)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRemoveExpiredProfiles(t *testing.T) {
+	tempDir := setupSessionsTest(t)
+
+	// Sessions bound to an expired profile are kept so they can log in again
+	key, err := CurrentSessionKey()
+	if err != nil {
+		t.Fatalf("CurrentSessionKey failed: %v", err)
+	}
+	if err := SaveSession(key, "awsc-prod", "111111111111", "prod", "Admin", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	removed, err := RemoveExpiredProfiles()
+	if err != nil {
+		t.Fatalf("RemoveExpiredProfiles failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "awsc-prod" {
+		t.Errorf("Expected only awsc-prod to be removed, got %v", removed)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(tempDir, ".aws", "config"))
+	contents := string(data)
+	if strings.Contains(contents, "[profile awsc-prod]") {
+		t.Error("Expired profile awsc-prod should have been removed")
+	}
+	for _, kept := range []string{"[profile awsc-dev]", "[profile other]"} {
+		if !strings.Contains(contents, kept) {
+			t.Errorf("Expected %s to be kept", kept)
+		}
+	}
+	if _, err := GetCurrentSession(); err != nil {
+		t.Errorf("Expected the session to be kept: %v", err)
+	}
+
+	if removed, err := RemoveExpiredProfiles(); err != nil || len(removed) != 0 {
+		t.Errorf("Expected nothing left to remove, got %v (%v)", removed, err)
+	}
+}
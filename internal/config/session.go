@@ -5,19 +5,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 )
 
 // SessionInfo contains information about the current session
 type SessionInfo struct {
-	ProfileName string `json:"profile_name"`
-	AccountID   string `json:"account_id"`
-	AccountName string `json:"account_name"`
-	RoleName    string `json:"role_name"`
+	ProfileName string    `json:"profile_name"`
+	AccountID   string    `json:"account_id"`
+	AccountName string    `json:"account_name"`
+	RoleName    string    `json:"role_name"`
+	Expiration  time.Time `json:"expiration,omitzero"` // Zero for profiles that refresh their own credentials
+	OwnerPID    int       `json:"owner_pid,omitempty"` // Process whose exit makes the session stale
 }
 
-// SaveSession saves session information for the given PPID
-func SaveSession(ppid int, profileName, accountID, accountName, roleName string) error {
+// SaveSession saves session information under the given session key
+func SaveSession(key SessionKey, profileName, accountID, accountName, roleName string, expiration time.Time) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("failed to get home directory: %w", err)
@@ -33,6 +39,8 @@ func SaveSession(ppid int, profileName, accountID, accountName, roleName string)
 		AccountID:   accountID,
 		AccountName: accountName,
 		RoleName:    roleName,
+		Expiration:  expiration,
+		OwnerPID:    key.OwnerPID,
 	}
 
 	data, err := json.MarshalIndent(session, "", "  ")
@@ -40,7 +48,7 @@ func SaveSession(ppid int, profileName, accountID, accountName, roleName string)
 		return fmt.Errorf("failed to marshal session: %w", err)
 	}
 
-	sessionFile := filepath.Join(sessionsDir, fmt.Sprintf("session-%d.json", ppid))
+	sessionFile := filepath.Join(sessionsDir, sessionFileName(key))
 	if err := os.WriteFile(sessionFile, data, 0600); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
@@ -48,23 +56,23 @@ func SaveSession(ppid int, profileName, accountID, accountName, roleName string)
 	return nil
 }
 
-// GetCurrentSession retrieves the session for the current shell (PPID)
+// GetCurrentSession retrieves the session for the current shell, terminal, pane or name
 func GetCurrentSession() (*SessionInfo, error) {
-	ppid := os.Getppid()
-	if ppid <= 0 {
-		return nil, fmt.Errorf("no active session")
+	key, err := CurrentSessionKey()
+	if err != nil {
+		return nil, ErrNoActiveSession
 	}
 
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("no active session")
+		return nil, ErrNoActiveSession
 	}
 
-	sessionFile := filepath.Join(homeDir, ".awsc", "sessions", fmt.Sprintf("session-%d.json", ppid))
+	sessionFile := filepath.Join(homeDir, ".awsc", "sessions", sessionFileName(key))
 	data, err := os.ReadFile(sessionFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("no active session")
+			return nil, ErrNoActiveSession
 		}
 		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
@@ -77,38 +85,200 @@ func GetCurrentSession() (*SessionInfo, error) {
 	return &session, nil
 }
 
-// CleanupStaleSessions removes session files for processes that no longer exist
-func CleanupStaleSessions() error {
+// DeleteSession removes the session file for the given session key
+func DeleteSession(key SessionKey) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil // Best effort
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	sessionFile := filepath.Join(homeDir, ".awsc", "sessions", sessionFileName(key))
+	if err := os.Remove(sessionFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove session file: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAllSessions removes the session files of every shell
+func DeleteAllSessions() error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(homeDir, ".awsc", "sessions")); err != nil {
+		return fmt.Errorf("failed to remove sessions directory: %w", err)
+	}
+
+	return nil
+}
+
+// StoredSession is a saved session and the state of the process that owns it
+type StoredSession struct {
+	Key string `json:"key"` // Session key ID, e.g. "1234", "tty-34816" or "name-deploy"
+	SessionInfo
+	Alive bool `json:"alive"` // False once the owning process has exited; named sessions are always alive
+}
+
+// ListSessions returns every saved session, ordered by key
+func ListSessions() ([]StoredSession, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
 	sessionsDir := filepath.Join(homeDir, ".awsc", "sessions")
 	entries, err := os.ReadDir(sessionsDir)
 	if err != nil {
-		return nil // Best effort
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
 	}
 
+	var sessions []StoredSession
 	for _, entry := range entries {
-		if entry.IsDir() {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "session-") || !strings.HasSuffix(name, ".json") {
 			continue
 		}
 
-		var ppid int
-		if _, err := fmt.Sscanf(entry.Name(), "session-%d.json", &ppid); err != nil {
-			continue
+		sessionFile := filepath.Join(sessionsDir, name)
+		session := StoredSession{Key: strings.TrimSuffix(strings.TrimPrefix(name, "session-"), ".json")}
+		if data, err := os.ReadFile(sessionFile); err == nil {
+			_ = json.Unmarshal(data, &session.SessionInfo) // Unreadable sessions are listed without details
+		}
+		ownerPID := sessionOwnerPID(sessionFile)
+		session.Alive = ownerPID <= 0 || processExists(ownerPID)
+		sessions = append(sessions, session)
+	}
+
+	// ReadDir sorts by file name
+	return sessions, nil
+}
+
+// ReadSession returns the session saved under the given key ID
+func ReadSession(keyID string) (*SessionInfo, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".awsc", "sessions", sessionFileName(SessionKey{ID: keyID})))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no session found for %s", keyID)
 		}
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var session SessionInfo
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session file: %w", err)
+	}
+	return &session, nil
+}
+
+// sessionKeyPattern matches session keys as shown by `awsc sessions list`
+var sessionKeyPattern = regexp.MustCompile(`^([0-9]+|[a-z]+-[A-Za-z0-9_.-]+)$`)
+
+// UseProfile binds the current shell to an existing awsc profile without logging in again
+func UseProfile(profile AWSCProfile) error {
+	return saveCurrentSession(profile.Name, &SessionInfo{
+		AccountID:   profile.Metadata.AccountID,
+		AccountName: profile.Metadata.AccountName,
+		RoleName:    profile.Metadata.RoleName,
+		Expiration:  profile.Metadata.Expiration,
+	})
+}
+
+// CopySession binds the current shell to the session of another terminal, given its shell
+// PID or session key, and returns that session
+func CopySession(from string) (*SessionInfo, error) {
+	if !sessionKeyPattern.MatchString(from) {
+		return nil, fmt.Errorf("invalid session '%s' (expected a PID or a key from 'awsc sessions list')", from)
+	}
 
-		if !processExists(ppid) {
-			sessionFile := filepath.Join(sessionsDir, entry.Name())
-			_ = os.Remove(sessionFile) // Ignore errors
+	key, err := CurrentSessionKey()
+	if err != nil {
+		return nil, err
+	}
+	if key.ID == from {
+		return nil, fmt.Errorf("session %s is already this shell's session", from)
+	}
+
+	session, err := ReadSession(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveCurrentSession(session.ProfileName, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// saveCurrentSession binds profileName to the current shell with the details of session
+func saveCurrentSession(profileName string, session *SessionInfo) error {
+	key, err := CurrentSessionKey()
+	if err != nil {
+		return fmt.Errorf("error saving session: %v", err)
+	}
+	if err := SaveSession(key, profileName, session.AccountID, session.AccountName, session.RoleName, session.Expiration); err != nil {
+		return fmt.Errorf("error saving session: %v", err)
+	}
+	return nil
+}
+
+// PruneSessions removes session files whose owning process no longer exists and returns
+// their keys. Named sessions have no owner and are kept.
+func PruneSessions() ([]string, error) {
+	sessions, err := ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, session := range sessions {
+		if session.Alive {
+			continue
+		}
+		if err := DeleteSession(SessionKey{ID: session.Key}); err != nil {
+			return removed, err
 		}
+		removed = append(removed, session.Key)
 	}
+	return removed, nil
+}
 
+// CleanupStaleSessions removes session files whose owning process no longer exists
+func CleanupStaleSessions() error {
+	_, _ = PruneSessions() // Best effort
 	return nil
 }
 
+// sessionFileName returns the file name of the session with the given key
+func sessionFileName(key SessionKey) string {
+	return fmt.Sprintf("session-%s.json", key.ID)
+}
+
+// sessionOwnerPID returns the PID recorded in a session file, or the PID in the file name
+// for sessions saved before owners were recorded. Returns 0 if the session has no owner.
+func sessionOwnerPID(sessionFile string) int {
+	if data, err := os.ReadFile(sessionFile); err == nil {
+		var session SessionInfo
+		if json.Unmarshal(data, &session) == nil && session.OwnerPID > 0 {
+			return session.OwnerPID
+		}
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(sessionFile), "session-"), ".json")
+	if pid, err := strconv.Atoi(id); err == nil {
+		return pid
+	}
+	return 0
+}
+
 // processExists checks if a process with the given PID exists
 func processExists(pid int) bool {
 	process, err := os.FindProcess(pid)
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// SessionKeyPPID keys sessions on the shell that runs awsc (default)
+	SessionKeyPPID = "ppid"
+	// SessionKeyTTY keys sessions on the controlling terminal, shared by every shell in it
+	SessionKeyTTY = "tty"
+	// SessionKeyTmux keys sessions on $TMUX_PANE, falling back to ppid outside tmux
+	SessionKeyTmux = "tmux"
+	// SessionKeyName keys sessions only on $AWSC_SESSION
+	SessionKeyName = "name"
+)
+
+// SessionKeyStrategies lists the valid session.key values
+var SessionKeyStrategies = []string{SessionKeyPPID, SessionKeyTTY, SessionKeyTmux, SessionKeyName}
+
+// maxProcessTreeDepth bounds the walk up the process tree when looking for the owning shell
+const maxProcessTreeDepth = 32
+
+var sessionNamePattern = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// SessionKey identifies the terminal, pane or name a session file belongs to
+type SessionKey struct {
+	ID       string // Session file suffix: "1234" for ppid keys, otherwise prefixed by kind
+	OwnerPID int    // Process whose exit makes the session stale, 0 if it never goes stale
+}
+
+// PPIDSessionKey returns the key of the session owned by the shell with the given PID
+func PPIDSessionKey(pid int) SessionKey {
+	return SessionKey{ID: strconv.Itoa(pid), OwnerPID: pid}
+}
+
+// GetSessionKeyStrategy returns the configured session.key strategy, defaulting to ppid
+func GetSessionKeyStrategy() string {
+	if strategy := viper.GetString("session.key"); slices.Contains(SessionKeyStrategies, strategy) {
+		return strategy
+	}
+	return SessionKeyPPID
+}
+
+// CurrentSessionKey resolves the session key for this process. $AWSC_SESSION always names
+// the session explicitly; otherwise the configured strategy applies, falling back to the
+// owning shell when there is no terminal or tmux pane.
+func CurrentSessionKey() (SessionKey, error) {
+	if name := os.Getenv("AWSC_SESSION"); name != "" {
+		return SessionKey{ID: "name-" + sessionNamePattern.ReplaceAllString(name, "_")}, nil
+	}
+
+	strategy := GetSessionKeyStrategy()
+	switch strategy {
+	case SessionKeyName:
+		return SessionKey{}, fmt.Errorf("session.key is %q but AWSC_SESSION is not set", strategy)
+	case SessionKeyTmux:
+		if pane := strings.TrimPrefix(os.Getenv("TMUX_PANE"), "%"); pane != "" {
+			return SessionKey{ID: "tmux-" + sessionNamePattern.ReplaceAllString(pane, "_"), OwnerPID: owningShellPID()}, nil
+		}
+	case SessionKeyTTY:
+		if device, leader, err := controllingTTY(); err == nil {
+			// The terminal's session leader lives as long as the terminal
+			return SessionKey{ID: fmt.Sprintf("tty-%d", device), OwnerPID: leader}, nil
+		}
+	}
+
+	pid := owningShellPID()
+	if pid <= 0 {
+		return SessionKey{}, ErrNoActiveSession
+	}
+	return PPIDSessionKey(pid), nil
+}
+
+// processInfo describes a process for the owning shell walk
+type processInfo struct {
+	ppid int
+	args []string // Command line, argv[0] first; may be just the command name
+}
+
+// owningShellPID walks up the process tree from the parent to the interactive shell that
+// runs awsc, skipping scripts, `sh -c` wrappers (make, editors), sudo and forked
+// subshells. Falls back to the parent PID when no shell is found.
+func owningShellPID() int {
+	parent := os.Getppid()
+
+	pid := parent
+	for depth := 0; pid > 1 && depth < maxProcessTreeDepth; depth++ {
+		info, err := readProcessInfo(pid)
+		if err != nil {
+			break
+		}
+		if isInteractiveShell(info.args) {
+			// A subshell is a fork of its parent shell with the same command line
+			parentInfo, err := readProcessInfo(info.ppid)
+			if err != nil || !slices.Equal(parentInfo.args, info.args) {
+				return pid
+			}
+		}
+		pid = info.ppid
+	}
+	return parent
+}
+
+var shellNames = []string{"bash", "zsh", "fish", "sh", "dash", "ksh", "mksh", "tcsh", "csh", "nu", "xonsh", "elvish", "pwsh", "powershell", "cmd"}
+
+// isInteractiveShell reports whether a command line is a shell running without a script
+// or -c command
+func isInteractiveShell(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	// Login shells are started as "-bash"
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(strings.TrimPrefix(args[0], "-"))), ".exe")
+	if !slices.Contains(shellNames, name) {
+		return false
+	}
+
+	for _, arg := range args[1:] {
+		if arg == "-c" || arg == "--command" || !strings.HasPrefix(arg, "-") {
+			return false
+		}
+	}
+	return true
+}
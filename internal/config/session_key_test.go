@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestCurrentSessionKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		strategy    string
+		session     string
+		tmuxPane    string
+		expectedID  string
+		expectOwner bool
+		expectError bool
+	}{
+		{"named session overrides strategy", SessionKeyTmux, "deploy/prod", "%3", "name-deploy_prod", false, false},
+		{"name strategy without AWSC_SESSION", SessionKeyName, "", "", "", false, true},
+		{"tmux pane", SessionKeyTmux, "", "%3", "tmux-3", true, false},
+		{"tmux strategy outside tmux", SessionKeyTmux, "", "", "", true, false},
+		{"default strategy", "", "", "%3", "", true, false},
+		{"unknown strategy", "bogus", "", "%3", "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("session.key", tt.strategy)
+			defer viper.Reset()
+			t.Setenv("AWSC_SESSION", tt.session)
+			t.Setenv("TMUX_PANE", tt.tmuxPane)
+
+			key, err := CurrentSessionKey()
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if tt.expectError {
+				return
+			}
+
+			if tt.expectedID != "" && key.ID != tt.expectedID {
+				t.Errorf("Expected key %s, got %s", tt.expectedID, key.ID)
+			}
+			if tt.expectedID == "" {
+				// Falls back to the owning shell
+				if pid, err := strconv.Atoi(key.ID); err != nil || pid != key.OwnerPID {
+					t.Errorf("Expected ppid key, got %+v", key)
+				}
+			}
+			if (key.OwnerPID > 0) != tt.expectOwner {
+				t.Errorf("Expected owner: %v, got %d", tt.expectOwner, key.OwnerPID)
+			}
+		})
+	}
+}
+
+func TestIsInteractiveShell(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"bash"}, true},
+		{[]string{"-zsh"}, true},
+		{[]string{"/usr/local/bin/fish", "--login"}, true},
+		{[]string{"bash", "-i"}, true},
+		{[]string{"pwsh.exe"}, true},
+		{[]string{"bash", "./deploy.sh"}, false},
+		{[]string{"/bin/sh", "-c", "awsc login"}, false},
+		{[]string{"make", "login"}, false},
+		{[]string{"sudo", "awsc", "login"}, false},
+		{[]string{"code"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if result := isInteractiveShell(tt.args); result != tt.expected {
+			t.Errorf("isInteractiveShell(%v): expected %v, got %v", tt.args, tt.expected, result)
+		}
+	}
+}
+
+func TestReadProcessInfo(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process information is only read on linux and darwin")
+	}
+
+	info, err := readProcessInfo(os.Getpid())
+	if err != nil {
+		t.Fatalf("readProcessInfo failed: %v", err)
+	}
+	if info.ppid != os.Getppid() {
+		t.Errorf("Expected parent %d, got %d", os.Getppid(), info.ppid)
+	}
+	if len(info.args) == 0 {
+		t.Error("Expected command line")
+	}
+}
+
+func TestCleanupStaleSessions_Strategies(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+
+	live := os.Getpid()
+	sessions := []struct {
+		key       SessionKey
+		remaining bool
+	}{
+		{PPIDSessionKey(999999), false},
+		{PPIDSessionKey(live), true},
+		{SessionKey{ID: "tty-34816", OwnerPID: 999998}, false},
+		{SessionKey{ID: "tty-34817", OwnerPID: live}, true},
+		{SessionKey{ID: "tmux-3", OwnerPID: 999997}, false},
+		{SessionKey{ID: "name-deploy"}, true},
+	}
+
+	for _, s := range sessions {
+		if err := SaveSession(s.key, "awsc-test", "123456789012", "test", "Admin", time.Time{}); err != nil {
+			t.Fatalf("SaveSession failed: %v", err)
+		}
+	}
+
+	if err := CleanupStaleSessions(); err != nil {
+		t.Fatalf("CleanupStaleSessions failed: %v", err)
+	}
+
+	for _, s := range sessions {
+		_, err := os.Stat(filepath.Join(tempDir, ".awsc", "sessions", sessionFileName(s.key)))
+		if exists := err == nil; exists != s.remaining {
+			t.Errorf("Session %s: expected remaining=%v, got %v", s.key.ID, s.remaining, exists)
+		}
+	}
+}
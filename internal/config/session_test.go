@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestSaveAndGetSession(t *testing.T) {
@@ -16,14 +18,14 @@ func TestSaveAndGetSession(t *testing.T) {
 	defer os.Setenv("HOME", originalHome)
 
 	// Test data
-	ppid := 12345
+	key := PPIDSessionKey(12345)
 	profileName := "awsc-test-account"
 	accountID := "123456789012"
 	accountName := "test-account"
 	roleName := "TestRole"
 
 	// Save session
-	err := SaveSession(ppid, profileName, accountID, accountName, roleName)
+	err := SaveSession(key, profileName, accountID, accountName, roleName, time.Time{})
 	if err != nil {
 		t.Fatalf("SaveSession failed: %v", err)
 	}
@@ -139,3 +141,204 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDeleteSession(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+
+	if err := SaveSession(PPIDSessionKey(111), "awsc-a", "111111111111", "a", "Admin", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if err := SaveSession(PPIDSessionKey(222), "awsc-b", "222222222222", "b", "Admin", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	sessionsDir := filepath.Join(tempDir, ".awsc", "sessions")
+
+	if err := DeleteSession(PPIDSessionKey(111)); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(sessionsDir, "session-111.json")); !os.IsNotExist(err) {
+		t.Error("Session file for 111 should have been removed")
+	}
+	if _, err := os.Stat(filepath.Join(sessionsDir, "session-222.json")); err != nil {
+		t.Error("Session file for 222 should still exist")
+	}
+
+	// Deleting a missing session is not an error
+	if err := DeleteSession(PPIDSessionKey(111)); err != nil {
+		t.Errorf("DeleteSession on missing file failed: %v", err)
+	}
+
+	if err := DeleteAllSessions(); err != nil {
+		t.Fatalf("DeleteAllSessions failed: %v", err)
+	}
+	if _, err := os.Stat(sessionsDir); !os.IsNotExist(err) {
+		t.Error("Sessions directory should have been removed")
+	}
+}
+
+// currentSessionKey returns the session key the code under test resolves for this process
+func currentSessionKey(t *testing.T) SessionKey {
+	t.Helper()
+	key, err := CurrentSessionKey()
+	if err != nil {
+		t.Fatalf("CurrentSessionKey failed: %v", err)
+	}
+	return key
+}
+
+func TestListAndReadSessions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if sessions, err := ListSessions(); err != nil || len(sessions) != 0 {
+		t.Fatalf("Expected no sessions, got %v (%v)", sessions, err)
+	}
+
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := SaveSession(PPIDSessionKey(os.Getpid()), "awsc-a", "111111111111", "a", "Admin", expiration); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if err := SaveSession(PPIDSessionKey(999999), "awsc-b", "222222222222", "b", "Admin", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if err := SaveSession(SessionKey{ID: "name-ci"}, "awsc-c", "333333333333", "c", "Deploy", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	sessions, err := ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+
+	alive := map[string]bool{}
+	for _, session := range sessions {
+		alive[session.Key] = session.Alive
+	}
+	expected := map[string]bool{strconv.Itoa(os.Getpid()): true, "999999": false, "name-ci": true}
+	for key, expectedAlive := range expected {
+		if got, ok := alive[key]; !ok || got != expectedAlive {
+			t.Errorf("Session %s: expected alive=%v, got %v (listed: %v)", key, expectedAlive, got, ok)
+		}
+	}
+
+	session, err := ReadSession("name-ci")
+	if err != nil || session.ProfileName != "awsc-c" {
+		t.Errorf("Expected awsc-c from ReadSession, got %+v (%v)", session, err)
+	}
+	if _, err := ReadSession("12"); err == nil {
+		t.Error("Expected error reading missing session")
+	}
+
+	removed, err := PruneSessions()
+	if err != nil || len(removed) != 1 || removed[0] != "999999" {
+		t.Errorf("Expected only 999999 pruned, got %v (%v)", removed, err)
+	}
+}
+
+const sessionsTestConfig = `[profile awsc-prod]
+# Account: prod (111111111111)
+# Role: Admin
+# Expires: 2000-01-02T03:04:05Z
+aws_access_key_id = AKIAPROD
+
+[profile awsc-dev]
+# Account: dev (222222222222)
+# Role: ReadOnly
+sso_session = awsc
+
+[profile other]
+region = us-east-1
+`
+
+func setupSessionsTest(t *testing.T) string {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_PROFILE", "")
+	t.Setenv("AWSC_SESSION", "")
+
+	awsDir := filepath.Join(tempDir, ".aws")
+	if err := os.MkdirAll(awsDir, 0700); err != nil {
+		t.Fatalf("Failed to create .aws directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(awsDir, "config"), []byte(sessionsTestConfig), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return tempDir
+}
+
+func TestUseProfile(t *testing.T) {
+	tests := []struct {
+		name            string
+		target          string
+		expectedProfile string
+		expectedAccount string
+	}{
+		{"profile name", "awsc-dev", "awsc-dev", "222222222222"},
+		{"profile name without prefix", "prod", "awsc-prod", "111111111111"},
+		{"account name", "DEV", "awsc-dev", "222222222222"},
+		{"account ID", "111111111111", "awsc-prod", "111111111111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupSessionsTest(t)
+
+			profiles, err := LoadAWSCProfiles()
+			if err != nil {
+				t.Fatalf("LoadAWSCProfiles failed: %v", err)
+			}
+			matches := MatchAWSCProfiles(profiles, tt.target)
+			if len(matches) != 1 {
+				t.Fatalf("Expected one profile matching %s, got %d", tt.target, len(matches))
+			}
+			if err := UseProfile(matches[0]); err != nil {
+				t.Fatalf("UseProfile failed: %v", err)
+			}
+
+			session, err := GetCurrentSession()
+			if err != nil {
+				t.Fatalf("Expected a session for this shell: %v", err)
+			}
+			if session.ProfileName != tt.expectedProfile || session.AccountID != tt.expectedAccount {
+				t.Errorf("Expected %s (%s), got %s (%s)", tt.expectedProfile, tt.expectedAccount, session.ProfileName, session.AccountID)
+			}
+		})
+	}
+
+	setupSessionsTest(t)
+	profiles, _ := LoadAWSCProfiles()
+	if matches := MatchAWSCProfiles(profiles, "staging"); len(matches) != 0 {
+		t.Errorf("Expected no profile matching staging, got %d", len(matches))
+	}
+}
+
+func TestCopySession(t *testing.T) {
+	setupSessionsTest(t)
+
+	source := SessionKey{ID: "name-deploy"}
+	if err := SaveSession(source, "awsc-dev", "222222222222", "dev", "ReadOnly", time.Time{}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	copied, err := CopySession("name-deploy")
+	if err != nil {
+		t.Fatalf("CopySession failed: %v", err)
+	}
+	if copied.ProfileName != "awsc-dev" {
+		t.Errorf("Expected the awsc-dev session, got %+v", copied)
+	}
+	session, err := GetCurrentSession()
+	if err != nil || session.ProfileName != "awsc-dev" || session.RoleName != "ReadOnly" {
+		t.Errorf("Expected copied awsc-dev session, got %+v (%v)", session, err)
+	}
+
+	for _, from := range []string{"../config", "999999"} {
+		if _, err := CopySession(from); err == nil {
+			t.Errorf("Expected error copying session %q", from)
+		}
+	}
+}
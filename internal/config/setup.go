@@ -6,56 +6,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/spf13/viper"
 )
 
-// AWS regions list
-var awsRegions = map[string]bool{
-	"us-east-1":      true,
-	"us-east-2":      true,
-	"us-west-1":      true,
-	"us-west-2":      true,
-	"af-south-1":     true,
-	"ap-east-1":      true,
-	"ap-south-1":     true,
-	"ap-south-2":     true,
-	"ap-southeast-1": true,
-	"ap-southeast-2": true,
-	"ap-southeast-3": true,
-	"ap-southeast-4": true,
-	"ap-northeast-1": true,
-	"ap-northeast-2": true,
-	"ap-northeast-3": true,
-	"ca-central-1":   true,
-	"ca-west-1":      true,
-	"eu-central-1":   true,
-	"eu-central-2":   true,
-	"eu-west-1":      true,
-	"eu-west-2":      true,
-	"eu-west-3":      true,
-	"eu-north-1":     true,
-	"eu-south-1":     true,
-	"eu-south-2":     true,
-	"il-central-1":   true,
-	"me-central-1":   true,
-	"me-south-1":     true,
-	"sa-east-1":      true,
-}
-
-// SSO URL regex pattern
-var ssoURLPattern = regexp.MustCompile(`^https://[a-zA-Z0-9-]+\.awsapps\.com/start/?$`)
-
-// validateRegion checks if the region is a valid AWS region
+// validateRegion checks if the region belongs to an AWS partition
 func validateRegion(region string) bool {
-	return awsRegions[region]
+	return ValidateRegion(region) == nil
 }
 
-// validateSSOURL checks if the SSO URL matches the expected pattern
+// validateSSOURL checks if the SSO URL is an access portal URL in any partition
 func validateSSOURL(url string) bool {
-	return ssoURLPattern.MatchString(url)
+	return ValidateStartURL(url) == nil
 }
 
 func EnsureConfigExists() error {
@@ -89,7 +52,7 @@ func InitializeConfig() error {
 		if validateSSOURL(ssoStartURL) {
 			break
 		}
-		fmt.Printf("Invalid SSO URL format. Expected: https://your-org.awsapps.com/start\n")
+		fmt.Printf("Invalid SSO URL format. Expected: https://your-org.awsapps.com/start (or awsapps-us-gov.com, awsapps.cn)\n")
 	}
 
 	// Get SSO Region
@@ -224,5 +187,9 @@ func ShowConfig() error {
 	fmt.Printf("SSO Start URL: %s\n", viper.GetString("sso.start_url"))
 	fmt.Printf("SSO Region: %s\n", viper.GetString("sso.region"))
 	fmt.Printf("Default Region: %s\n", viper.GetString("default_region"))
+	fmt.Printf("SSO Session Name: %s\n", GetSSOSessionName())
+	fmt.Printf("Profile Type: %s\n", GetProfileType())
+	fmt.Printf("Credential Store: %s\n", GetCredentialStoreType())
+	fmt.Printf("Expiry Warning: %v\n", GetExpiryWarningMargin())
 	return nil
 }
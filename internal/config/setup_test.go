@@ -146,8 +146,13 @@ func TestValidateRegion(t *testing.T) {
 		{"us-west-2", true},
 		{"eu-west-1", true},
 		{"ap-southeast-1", true},
+		{"mx-central-1", true},   // newer regions are valid without a release
+		{"ap-southeast-7", true}, // newer regions are valid without a release
+		{"us-gov-west-1", true},
+		{"cn-north-1", true},
 		{"invalid-region", false},
-		{"us-east-99", false},
+		{"us-east", false},
+		{"xx-east-1", false}, // not in any partition
 		{"", false},
 		{"US-EAST-1", false}, // case sensitive
 	}
@@ -176,7 +181,15 @@ func TestValidateSSOURL(t *testing.T) {
 		{"https://my_org.awsapps.com/start", false},  // underscore not allowed
 		{"myorg.awsapps.com/start", false},           // missing https
 		{"", false},
-		{"https://.awsapps.com/start", false}, // empty subdomain
+		{"https://.awsapps.com/start", false},                       // empty subdomain
+		{"https://my-org.awsapps-us-gov.com/start", true},           // GovCloud
+		{"https://start.us-gov-home.awsapps.com/start", true},       // GovCloud on awsapps.com
+		{"https://my-org.awsapps.cn/start", true},                   // China
+		{"https://ssoins-7223a0b1.portal.us-east-1.app.aws", true},  // access portal URL
+		{"https://ssoins-7223a0b1.portal.us-east-1.app.aws/", true}, // access portal URL
+		{"https://myorg.awsapps.com.evil.example/start", false},     // wrong domain
+		{"https://myorgawsapps.com/start", false},                   // not a subdomain
+		{"https://myorg.awsapps.com/start?next=x", false},           // query string
 	}
 
 	for _, tt := range tests {
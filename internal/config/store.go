@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// CredentialStorePlaintext keeps tokens and credentials in 0600 files (default)
+	CredentialStorePlaintext = "plaintext"
+	// CredentialStoreEncrypted encrypts them with a key file or AWSC_STORE_PASSPHRASE
+	CredentialStoreEncrypted = "encrypted"
+)
+
+// CredentialStore persists the secrets awsc caches on disk - the SSO token and role
+// credentials - keyed by the plaintext file path they have always used
+type CredentialStore interface {
+	// Load returns the data stored for path, or an error satisfying os.IsNotExist
+	Load(path string) ([]byte, error)
+	// Save stores data for path, creating its directory with secure permissions
+	Save(path string, data []byte) error
+	// Delete removes the data stored for path; deleting missing data is not an error
+	Delete(path string) error
+}
+
+// GetCredentialStoreType returns the configured credential_store.type, defaulting to plaintext
+func GetCredentialStoreType() string {
+	if storeType := viper.GetString("credential_store.type"); storeType != "" {
+		return storeType
+	}
+	return CredentialStorePlaintext
+}
+
+// IsEncryptedCredentialStore reports whether credentials are kept in the encrypted store
+func IsEncryptedCredentialStore() bool {
+	return GetCredentialStoreType() == CredentialStoreEncrypted
+}
+
+// GetCredentialStore returns the configured credential store
+func GetCredentialStore() (CredentialStore, error) {
+	switch GetCredentialStoreType() {
+	case CredentialStorePlaintext:
+		return plaintextStore{}, nil
+	case CredentialStoreEncrypted:
+		if viper.GetString("profile_type") == ProfileTypeSSO {
+			// The AWS CLI and SDKs read the SSO token cache of sso profiles themselves
+			return nil, fmt.Errorf("profile_type sso can't be used with the encrypted credential store, use credential_process")
+		}
+		return newEncryptedStore()
+	default:
+		return nil, fmt.Errorf("invalid credential_store.type '%s' (expected %s or %s)", GetCredentialStoreType(), CredentialStorePlaintext, CredentialStoreEncrypted)
+	}
+}
+
+// plaintextStore keeps data in the file at path, readable only by the user
+type plaintextStore struct{}
+
+func (plaintextStore) Load(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (plaintextStore) Save(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (plaintextStore) Delete(path string) error {
+	return RemoveStoredFile(path)
+}
+
+// RemoveStoredFile removes the data stored for path by any store, so deleting secrets
+// works even when the configured store can't be opened (e.g. the key is missing)
+func RemoveStoredFile(path string) error {
+	return removeFiles(path, path+encryptedFileSuffix)
+}
+
+// removeFiles removes every path, ignoring missing files
+func removeFiles(paths ...string) error {
+	var errs []error
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/spf13/viper"
+)
+
+const (
+	// encryptedFileSuffix is appended to the plaintext path of data in the encrypted store
+	encryptedFileSuffix = ".enc"
+	// passphraseIterations is the PBKDF2-SHA256 work factor for passphrase-derived keys
+	passphraseIterations = 210000
+	encryptionKeySize    = 32 // AES-256
+)
+
+// encryptedEnvelope is the on-disk format of the encrypted store
+type encryptedEnvelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf,omitempty"` // "pbkdf2-sha256" for passphrase-derived keys
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// encryptedStore encrypts data with AES-256-GCM next to its plaintext path, using the key
+// in credential_store.key_file or one derived from $AWSC_STORE_PASSPHRASE
+type encryptedStore struct {
+	key        []byte // Set when using a key file
+	passphrase string // Set when using a passphrase
+}
+
+func newEncryptedStore() (*encryptedStore, error) {
+	if keyFile := viper.GetString("credential_store.key_file"); keyFile != "" {
+		return newEncryptedStoreWithKeyFile(expandHome(keyFile))
+	}
+
+	if passphrase := os.Getenv("AWSC_STORE_PASSPHRASE"); passphrase != "" {
+		return &encryptedStore{passphrase: passphrase}, nil
+	}
+
+	return nil, fmt.Errorf("the encrypted credential store needs credential_store.key_file or AWSC_STORE_PASSPHRASE")
+}
+
+func newEncryptedStoreWithKeyFile(keyFile string) (*encryptedStore, error) {
+	key, err := loadOrCreateKeyFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedStore{key: key}, nil
+}
+
+func (s *encryptedStore) Load(path string) ([]byte, error) {
+	data, err := os.ReadFile(path + encryptedFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope encryptedEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path)+encryptedFileSuffix, err)
+	}
+	if envelope.Version != 1 {
+		return nil, fmt.Errorf("unsupported encrypted store version %d", envelope.Version)
+	}
+
+	key := s.key
+	if envelope.KDF != "" {
+		if s.passphrase == "" {
+			return nil, fmt.Errorf("%s was encrypted with a passphrase, set AWSC_STORE_PASSPHRASE", filepath.Base(path))
+		}
+		if key, err = pbkdf2.Key(sha256.New, s.passphrase, envelope.Salt, envelope.Iterations, encryptionKeySize); err != nil {
+			return nil, err
+		}
+	} else if key == nil {
+		return nil, fmt.Errorf("%s was encrypted with a key file, set credential_store.key_file", filepath.Base(path))
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(filepath.Base(path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: wrong key or passphrase", filepath.Base(path))
+	}
+	return plaintext, nil
+}
+
+func (s *encryptedStore) Save(path string, data []byte) error {
+	envelope := encryptedEnvelope{Version: 1}
+
+	key := s.key
+	if key == nil {
+		envelope.KDF = "pbkdf2-sha256"
+		envelope.Iterations = passphraseIterations
+		envelope.Salt = make([]byte, 16)
+		if _, err := rand.Read(envelope.Salt); err != nil {
+			return err
+		}
+		var err error
+		if key, err = pbkdf2.Key(sha256.New, s.passphrase, envelope.Salt, envelope.Iterations, encryptionKeySize); err != nil {
+			return err
+		}
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	envelope.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return err
+	}
+	// Bind the ciphertext to its file name so files can't be swapped
+	envelope.Ciphertext = gcm.Seal(nil, envelope.Nonce, data, []byte(filepath.Base(path)))
+
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+encryptedFileSuffix, encoded, 0600); err != nil {
+		return err
+	}
+
+	// Don't leave a plaintext copy from before the store was encrypted
+	return removeFiles(path)
+}
+
+func (s *encryptedStore) Delete(path string) error {
+	return RemoveStoredFile(path)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadOrCreateKeyFile reads a base64 key file, generating a random key on first use
+func loadOrCreateKeyFile(path string) ([]byte, error) {
+	key, err := readKeyFile(path)
+	if err == nil || !os.IsNotExist(err) {
+		return key, err
+	}
+
+	key = make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key file directory: %w", err)
+	}
+
+	// The key is written in full to a temp file and linked into place, so the key file
+	// never exists half-written, and a process that loses the race to create it uses the
+	// winner's key rather than overwriting it
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if os.IsExist(err) {
+			return readKeyFile(path)
+		}
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+
+	debug.Printf("Created credential store key file %s\n", path)
+	return key, nil
+}
+
+// readKeyFile reads and validates a base64 key file, returning a not-exist error as is
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("invalid key file %s: expected %d base64-encoded bytes", path, encryptionKeySize)
+	}
+	return key, nil
+}
+
+// expandHome expands a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
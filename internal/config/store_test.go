@@ -0,0 +1,346 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/spf13/viper"
+)
+
+func TestGetCredentialStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   map[string]string
+		passphrase string
+		wantErr    bool
+		wantType   string
+	}{
+		{name: "default is plaintext", wantType: "plaintext"},
+		{name: "plaintext", settings: map[string]string{"credential_store.type": "plaintext"}, wantType: "plaintext"},
+		{name: "encrypted with key file", settings: map[string]string{"credential_store.type": "encrypted", "credential_store.key_file": "~/.awsc/store.key"}, wantType: "encrypted"},
+		{name: "encrypted with passphrase", settings: map[string]string{"credential_store.type": "encrypted"}, passphrase: "hunter2", wantType: "encrypted"},
+		{name: "encrypted without key", settings: map[string]string{"credential_store.type": "encrypted"}, wantErr: true},
+		{name: "encrypted with sso profiles", settings: map[string]string{"credential_store.type": "encrypted", "profile_type": "sso"}, passphrase: "hunter2", wantErr: true},
+		{name: "unknown type", settings: map[string]string{"credential_store.type": "keychain"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("AWSC_STORE_PASSPHRASE", tt.passphrase)
+			viper.Reset()
+			defer viper.Reset()
+			for key, value := range tt.settings {
+				viper.Set(key, value)
+			}
+
+			store, err := GetCredentialStore()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCredentialStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			_, encrypted := store.(*encryptedStore)
+			if encrypted != (tt.wantType == "encrypted") {
+				t.Errorf("GetCredentialStore() returned %T, want %s store", store, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestEncryptedStore_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		store func(t *testing.T, home string) CredentialStore
+	}{
+		{
+			name: "key file",
+			store: func(t *testing.T, home string) CredentialStore {
+				store, err := newEncryptedStoreWithKeyFile(filepath.Join(home, "store.key"))
+				if err != nil {
+					t.Fatalf("failed to create store: %v", err)
+				}
+				return store
+			},
+		},
+		{
+			name: "passphrase",
+			store: func(t *testing.T, home string) CredentialStore {
+				return &encryptedStore{passphrase: "correct horse battery staple"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			store := tt.store(t, home)
+			path := filepath.Join(home, "cache", "token.json")
+			secret := []byte(`{"accessToken":"secret-token"}`)
+
+			if err := store.Save(path, secret); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			// Only the encrypted file exists, and it doesn't contain the secret
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("plaintext file should not exist, stat error = %v", err)
+			}
+			data, err := os.ReadFile(path + ".enc")
+			if err != nil {
+				t.Fatalf("encrypted file was not created: %v", err)
+			}
+			if bytes.Contains(data, []byte("secret-token")) {
+				t.Error("encrypted file contains the plaintext secret")
+			}
+			if info, _ := os.Stat(path + ".enc"); info.Mode().Perm() != 0600 {
+				t.Errorf("expected file permissions 0600, got %o", info.Mode().Perm())
+			}
+
+			loaded, err := store.Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !bytes.Equal(loaded, secret) {
+				t.Errorf("Load() = %s, want %s", loaded, secret)
+			}
+
+			if err := store.Delete(path); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := store.Load(path); !os.IsNotExist(err) {
+				t.Errorf("Load() after Delete() error = %v, want not exist", err)
+			}
+		})
+	}
+}
+
+func TestEncryptedStore_WrongKey(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, "credentials.json")
+
+	if err := (&encryptedStore{passphrase: "right"}).Save(path, []byte("secret")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	_, err := (&encryptedStore{passphrase: "wrong"}).Load(path)
+	if err == nil || !strings.Contains(err.Error(), "wrong key or passphrase") {
+		t.Errorf("Load() with wrong passphrase error = %v", err)
+	}
+
+	// A key file can't open data encrypted with a passphrase
+	keyStore, err := newEncryptedStoreWithKeyFile(filepath.Join(home, "store.key"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := keyStore.Load(path); err == nil || !strings.Contains(err.Error(), "AWSC_STORE_PASSPHRASE") {
+		t.Errorf("Load() with key file error = %v", err)
+	}
+
+	// Data moved to another file name doesn't decrypt
+	moved := filepath.Join(home, "other.json")
+	if err := os.Rename(path+".enc", moved+".enc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&encryptedStore{passphrase: "right"}).Load(moved); err == nil {
+		t.Error("Load() of a renamed file should fail")
+	}
+}
+
+func TestLoadOrCreateKeyFile(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "keys", "store.key")
+
+	key, err := loadOrCreateKeyFile(keyPath)
+	if err != nil {
+		t.Fatalf("loadOrCreateKeyFile() error = %v", err)
+	}
+	if len(key) != 32 {
+		t.Errorf("expected a 32 byte key, got %d", len(key))
+	}
+	if info, _ := os.Stat(keyPath); info.Mode().Perm() != 0600 {
+		t.Errorf("expected key file permissions 0600, got %o", info.Mode().Perm())
+	}
+
+	again, err := loadOrCreateKeyFile(keyPath)
+	if err != nil {
+		t.Fatalf("loadOrCreateKeyFile() error = %v", err)
+	}
+	if !bytes.Equal(key, again) {
+		t.Error("existing key file should be reused")
+	}
+
+	if err := os.WriteFile(keyPath, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadOrCreateKeyFile(keyPath); err == nil {
+		t.Error("expected error for an invalid key file")
+	}
+}
+
+func TestLoadOrCreateKeyFile_Concurrent(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "store.key")
+
+	// Every process racing to create the key file must end up with the same, complete key
+	const workers = 16
+	keys := make([][]byte, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			keys[i], errs[i] = loadOrCreateKeyFile(keyPath)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("loadOrCreateKeyFile() error = %v", errs[i])
+		}
+		if !bytes.Equal(keys[i], keys[0]) {
+			t.Fatal("concurrent callers got different keys")
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(keyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the key file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestCachedCredentials_EncryptedStore(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AWSC_STORE_PASSPHRASE", "hunter2")
+	viper.Reset()
+	defer viper.Reset()
+
+	// Credentials cached before switching stores are replaced by the encrypted copy
+	creds := &CachedCredentials{
+		AccessKeyID:     "AKIATEST",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(time.Hour),
+	}
+	if err := SaveCachedCredentials("123456789012", "TestRole", creds); err != nil {
+		t.Fatalf("SaveCachedCredentials failed: %v", err)
+	}
+	cachePath := filepath.Join(tempDir, ".awsc", "cache", "credentials", "123456789012-TestRole.json")
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("plaintext cache file was not created: %v", err)
+	}
+
+	viper.Set("credential_store.type", "encrypted")
+	if err := SaveCachedCredentials("123456789012", "TestRole", creds); err != nil {
+		t.Fatalf("SaveCachedCredentials failed: %v", err)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("plaintext cache file should be removed, stat error = %v", err)
+	}
+
+	loaded, err := LoadCachedCredentials("123456789012", "TestRole")
+	if err != nil {
+		t.Fatalf("LoadCachedCredentials failed: %v", err)
+	}
+	if loaded.SecretAccessKey != "secret" {
+		t.Errorf("Expected secret, got %s", loaded.SecretAccessKey)
+	}
+
+	if err := DeleteCachedCredentials("123456789012", "TestRole"); err != nil {
+		t.Fatalf("DeleteCachedCredentials failed: %v", err)
+	}
+	if _, err := os.Stat(cachePath + ".enc"); !os.IsNotExist(err) {
+		t.Errorf("encrypted cache file should be removed, stat error = %v", err)
+	}
+}
+
+func TestGetProfileType_EncryptedStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		profileType string
+		store       string
+		want        string
+	}{
+		{"default plaintext", "", "", ProfileTypeStatic},
+		{"static plaintext", ProfileTypeStatic, "plaintext", ProfileTypeStatic},
+		{"default encrypted", "", "encrypted", ProfileTypeCredentialProcess},
+		{"static encrypted", ProfileTypeStatic, "encrypted", ProfileTypeCredentialProcess},
+		{"credential_process encrypted", ProfileTypeCredentialProcess, "encrypted", ProfileTypeCredentialProcess},
+		{"sso plaintext", ProfileTypeSSO, "plaintext", ProfileTypeSSO},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			if tt.profileType != "" {
+				viper.Set("profile_type", tt.profileType)
+			}
+			if tt.store != "" {
+				viper.Set("credential_store.type", tt.store)
+			}
+
+			if got := GetProfileType(); got != tt.want {
+				t.Errorf("GetProfileType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteChainProfile_EncryptedStore(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("credential_store.type", "encrypted")
+
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	creds := &types.RoleCredentials{
+		AccessKeyId:     aws.String("AKIACHAIN"),
+		SecretAccessKey: aws.String("chain-secret"),
+		SessionToken:    aws.String("chain-token"),
+		Expiration:      expiration.UnixMilli(),
+	}
+
+	profileName, err := WriteChainProfile("prod-admin", "production", "123456789012", "admin", creds)
+	if err != nil {
+		t.Fatalf("WriteChainProfile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, ".aws", "config"))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	content := string(data)
+
+	if strings.Contains(content, "chain-secret") || strings.Contains(content, "aws_access_key_id") {
+		t.Error("encrypted store profiles must not contain credentials")
+	}
+	if !strings.Contains(content, "credential-process --chain prod-admin") {
+		t.Errorf("expected credential_process for the chain, got:\n%s", content)
+	}
+
+	metadata, err := GetProfileMetadata(profileName)
+	if err != nil {
+		t.Fatalf("GetProfileMetadata failed: %v", err)
+	}
+	if metadata.Chain != "prod-admin" || metadata.AccountID != "123456789012" || metadata.RoleName != "admin" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+	if !metadata.Expiration.Equal(expiration) {
+		t.Errorf("Expiration = %v, want %v", metadata.Expiration, expiration)
+	}
+}
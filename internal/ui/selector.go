@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	awscconfig "github.com/blontic/awsc/internal/config"
 	tea "github.com/charmbracelet/bubbletea"
@@ -28,9 +29,10 @@ type SelectorModel struct {
 }
 
 type AWSContext struct {
-	Account string
-	Role    string
-	Region  string
+	Account    string
+	Role       string
+	Region     string
+	Expiration time.Time // Zero if the profile refreshes its own credentials
 }
 
 func NewSelector(title string, choices []string) SelectorModel {
@@ -140,6 +142,10 @@ func (m SelectorModel) View() string {
 			valueStyle.Render(m.awsContext.Role),
 			valueStyle.Render(m.awsContext.Region))
 
+		if !m.awsContext.Expiration.IsZero() {
+			headerText += " | " + formatExpiration(m.awsContext.Expiration)
+		}
+
 		s.WriteString(headerText)
 		s.WriteString("\n\n")
 	}
@@ -200,10 +206,12 @@ func (m SelectorModel) Selected() int {
 	return m.selected
 }
 
+// RunSelector lets the user pick one of choices. Like every prompt, the selector renders on
+// stderr so it doesn't end up in a command's redirected stdout (e.g. awsc exec).
 func RunSelector(title string, choices []string) (int, error) {
 	// Try interactive mode first
 	model := NewSelector(title, choices)
-	p := tea.NewProgram(model)
+	p := tea.NewProgram(model, tea.WithOutput(os.Stderr))
 
 	finalModel, err := p.Run()
 	if err != nil {
@@ -221,7 +229,7 @@ func RunSelector(title string, choices []string) (int, error) {
 func RunSelectorWithSelectability(title string, choices []string, selectable []bool) (int, error) {
 	// Try interactive mode first
 	model := NewSelectorWithSelectability(title, choices, selectable)
-	p := tea.NewProgram(model)
+	p := tea.NewProgram(model, tea.WithOutput(os.Stderr))
 
 	finalModel, err := p.Run()
 	if err != nil {
@@ -237,12 +245,12 @@ func RunSelectorWithSelectability(title string, choices []string, selectable []b
 }
 
 func runSimpleSelector(title string, choices []string) (int, error) {
-	fmt.Println(title)
+	fmt.Fprintln(os.Stderr, title)
 	for i, choice := range choices {
-		fmt.Printf("%d. %s\n", i+1, choice)
+		fmt.Fprintf(os.Stderr, "%d. %s\n", i+1, choice)
 	}
 
-	fmt.Print("Select (number): ")
+	fmt.Fprint(os.Stderr, "Select (number): ")
 	var choice int
 	if _, err := fmt.Scanln(&choice); err != nil {
 		return -1, err
@@ -256,8 +264,8 @@ func runSimpleSelector(title string, choices []string) (int, error) {
 }
 
 func runSimpleSelectorWithSelectability(title string, choices []string, selectable []bool) (int, error) {
-	fmt.Println(title)
-	fmt.Println("(Filtering not available in non-interactive mode)")
+	fmt.Fprintln(os.Stderr, title)
+	fmt.Fprintln(os.Stderr, "(Filtering not available in non-interactive mode)")
 	selectableChoices := make([]string, 0)
 	indexMap := make([]int, 0)
 
@@ -266,15 +274,15 @@ func runSimpleSelectorWithSelectability(title string, choices []string, selectab
 			selectableChoices = append(selectableChoices, choice)
 			indexMap = append(indexMap, i)
 		} else {
-			fmt.Printf("   %s (unavailable)\n", choice)
+			fmt.Fprintf(os.Stderr, "   %s (unavailable)\n", choice)
 		}
 	}
 
 	for i, choice := range selectableChoices {
-		fmt.Printf("%d. %s\n", i+1, choice)
+		fmt.Fprintf(os.Stderr, "%d. %s\n", i+1, choice)
 	}
 
-	fmt.Print("Select (number): ")
+	fmt.Fprint(os.Stderr, "Select (number): ")
 	var choice int
 	if _, err := fmt.Scanln(&choice); err != nil {
 		return -1, err
@@ -348,8 +356,24 @@ func getAWSContext() *AWSContext {
 	}
 
 	return &AWSContext{
-		Account: accountName,
-		Role:    roleName,
-		Region:  region,
+		Account:    accountName,
+		Role:       roleName,
+		Region:     region,
+		Expiration: awscconfig.GetCredentialExpiration(),
+	}
+}
+
+// formatExpiration renders the time left on the credentials, in red once inside the warning margin
+func formatExpiration(expiration time.Time) string {
+	remaining := time.Until(expiration).Round(time.Minute)
+
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
+	if remaining <= awscconfig.GetExpiryWarningMargin() {
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+	}
+
+	if remaining <= 0 {
+		return "Expires: " + style.Render("expired")
 	}
+	return "Expires: " + style.Render(strings.TrimSuffix(remaining.String(), "0s"))
 }
@@ -1,11 +1,12 @@
 package ui
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/spf13/viper"
 )
 
@@ -320,7 +321,10 @@ func TestGetAWSContext_NoEnvVar(t *testing.T) {
 	}()
 
 	// Create a session file for current PPID
-	ppid := os.Getppid()
+	key, err := awscconfig.CurrentSessionKey()
+	if err != nil {
+		t.Fatalf("CurrentSessionKey failed: %v", err)
+	}
 	sessionsDir := filepath.Join(tempDir, ".awsc", "sessions")
 	if err := os.MkdirAll(sessionsDir, 0700); err != nil {
 		t.Fatalf("Failed to create sessions directory: %v", err)
@@ -332,7 +336,7 @@ func TestGetAWSContext_NoEnvVar(t *testing.T) {
   "account_name": "ppid-account",
   "role_name": "PPIDRole"
 }`
-	sessionPath := filepath.Join(sessionsDir, fmt.Sprintf("session-%d.json", ppid))
+	sessionPath := filepath.Join(sessionsDir, "session-"+key.ID+".json")
 	if err := os.WriteFile(sessionPath, []byte(sessionContent), 0600); err != nil {
 		t.Fatalf("Failed to write session file: %v", err)
 	}
@@ -390,3 +394,36 @@ func TestGetAWSContext_NoSession(t *testing.T) {
 		t.Error("Expected nil context when no session exists")
 	}
 }
+
+func TestSelectorModel_ViewWithExpiration(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	tests := []struct {
+		name       string
+		expiration time.Time
+		expected   string
+	}{
+		{"no expiration", time.Time{}, ""},
+		{"time remaining", time.Now().Add(2*time.Hour + 10*time.Second), "2h0m"},
+		{"expired", time.Now().Add(-time.Minute), "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewSelector("Test", []string{"A"})
+			model.awsContext = &AWSContext{Account: "acct", Role: "role", Region: "us-east-1", Expiration: tt.expiration}
+
+			view := model.View()
+			if tt.expected == "" {
+				if contains(view, "Expires:") {
+					t.Error("View should not show expiration when unknown")
+				}
+				return
+			}
+			if !contains(view, "Expires:") || !contains(view, tt.expected) {
+				t.Errorf("View should contain expiration %q:\n%s", tt.expected, view)
+			}
+		})
+	}
+}